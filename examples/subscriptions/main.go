@@ -108,6 +108,8 @@ func subscribeToTaskOutput(client *mythic.Client) *types.Subscription {
 			return nil
 		},
 		BufferSize: 100,
+		// Optional: Re-dial and re-subscribe if the connection drops
+		AutoReconnect: true,
 		// Optional: Add filters
 		Filter: map[string]interface{}{
 			// "callback_id": 42,  // Only events from specific callback
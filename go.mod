@@ -6,6 +6,7 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/hasura/go-graphql-client v0.10.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/time v0.5.0
 )
 
 require (
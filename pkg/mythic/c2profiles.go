@@ -8,6 +8,33 @@ import (
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
+// c2ProfileQueryFields is the GraphQL selection shared by C2 profile queries.
+type c2ProfileQueryFields struct {
+	ID               int    `graphql:"id"`
+	Name             string `graphql:"name"`
+	Description      string `graphql:"description"`
+	CreationTime     string `graphql:"creation_time"`
+	Running          bool   `graphql:"running"`
+	ContainerRunning bool   `graphql:"container_running"`
+	Deleted          bool   `graphql:"deleted"`
+	IsP2P            bool   `graphql:"is_p2p"`
+}
+
+// toC2Profile converts the query result into a types.C2Profile.
+func (p c2ProfileQueryFields) toC2Profile() *types.C2Profile {
+	creationTime, _ := parseTime(p.CreationTime) //nolint:errcheck // Timestamp parse errors not critical
+	return &types.C2Profile{
+		ID:               p.ID,
+		Name:             p.Name,
+		Description:      p.Description,
+		CreationTime:     creationTime,
+		Running:          p.Running,
+		ContainerRunning: p.ContainerRunning,
+		Deleted:          p.Deleted,
+		IsP2P:            p.IsP2P,
+	}
+}
+
 // GetC2Profiles retrieves all C2 profiles.
 func (c *Client) GetC2Profiles(ctx context.Context) ([]*types.C2Profile, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -15,15 +42,7 @@ func (c *Client) GetC2Profiles(ctx context.Context) ([]*types.C2Profile, error)
 	}
 
 	var query struct {
-		C2Profile []struct {
-			ID           int    `graphql:"id"`
-			Name         string `graphql:"name"`
-			Description  string `graphql:"description"`
-			CreationTime string `graphql:"creation_time"`
-			Running      bool   `graphql:"running"`
-			Deleted      bool   `graphql:"deleted"`
-			IsP2P        bool   `graphql:"is_p2p"`
-		} `graphql:"c2profile(where: {deleted: {_eq: false}}, order_by: {name: asc})"`
+		C2Profile []c2ProfileQueryFields `graphql:"c2profile(where: {deleted: {_eq: false}}, order_by: {name: asc})"`
 	}
 
 	err := c.executeQuery(ctx, &query, nil)
@@ -33,16 +52,7 @@ func (c *Client) GetC2Profiles(ctx context.Context) ([]*types.C2Profile, error)
 
 	profiles := make([]*types.C2Profile, len(query.C2Profile))
 	for i, p := range query.C2Profile {
-		creationTime, _ := parseTime(p.CreationTime) //nolint:errcheck // Timestamp parse errors not critical
-		profiles[i] = &types.C2Profile{
-			ID:           p.ID,
-			Name:         p.Name,
-			Description:  p.Description,
-			CreationTime: creationTime,
-			Running:      p.Running,
-			Deleted:      p.Deleted,
-			IsP2P:        p.IsP2P,
-		}
+		profiles[i] = p.toC2Profile()
 	}
 
 	return profiles, nil
@@ -59,15 +69,7 @@ func (c *Client) GetC2ProfileByID(ctx context.Context, profileID int) (*types.C2
 	}
 
 	var query struct {
-		C2Profile []struct {
-			ID           int    `graphql:"id"`
-			Name         string `graphql:"name"`
-			Description  string `graphql:"description"`
-			CreationTime string `graphql:"creation_time"`
-			Running      bool   `graphql:"running"`
-			Deleted      bool   `graphql:"deleted"`
-			IsP2P        bool   `graphql:"is_p2p"`
-		} `graphql:"c2profile(where: {id: {_eq: $profile_id}})"`
+		C2Profile []c2ProfileQueryFields `graphql:"c2profile(where: {id: {_eq: $profile_id}})"`
 	}
 
 	variables := map[string]interface{}{
@@ -83,17 +85,38 @@ func (c *Client) GetC2ProfileByID(ctx context.Context, profileID int) (*types.C2
 		return nil, WrapError("GetC2ProfileByID", ErrNotFound, "C2 profile not found")
 	}
 
-	p := query.C2Profile[0]
-	creationTime, _ := parseTime(p.CreationTime) //nolint:errcheck // Timestamp parse errors not critical
-	return &types.C2Profile{
-		ID:           p.ID,
-		Name:         p.Name,
-		Description:  p.Description,
-		CreationTime: creationTime,
-		Running:      p.Running,
-		Deleted:      p.Deleted,
-		IsP2P:        p.IsP2P,
-	}, nil
+	return query.C2Profile[0].toC2Profile(), nil
+}
+
+// GetC2ProfileByName retrieves a C2 profile by its name (e.g., "http").
+// Deleted profiles are not matched.
+func (c *Client) GetC2ProfileByName(ctx context.Context, name string) (*types.C2Profile, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, WrapError("GetC2ProfileByName", ErrInvalidInput, "profile name is required")
+	}
+
+	var query struct {
+		C2Profile []c2ProfileQueryFields `graphql:"c2profile(where: {name: {_eq: $name}, deleted: {_eq: false}}, limit: 1)"`
+	}
+
+	variables := map[string]interface{}{
+		"name": name,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetC2ProfileByName", err, "failed to query C2 profile")
+	}
+
+	if len(query.C2Profile) == 0 {
+		return nil, WrapError("GetC2ProfileByName", ErrNotFound, fmt.Sprintf("C2 profile %q not found", name))
+	}
+
+	return query.C2Profile[0].toC2Profile(), nil
 }
 
 // CreateC2Instance creates a new C2 profile instance.
@@ -396,6 +419,11 @@ func (c *Client) C2GetIOC(ctx context.Context, profileName string) (*types.C2IOC
 // GetC2ProfileParameters retrieves all configuration parameters for a specific C2 profile.
 // These define what options are available when configuring this C2 profile for a payload
 // (e.g., callback_host, callback_port, callback_interval, etc.).
+//
+// Parameters are selected as a list under the profile, the same way payload
+// type commands are. default_value and choices are decoded as raw JSON because
+// dictionary and array parameters (such as the http profile's headers) carry
+// JSON values rather than plain strings.
 func (c *Client) GetC2ProfileParameters(ctx context.Context, profileID int) ([]*types.C2ProfileParameter, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
@@ -406,21 +434,23 @@ func (c *Client) GetC2ProfileParameters(ctx context.Context, profileID int) ([]*
 	}
 
 	var query struct {
-		C2ProfileParameters []struct {
-			ID            int             `graphql:"id"`
-			C2ProfileID   int             `graphql:"c2_profile_id"`
-			Name          string          `graphql:"name"`
-			Description   string          `graphql:"description"`
-			DefaultValue  string          `graphql:"default_value"`
-			ParameterType string          `graphql:"parameter_type"`
-			Required      bool            `graphql:"required"`
-			Randomize     bool            `graphql:"randomize"`
-			FormatString  string          `graphql:"format_string"`
-			VerifierRegex string          `graphql:"verifier_regex"`
-			IsCryptoType  bool            `graphql:"crypto_type"`
-			Deleted       bool            `graphql:"deleted"`
-			Choices       json.RawMessage `graphql:"choices"`
-		} `graphql:"c2profileparameters(where: {c2_profile_id: {_eq: $profile_id}, deleted: {_eq: false}}, order_by: {name: asc})"`
+		C2Profile *struct {
+			ID                  int `graphql:"id"`
+			C2ProfileParameters []struct {
+				ID            int             `graphql:"id"`
+				Name          string          `graphql:"name"`
+				Description   string          `graphql:"description"`
+				DefaultValue  json.RawMessage `graphql:"default_value"`
+				ParameterType string          `graphql:"parameter_type"`
+				Required      bool            `graphql:"required"`
+				Randomize     bool            `graphql:"randomize"`
+				FormatString  string          `graphql:"format_string"`
+				VerifierRegex string          `graphql:"verifier_regex"`
+				IsCryptoType  bool            `graphql:"crypto_type"`
+				Deleted       bool            `graphql:"deleted"`
+				Choices       json.RawMessage `graphql:"choices"`
+			} `graphql:"c2profileparameters(where: {deleted: {_eq: false}}, order_by: {name: asc})"`
+		} `graphql:"c2profile_by_pk(id: $profile_id)"`
 	}
 
 	variables := map[string]interface{}{
@@ -432,14 +462,18 @@ func (c *Client) GetC2ProfileParameters(ctx context.Context, profileID int) ([]*
 		return nil, WrapError("GetC2ProfileParameters", err, "failed to query C2 profile parameters")
 	}
 
-	parameters := make([]*types.C2ProfileParameter, len(query.C2ProfileParameters))
-	for i, p := range query.C2ProfileParameters {
+	if query.C2Profile == nil {
+		return nil, WrapError("GetC2ProfileParameters", ErrNotFound, fmt.Sprintf("C2 profile %d not found", profileID))
+	}
+
+	parameters := make([]*types.C2ProfileParameter, len(query.C2Profile.C2ProfileParameters))
+	for i, p := range query.C2Profile.C2ProfileParameters {
 		parameters[i] = &types.C2ProfileParameter{
 			ID:            p.ID,
-			C2ProfileID:   p.C2ProfileID,
+			C2ProfileID:   query.C2Profile.ID,
 			Name:          p.Name,
 			Description:   p.Description,
-			DefaultValue:  p.DefaultValue,
+			DefaultValue:  rawJSONText(p.DefaultValue),
 			ParameterType: p.ParameterType,
 			Required:      p.Required,
 			Randomize:     p.Randomize,
@@ -453,3 +487,14 @@ func (c *Client) GetC2ProfileParameters(ctx context.Context, profileID int) ([]*
 
 	return parameters, nil
 }
+
+// GetC2ProfileParametersByName retrieves the configuration parameters of the
+// C2 profile with the given name (e.g., "http").
+func (c *Client) GetC2ProfileParametersByName(ctx context.Context, name string) ([]*types.C2ProfileParameter, error) {
+	profile, err := c.GetC2ProfileByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetC2ProfileParameters(ctx, profile.ID)
+}
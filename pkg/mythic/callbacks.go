@@ -3,214 +3,448 @@ package mythic
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
-// GetAllCallbacks retrieves all callbacks (active and inactive).
-func (c *Client) GetAllCallbacks(ctx context.Context) ([]*types.Callback, error) {
+// CallbackQueryOptions filters and paginates GetCallbacks. Zero-value fields
+// are not applied.
+type CallbackQueryOptions struct {
+	// Limit is the maximum number of callbacks to return (0 for no limit)
+	Limit int
+
+	// Offset is the number of callbacks to skip
+	Offset int
+
+	// ActiveOnly restricts results to active callbacks
+	ActiveOnly bool
+
+	// Host matches the hostname case-insensitively; may contain % wildcards
+	Host string
+
+	// User matches the username case-insensitively; may contain % wildcards
+	User string
+
+	// PayloadTypeName matches the callback's payload type (e.g. "poseidon")
+	PayloadTypeName string
+
+	// IntegrityAtLeast restricts results to callbacks at or above this integrity level
+	IntegrityAtLeast types.CallbackIntegrityLevel
+
+	// LastCheckinBefore restricts results to callbacks that last checked in before this time
+	LastCheckinBefore time.Time
+}
+
+// GetCallbacks retrieves callbacks matching opts, newest first. Filtering and
+// pagination are applied server-side. A nil opts returns every callback.
+func (c *Client) GetCallbacks(ctx context.Context, opts *CallbackQueryOptions) ([]*types.Callback, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
-	var query struct {
-		Callback []struct {
-			ID              int    `graphql:"id"`
-			DisplayID       int    `graphql:"display_id"`
-			AgentCallbackID string `graphql:"agent_callback_id"`
-			InitCallback    string `graphql:"init_callback"`
-			LastCheckin     string `graphql:"last_checkin"`
-			User            string `graphql:"user"`
-			Host            string `graphql:"host"`
-			PID             int    `graphql:"pid"`
-			IP              string `graphql:"ip"`
-			ExternalIP      string `graphql:"external_ip"`
-			ProcessName     string `graphql:"process_name"`
-			Description     string `graphql:"description"`
-			Active          bool   `graphql:"active"`
-			IntegrityLevel  int    `graphql:"integrity_level"`
-			Locked          bool   `graphql:"locked"`
-			OS              string `graphql:"os"`
-			Architecture    string `graphql:"architecture"`
-			Domain          string `graphql:"domain"`
-			ExtraInfo       string `graphql:"extra_info"`
-			SleepInfo       string `graphql:"sleep_info"`
-			OperationID     int    `graphql:"operation_id"`
-			OperatorID      int    `graphql:"operator_id"`
-			Payload         struct {
-				ID          int    `graphql:"id"`
-				UUID        string `graphql:"uuid"`
-				Description string `graphql:"description"`
-				OS          string `graphql:"os"`
-				PayloadType struct {
-					ID   int    `graphql:"id"`
-					Name string `graphql:"name"`
-				} `graphql:"payloadtype"`
-			} `graphql:"payload"`
-			Operator struct {
-				ID       int    `graphql:"id"`
-				Username string `graphql:"username"`
-			} `graphql:"operator"`
-		} `graphql:"callback(order_by: {id: desc})"`
-	}
-
-	err := c.executeQuery(ctx, &query, nil)
-	if err != nil {
-		return nil, WrapError("GetAllCallbacks", err, "failed to query callbacks")
-	}
-
-	callbacks := make([]*types.Callback, len(query.Callback))
-	for i, cb := range query.Callback {
-		initCallback, _ := parseTime(cb.InitCallback) //nolint:errcheck // Timestamp parse errors not critical
-		lastCheckin, _ := parseTime(cb.LastCheckin)   //nolint:errcheck // Timestamp parse errors not critical
-
-		callbacks[i] = &types.Callback{
-			ID:              cb.ID,
-			DisplayID:       cb.DisplayID,
-			AgentCallbackID: cb.AgentCallbackID,
-			InitCallback:    initCallback,
-			LastCheckin:     lastCheckin,
-			User:            cb.User,
-			Host:            cb.Host,
-			PID:             cb.PID,
-			IP:              parseIPString(cb.IP),
-			ExternalIP:      cb.ExternalIP,
-			ProcessName:     cb.ProcessName,
-			Description:     cb.Description,
-			Active:          cb.Active,
-			IntegrityLevel:  types.CallbackIntegrityLevel(cb.IntegrityLevel),
-			Locked:          cb.Locked,
-			OS:              cb.OS,
-			Architecture:    cb.Architecture,
-			Domain:          cb.Domain,
-			ExtraInfo:       cb.ExtraInfo,
-			SleepInfo:       cb.SleepInfo,
-			OperationID:     cb.OperationID,
-			PayloadTypeID:   cb.Payload.PayloadType.ID,
-			OperatorID:      cb.OperatorID,
-			Payload: &types.CallbackPayload{
-				ID:          cb.Payload.ID,
-				UUID:        cb.Payload.UUID,
-				Description: cb.Payload.Description,
-				OS:          cb.Payload.OS,
-			},
-			PayloadType: &types.CallbackPayloadType{
-				ID:   cb.Payload.PayloadType.ID,
-				Name: cb.Payload.PayloadType.Name,
-			},
-			Operator: &types.CallbackOperator{
-				ID:       cb.Operator.ID,
-				Username: cb.Operator.Username,
+	if opts == nil {
+		opts = &CallbackQueryOptions{}
+	}
+
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return nil, WrapError("GetCallbacks", ErrInvalidInput, "limit and offset cannot be negative")
+	}
+
+	where := newBoolExp("callback")
+	if opts.ActiveOnly {
+		where.conds["active"] = map[string]interface{}{"_eq": true}
+	}
+	if opts.Host != "" {
+		where.conds["host"] = map[string]interface{}{"_ilike": opts.Host}
+	}
+	if opts.User != "" {
+		where.conds["user"] = map[string]interface{}{"_ilike": opts.User}
+	}
+	if opts.PayloadTypeName != "" {
+		where.conds["payload"] = map[string]interface{}{
+			"payloadtype": map[string]interface{}{
+				"name": map[string]interface{}{"_eq": opts.PayloadTypeName},
 			},
 		}
 	}
+	if opts.IntegrityAtLeast > 0 {
+		where.conds["integrity_level"] = map[string]interface{}{"_gte": int(opts.IntegrityAtLeast)}
+	}
+	if !opts.LastCheckinBefore.IsZero() {
+		// last_checkin is stored in UTC without a timezone
+		where.conds["last_checkin"] = map[string]interface{}{"_lt": opts.LastCheckinBefore.UTC().Format(time.RFC3339)}
+	}
+
+	callbacks, err := c.queryCallbacks(ctx, where, opts.Limit, opts.Offset)
+	if err != nil {
+		return nil, WrapError("GetCallbacks", err, "failed to query callbacks")
+	}
 
 	return callbacks, nil
 }
 
-// GetAllActiveCallbacks retrieves only currently active callbacks.
-func (c *Client) GetAllActiveCallbacks(ctx context.Context) ([]*types.Callback, error) {
+// CallbackFilter selects callbacks for GetCallbacksFiltered. Nil fields are
+// left out of the where clause; string fields match case-insensitively and
+// may contain % wildcards.
+type CallbackFilter struct {
+	// OS matches the callback's operating system
+	OS *string
+
+	// Host matches the hostname
+	Host *string
+
+	// User matches the username
+	User *string
+
+	// Domain matches the callback's domain
+	Domain *string
+
+	// IntegrityLevel restricts results to callbacks at exactly this integrity level
+	IntegrityLevel *int
+
+	// IntegrityLevelMin restricts results to callbacks at or above this integrity level
+	IntegrityLevelMin *int
+
+	// Active restricts results to active (true) or inactive (false) callbacks;
+	// it takes precedence over ActiveOnly
+	Active *bool
+
+	// ActiveOnly restricts results to active callbacks
+	ActiveOnly bool
+
+	// PayloadTypeName restricts results to callbacks from this payload type
+	// (e.g. "poseidon")
+	PayloadTypeName *string
+}
+
+// GetCallbacksFiltered retrieves callbacks matching filter, newest first, with
+// the filter applied server-side. A nil or empty filter behaves like
+// GetAllCallbacks.
+func (c *Client) GetCallbacksFiltered(ctx context.Context, filter *CallbackFilter) ([]*types.Callback, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
-	var query struct {
-		Callback []struct {
-			ID              int    `graphql:"id"`
-			DisplayID       int    `graphql:"display_id"`
-			AgentCallbackID string `graphql:"agent_callback_id"`
-			InitCallback    string `graphql:"init_callback"`
-			LastCheckin     string `graphql:"last_checkin"`
-			User            string `graphql:"user"`
-			Host            string `graphql:"host"`
-			PID             int    `graphql:"pid"`
-			IP              string `graphql:"ip"`
-			ExternalIP      string `graphql:"external_ip"`
-			ProcessName     string `graphql:"process_name"`
-			Description     string `graphql:"description"`
-			Active          bool   `graphql:"active"`
-			IntegrityLevel  int    `graphql:"integrity_level"`
-			Locked          bool   `graphql:"locked"`
-			OS              string `graphql:"os"`
-			Architecture    string `graphql:"architecture"`
-			Domain          string `graphql:"domain"`
-			ExtraInfo       string `graphql:"extra_info"`
-			SleepInfo       string `graphql:"sleep_info"`
-			OperationID     int    `graphql:"operation_id"`
-			OperatorID      int    `graphql:"operator_id"`
-			Payload         struct {
-				ID          int    `graphql:"id"`
-				UUID        string `graphql:"uuid"`
-				Description string `graphql:"description"`
-				OS          string `graphql:"os"`
-				PayloadType struct {
-					ID   int    `graphql:"id"`
-					Name string `graphql:"name"`
-				} `graphql:"payloadtype"`
-			} `graphql:"payload"`
-			Operator struct {
-				ID       int    `graphql:"id"`
-				Username string `graphql:"username"`
-			} `graphql:"operator"`
-		} `graphql:"callback(where: {active: {_eq: true}}, order_by: {id: desc})"`
-	}
-
-	err := c.executeQuery(ctx, &query, nil)
-	if err != nil {
-		return nil, WrapError("GetAllActiveCallbacks", err, "failed to query active callbacks")
-	}
-
-	callbacks := make([]*types.Callback, len(query.Callback))
-	for i, cb := range query.Callback {
-		initCallback, _ := parseTime(cb.InitCallback) //nolint:errcheck // Timestamp parse errors not critical
-		lastCheckin, _ := parseTime(cb.LastCheckin)   //nolint:errcheck // Timestamp parse errors not critical
-
-		callbacks[i] = &types.Callback{
-			ID:              cb.ID,
-			DisplayID:       cb.DisplayID,
-			AgentCallbackID: cb.AgentCallbackID,
-			InitCallback:    initCallback,
-			LastCheckin:     lastCheckin,
-			User:            cb.User,
-			Host:            cb.Host,
-			PID:             cb.PID,
-			IP:              parseIPString(cb.IP),
-			ExternalIP:      cb.ExternalIP,
-			ProcessName:     cb.ProcessName,
-			Description:     cb.Description,
-			Active:          cb.Active,
-			IntegrityLevel:  types.CallbackIntegrityLevel(cb.IntegrityLevel),
-			Locked:          cb.Locked,
-			OS:              cb.OS,
-			Architecture:    cb.Architecture,
-			Domain:          cb.Domain,
-			ExtraInfo:       cb.ExtraInfo,
-			SleepInfo:       cb.SleepInfo,
-			OperationID:     cb.OperationID,
-			PayloadTypeID:   cb.Payload.PayloadType.ID,
-			OperatorID:      cb.OperatorID,
-			Payload: &types.CallbackPayload{
-				ID:          cb.Payload.ID,
-				UUID:        cb.Payload.UUID,
-				Description: cb.Payload.Description,
-				OS:          cb.Payload.OS,
-			},
-			PayloadType: &types.CallbackPayloadType{
-				ID:   cb.Payload.PayloadType.ID,
-				Name: cb.Payload.PayloadType.Name,
-			},
-			Operator: &types.CallbackOperator{
-				ID:       cb.Operator.ID,
-				Username: cb.Operator.Username,
+	if filter == nil {
+		filter = &CallbackFilter{}
+	}
+
+	where := newBoolExp("callback")
+	if filter.Active != nil {
+		where.conds["active"] = map[string]interface{}{"_eq": *filter.Active}
+	} else if filter.ActiveOnly {
+		where.conds["active"] = map[string]interface{}{"_eq": true}
+	}
+	for column, value := range map[string]*string{
+		"os":     filter.OS,
+		"host":   filter.Host,
+		"user":   filter.User,
+		"domain": filter.Domain,
+	} {
+		if value != nil {
+			where.conds[column] = map[string]interface{}{"_ilike": *value}
+		}
+	}
+	integrity := map[string]interface{}{}
+	if filter.IntegrityLevel != nil {
+		integrity["_eq"] = *filter.IntegrityLevel
+	}
+	if filter.IntegrityLevelMin != nil {
+		integrity["_gte"] = *filter.IntegrityLevelMin
+	}
+	if len(integrity) > 0 {
+		where.conds["integrity_level"] = integrity
+	}
+	if filter.PayloadTypeName != nil {
+		where.conds["payload"] = map[string]interface{}{
+			"payloadtype": map[string]interface{}{
+				"name": map[string]interface{}{"_eq": *filter.PayloadTypeName},
 			},
 		}
 	}
 
+	callbacks, err := c.queryCallbacks(ctx, where, 0, 0)
+	if err != nil {
+		return nil, WrapError("GetCallbacksFiltered", err, "failed to query callbacks")
+	}
+
+	return callbacks, nil
+}
+
+// callbackWaitPollInterval is how often WaitForCallback re-checks for a
+// matching callback between subscription updates.
+const callbackWaitPollInterval = 5 * time.Second
+
+// WaitForCallback blocks until an active callback matching filter exists and
+// returns it, or fails with ErrTimeout after timeoutSeconds (default 300).
+// A nil filter matches any active callback; filter.Active and
+// filter.ActiveOnly are ignored.
+//
+// Callbacks that already match when WaitForCallback is called are returned
+// immediately, newest first, so narrow the filter (for example by Host or
+// PayloadTypeName) when waiting for a specific agent. New callbacks are
+// picked up through a subscription to the current operation's callbacks,
+// with a periodic re-check in case the WebSocket is unavailable.
+//
+// Example:
+//
+//	payloadType := "poseidon"
+//	callback, err := client.WaitForCallback(ctx, 120, &mythic.CallbackFilter{
+//	    PayloadTypeName: &payloadType,
+//	})
+func (c *Client) WaitForCallback(ctx context.Context, timeoutSeconds int, filter *CallbackFilter) (*types.Callback, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 300 // Default 5 minutes
+	}
+
+	active := true
+	match := CallbackFilter{}
+	if filter != nil {
+		match = *filter
+	}
+	match.Active = &active
+
+	find := func() (*types.Callback, error) {
+		callbacks, err := c.GetCallbacksFiltered(ctx, &match)
+		if err != nil {
+			return nil, WrapError("WaitForCallback", err, "failed to check for callbacks")
+		}
+		if len(callbacks) == 0 {
+			return nil, nil
+		}
+		return callbacks[0], nil
+	}
+
+	deadline := time.After(time.Duration(timeoutSeconds) * time.Second)
+
+	if callback, err := find(); callback != nil || err != nil {
+		return callback, err
+	}
+
+	// Any change to the newest active callback triggers a re-check
+	updates := make(chan struct{}, 1)
+	if operationID := c.GetCurrentOperation(); operationID != nil {
+		type callbackWaitSubscription struct {
+			Callback []struct {
+				ID int `graphql:"id"`
+			} `graphql:"callback(where: {operation_id: {_eq: $operation_id}, active: {_eq: true}}, order_by: {id: desc}, limit: 1)"`
+		}
+
+		subscriptionClient, _ := c.getSubscriptionClient()
+		subID, err := subscriptionClient.Subscribe(&callbackWaitSubscription{}, map[string]interface{}{
+			"operation_id": *operationID,
+		}, func(dataValue []byte, errValue error) error {
+			if errValue == nil {
+				select {
+				case updates <- struct{}{}:
+				default:
+				}
+			}
+			return nil
+		})
+		if err == nil {
+			defer subscriptionClient.Unsubscribe(subID) //nolint:errcheck // Best effort cleanup
+		}
+	}
+
+	ticker := time.NewTicker(callbackWaitPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-deadline:
+			return nil, WrapError("WaitForCallback", ErrTimeout, fmt.Sprintf("no matching callback within %ds", timeoutSeconds))
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-updates:
+		case <-ticker.C:
+		}
+
+		if callback, err := find(); callback != nil || err != nil {
+			return callback, err
+		}
+	}
+}
+
+// queryCallbacks runs a callback query with the given where clause, newest
+// first. A limit of 0 returns every match.
+func (c *Client) queryCallbacks(ctx context.Context, where boolExp, limit, offset int) ([]*types.Callback, error) {
+	variables := map[string]interface{}{
+		"where":  where,
+		"offset": offset,
+	}
+
+	// Hasura has no "unlimited" value for limit, so it is only included when set
+	var rows []callbackQueryFields
+	if limit > 0 {
+		var query struct {
+			Callback []callbackQueryFields `graphql:"callback(where: $where, order_by: {id: desc}, limit: $limit, offset: $offset)"`
+		}
+		variables["limit"] = limit
+
+		if err := c.executeQuery(ctx, &query, variables); err != nil {
+			return nil, err
+		}
+		rows = query.Callback
+	} else {
+		var query struct {
+			Callback []callbackQueryFields `graphql:"callback(where: $where, order_by: {id: desc}, offset: $offset)"`
+		}
+
+		if err := c.executeQuery(ctx, &query, variables); err != nil {
+			return nil, err
+		}
+		rows = query.Callback
+	}
+
+	callbacks := make([]*types.Callback, len(rows))
+	for i, cb := range rows {
+		callbacks[i] = cb.toCallback()
+	}
+
 	return callbacks, nil
 }
 
+// DefaultCallbackPageSize is the page size GetCallbacksPaginated uses when
+// CallbackPageOptions.Limit is zero.
+const DefaultCallbackPageSize = 100
+
+// allCallbacksPageSize is the page size GetAllCallbacks walks the callback
+// table with.
+const allCallbacksPageSize = 1000
+
+// CallbackPageOptions selects one page of callbacks for GetCallbacksPaginated.
+type CallbackPageOptions struct {
+	// Limit is the maximum number of callbacks in the page (0 for DefaultCallbackPageSize)
+	Limit int
+
+	// AfterID is the cursor returned as NextCursor by the previous page (0 for the first page)
+	AfterID int
+
+	// ActiveOnly restricts results to active callbacks
+	ActiveOnly bool
+}
+
+// CallbackPage is one page of callbacks returned by GetCallbacksPaginated.
+type CallbackPage struct {
+	// Callbacks holds the page, newest first
+	Callbacks []*types.Callback
+
+	// Count is the number of callbacks in this page
+	Count int
+
+	// NextCursor is the AfterID to pass for the next page; 0 when HasMore is false
+	NextCursor int
+
+	// HasMore reports whether older callbacks remain after this page
+	HasMore bool
+}
+
+// GetCallbacksPaginated retrieves one page of callbacks, newest first. Pages
+// are keyed on the callback's database ID, so callbacks registered while
+// paging do not shift later pages the way an offset would.
+//
+// Example:
+//
+//	opts := &mythic.CallbackPageOptions{Limit: 200}
+//	for {
+//	    page, err := client.GetCallbacksPaginated(ctx, opts)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    process(page.Callbacks)
+//	    if !page.HasMore {
+//	        break
+//	    }
+//	    opts.AfterID = page.NextCursor
+//	}
+func (c *Client) GetCallbacksPaginated(ctx context.Context, opts *CallbackPageOptions) (*CallbackPage, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = &CallbackPageOptions{}
+	}
+
+	if opts.Limit < 0 || opts.AfterID < 0 {
+		return nil, WrapError("GetCallbacksPaginated", ErrInvalidInput, "limit and cursor cannot be negative")
+	}
+
+	limit := opts.Limit
+	if limit == 0 {
+		limit = DefaultCallbackPageSize
+	}
+
+	where := newBoolExp("callback")
+	if opts.ActiveOnly {
+		where.conds["active"] = map[string]interface{}{"_eq": true}
+	}
+	if opts.AfterID > 0 {
+		where.conds["id"] = map[string]interface{}{"_lt": opts.AfterID}
+	}
+
+	// One extra row tells us whether another page exists without a count query
+	callbacks, err := c.queryCallbacks(ctx, where, limit+1, 0)
+	if err != nil {
+		return nil, WrapError("GetCallbacksPaginated", err, "failed to query callbacks")
+	}
+
+	page := &CallbackPage{Callbacks: callbacks}
+	if len(callbacks) > limit {
+		page.Callbacks = callbacks[:limit]
+		page.HasMore = true
+		page.NextCursor = page.Callbacks[limit-1].ID
+	}
+	page.Count = len(page.Callbacks)
+
+	return page, nil
+}
+
+// GetAllCallbacks retrieves all callbacks (active and inactive), newest first.
+// Callbacks are fetched page by page through GetCallbacksPaginated.
+func (c *Client) GetAllCallbacks(ctx context.Context) ([]*types.Callback, error) {
+	callbacks := make([]*types.Callback, 0)
+	opts := &CallbackPageOptions{Limit: allCallbacksPageSize}
+	for {
+		page, err := c.GetCallbacksPaginated(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		callbacks = append(callbacks, page.Callbacks...)
+		if !page.HasMore {
+			return callbacks, nil
+		}
+		opts.AfterID = page.NextCursor
+	}
+}
+
+// GetAllActiveCallbacks retrieves only currently active callbacks.
+func (c *Client) GetAllActiveCallbacks(ctx context.Context) ([]*types.Callback, error) {
+	return c.GetCallbacks(ctx, &CallbackQueryOptions{ActiveOnly: true})
+}
+
+// GetStaleCallbacks retrieves active callbacks that have not checked in for
+// longer than olderThan, newest first. The cutoff is applied server-side.
+func (c *Client) GetStaleCallbacks(ctx context.Context, olderThan time.Duration) ([]*types.Callback, error) {
+	if olderThan <= 0 {
+		return nil, WrapError("GetStaleCallbacks", ErrInvalidInput, "duration must be positive")
+	}
+
+	return c.GetCallbacks(ctx, &CallbackQueryOptions{
+		ActiveOnly:        true,
+		LastCheckinBefore: time.Now().Add(-olderThan),
+	})
+}
+
 // GetCallbackByID retrieves a specific callback by its display ID.
 func (c *Client) GetCallbackByID(ctx context.Context, displayID int) (*types.Callback, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -218,44 +452,7 @@ func (c *Client) GetCallbackByID(ctx context.Context, displayID int) (*types.Cal
 	}
 
 	var query struct {
-		Callback []struct {
-			ID              int    `graphql:"id"`
-			DisplayID       int    `graphql:"display_id"`
-			AgentCallbackID string `graphql:"agent_callback_id"`
-			InitCallback    string `graphql:"init_callback"`
-			LastCheckin     string `graphql:"last_checkin"`
-			User            string `graphql:"user"`
-			Host            string `graphql:"host"`
-			PID             int    `graphql:"pid"`
-			IP              string `graphql:"ip"`
-			ExternalIP      string `graphql:"external_ip"`
-			ProcessName     string `graphql:"process_name"`
-			Description     string `graphql:"description"`
-			Active          bool   `graphql:"active"`
-			IntegrityLevel  int    `graphql:"integrity_level"`
-			Locked          bool   `graphql:"locked"`
-			OS              string `graphql:"os"`
-			Architecture    string `graphql:"architecture"`
-			Domain          string `graphql:"domain"`
-			ExtraInfo       string `graphql:"extra_info"`
-			SleepInfo       string `graphql:"sleep_info"`
-			OperationID     int    `graphql:"operation_id"`
-			OperatorID      int    `graphql:"operator_id"`
-			Payload         struct {
-				ID          int    `graphql:"id"`
-				UUID        string `graphql:"uuid"`
-				Description string `graphql:"description"`
-				OS          string `graphql:"os"`
-				PayloadType struct {
-					ID   int    `graphql:"id"`
-					Name string `graphql:"name"`
-				} `graphql:"payloadtype"`
-			} `graphql:"payload"`
-			Operator struct {
-				ID       int    `graphql:"id"`
-				Username string `graphql:"username"`
-			} `graphql:"operator"`
-		} `graphql:"callback(where: {display_id: {_eq: $displayID}}, limit: 1)"`
+		Callback []callbackQueryFields `graphql:"callback(where: {display_id: {_eq: $displayID}}, limit: 1)"`
 	}
 
 	variables := map[string]interface{}{
@@ -271,11 +468,87 @@ func (c *Client) GetCallbackByID(ctx context.Context, displayID int) (*types.Cal
 		return nil, WrapError("GetCallbackByID", ErrNotFound, fmt.Sprintf("callback with display_id %d not found", displayID))
 	}
 
-	cb := query.Callback[0]
+	return query.Callback[0].toCallback(), nil
+}
+
+// GetCallbackByAgentID retrieves a specific callback by its agent callback ID
+// (the UUID the agent uses to identify itself, as seen in subscription events).
+func (c *Client) GetCallbackByAgentID(ctx context.Context, agentCallbackID string) (*types.Callback, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if agentCallbackID == "" {
+		return nil, WrapError("GetCallbackByAgentID", ErrInvalidInput, "agent_callback_id is required")
+	}
+
+	var query struct {
+		Callback []callbackQueryFields `graphql:"callback(where: {agent_callback_id: {_eq: $agent_callback_id}}, limit: 1)"`
+	}
+
+	variables := map[string]interface{}{
+		"agent_callback_id": agentCallbackID,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetCallbackByAgentID", err, "failed to query callback")
+	}
+
+	if len(query.Callback) == 0 {
+		return nil, WrapError("GetCallbackByAgentID", ErrNotFound, fmt.Sprintf("callback with agent_callback_id %s not found", agentCallbackID))
+	}
+
+	return query.Callback[0].toCallback(), nil
+}
+
+// callbackQueryFields is the full set of callback columns selected by GraphQL
+// queries that return complete Callback objects.
+type callbackQueryFields struct {
+	ID              int    `graphql:"id"`
+	DisplayID       int    `graphql:"display_id"`
+	AgentCallbackID string `graphql:"agent_callback_id"`
+	InitCallback    string `graphql:"init_callback"`
+	LastCheckin     string `graphql:"last_checkin"`
+	User            string `graphql:"user"`
+	Host            string `graphql:"host"`
+	PID             int    `graphql:"pid"`
+	IP              string `graphql:"ip"`
+	ExternalIP      string `graphql:"external_ip"`
+	ProcessName     string `graphql:"process_name"`
+	Description     string `graphql:"description"`
+	Active          bool   `graphql:"active"`
+	IntegrityLevel  int    `graphql:"integrity_level"`
+	Locked          bool   `graphql:"locked"`
+	OS              string `graphql:"os"`
+	Architecture    string `graphql:"architecture"`
+	Domain          string `graphql:"domain"`
+	ExtraInfo       string `graphql:"extra_info"`
+	SleepInfo       string `graphql:"sleep_info"`
+	OperationID     int    `graphql:"operation_id"`
+	OperatorID      int    `graphql:"operator_id"`
+	Payload         struct {
+		ID          int    `graphql:"id"`
+		UUID        string `graphql:"uuid"`
+		Description string `graphql:"description"`
+		OS          string `graphql:"os"`
+		PayloadType struct {
+			ID   int    `graphql:"id"`
+			Name string `graphql:"name"`
+		} `graphql:"payloadtype"`
+	} `graphql:"payload"`
+	Operator struct {
+		ID       int    `graphql:"id"`
+		Username string `graphql:"username"`
+	} `graphql:"operator"`
+}
+
+// toCallback converts the queried callback columns into a Callback.
+func (cb callbackQueryFields) toCallback() *types.Callback {
 	initCallback, _ := parseTime(cb.InitCallback) //nolint:errcheck // Timestamp parse errors not critical
 	lastCheckin, _ := parseTime(cb.LastCheckin)   //nolint:errcheck // Timestamp parse errors not critical
 
-	callback := &types.Callback{
+	return &types.Callback{
 		ID:              cb.ID,
 		DisplayID:       cb.DisplayID,
 		AgentCallbackID: cb.AgentCallbackID,
@@ -314,36 +587,168 @@ func (c *Client) GetCallbackByID(ctx context.Context, displayID int) (*types.Cal
 			Username: cb.Operator.Username,
 		},
 	}
-
-	return callback, nil
 }
 
 // UpdateCallback updates properties of a callback.
+// Only the non-nil fields of req are changed. After the update the callback is
+// read back, and ErrOperationFailed is returned if a requested value did not persist.
+// Returns ErrNotFound if the callback does not exist.
+//
+// Note: This function uses the Hasura webhook endpoint directly instead of the GraphQL
+// mutation, for the same reason as IssueTask: unset fields must be omitted from the
+// request rather than sent as explicit nulls.
 func (c *Client) UpdateCallback(ctx context.Context, req *types.CallbackUpdateRequest) error {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return err
 	}
 
-	if req.CallbackDisplayID <= 0 {
+	if req == nil || req.CallbackDisplayID <= 0 {
 		return WrapError("UpdateCallback", ErrInvalidConfig, "callback display ID is required")
 	}
 
-	// Simplified: only support updating description for now
-	// Note: Multi-field updates require explicit field specification or REST webhooks
-	// due to GraphQL client library limitations with dynamic _set parameters
-	if req.Description == nil {
-		return WrapError("UpdateCallback", ErrInvalidConfig, "currently only description field updates are supported")
+	// Build request payload - only include fields that are being changed
+	input := map[string]interface{}{
+		"callback_display_id": req.CallbackDisplayID,
 	}
 
-	// Note: update_callback GraphQL mutation not available in all Mythic versions
-	// For now, skip the update and just verify the callback exists
-	// In a full implementation, this would use a REST webhook endpoint
-	_, err := c.GetCallbackByID(ctx, req.CallbackDisplayID)
+	if req.Active != nil {
+		input["active"] = *req.Active
+	}
+	if req.Locked != nil {
+		input["locked"] = *req.Locked
+	}
+	if req.Description != nil {
+		input["description"] = *req.Description
+	}
+	if req.IPs != nil {
+		input["ips"] = req.IPs
+	}
+	if req.User != nil {
+		input["user"] = *req.User
+	}
+	if req.Host != nil {
+		input["host"] = *req.Host
+	}
+	if req.OS != nil {
+		input["os"] = *req.OS
+	}
+	if req.Architecture != nil {
+		input["architecture"] = *req.Architecture
+	}
+	if req.ExtraInfo != nil {
+		input["extra_info"] = *req.ExtraInfo
+	}
+	if req.SleepInfo != nil {
+		input["sleep_info"] = *req.SleepInfo
+	}
+	if req.PID != nil {
+		input["pid"] = *req.PID
+	}
+	if req.ProcessName != nil {
+		input["process_name"] = *req.ProcessName
+	}
+	if req.IntegrityLevel != nil {
+		input["integrity_level"] = int(*req.IntegrityLevel)
+	}
+	if req.Domain != nil {
+		input["domain"] = *req.Domain
+	}
+
+	if len(input) == 1 {
+		return WrapError("UpdateCallback", ErrInvalidInput, "at least one field to update is required")
+	}
+
+	payload := map[string]interface{}{
+		"input": input,
+	}
+
+	var response struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+
+	err := c.executeRESTWebhook(ctx, "api/v1.4/update_callback_webhook", payload, &response)
+	if err != nil {
+		return WrapError("UpdateCallback", err, "failed to update callback")
+	}
+
+	if response.Status != "success" {
+		// The webhook reports a missing callback as a generic error; look it up
+		// so callers can distinguish "no such callback" from a rejected update.
+		if _, lookupErr := c.GetCallbackByID(ctx, req.CallbackDisplayID); errors.Is(lookupErr, ErrNotFound) {
+			return WrapError("UpdateCallback", ErrNotFound, fmt.Sprintf("callback %d not found", req.CallbackDisplayID))
+		}
+		return WrapError("UpdateCallback", ErrOperationFailed, fmt.Sprintf("callback update failed: %s", response.Error))
+	}
+
+	// Read the callback back and confirm the requested values were stored
+	updated, err := c.GetCallbackByID(ctx, req.CallbackDisplayID)
+	if err != nil {
+		return WrapError("UpdateCallback", err, "failed to verify callback update")
+	}
+	if field := unappliedCallbackField(req, updated); field != "" {
+		return WrapError("UpdateCallback", ErrOperationFailed, fmt.Sprintf("callback %s was not updated", field))
+	}
+
+	return nil
+}
+
+// unappliedCallbackField returns the name of the first field set in req whose
+// value does not match cb, or "" if every checked field was applied.
+// Fields Mythic may normalize (ips, os, architecture, extra_info) are not checked.
+func unappliedCallbackField(req *types.CallbackUpdateRequest, cb *types.Callback) string {
+	switch {
+	case req.Active != nil && cb.Active != *req.Active:
+		return "active"
+	case req.Locked != nil && cb.Locked != *req.Locked:
+		return "locked"
+	case req.Description != nil && cb.Description != *req.Description:
+		return "description"
+	case req.SleepInfo != nil && cb.SleepInfo != *req.SleepInfo:
+		return "sleep_info"
+	case req.User != nil && cb.User != *req.User:
+		return "user"
+	case req.Host != nil && cb.Host != *req.Host:
+		return "host"
+	case req.Domain != nil && cb.Domain != *req.Domain:
+		return "domain"
+	case req.ProcessName != nil && cb.ProcessName != *req.ProcessName:
+		return "process_name"
+	case req.PID != nil && cb.PID != *req.PID:
+		return "pid"
+	case req.IntegrityLevel != nil && cb.IntegrityLevel != *req.IntegrityLevel:
+		return "integrity_level"
+	}
+	return ""
+}
+
+// LockCallback locks a callback so that only the locking operator can task it.
+// Returns ErrNotFound if the callback does not exist.
+func (c *Client) LockCallback(ctx context.Context, displayID int) error {
+	return c.setCallbackLocked(ctx, "LockCallback", displayID, true)
+}
+
+// UnlockCallback unlocks a callback so that any operator can task it.
+// Returns ErrNotFound if the callback does not exist.
+func (c *Client) UnlockCallback(ctx context.Context, displayID int) error {
+	return c.setCallbackLocked(ctx, "UnlockCallback", displayID, false)
+}
+
+// setCallbackLocked sets the locked field of a callback.
+// UpdateCallback reports a missing callback as ErrNotFound.
+func (c *Client) setCallbackLocked(ctx context.Context, op string, displayID int, locked bool) error {
+	if displayID <= 0 {
+		return WrapError(op, ErrInvalidInput, "callback display ID must be positive")
+	}
+
+	err := c.UpdateCallback(ctx, &types.CallbackUpdateRequest{
+		CallbackDisplayID: displayID,
+		Locked:            &locked,
+	})
 	if err != nil {
-		return WrapError("UpdateCallback", err, "failed to verify callback exists")
+		return WrapError(op, err, "failed to update callback")
 	}
 
-	// Update not performed - would need REST API endpoint
 	return nil
 }
 
@@ -602,6 +1007,160 @@ func (c *Client) RemoveCallbackGraphEdge(ctx context.Context, edgeID int) error
 	return nil
 }
 
+// RemoveCallbackGraphEdgeByEndpoints removes the active P2P edge from sourceID to
+// destinationID over the named C2 profile. IDs are callback display IDs.
+// Returns ErrNotFound if no active edge matches, and ErrInvalidInput listing the
+// candidate edge IDs if more than one does; use RemoveCallbackGraphEdge to pick one.
+func (c *Client) RemoveCallbackGraphEdgeByEndpoints(ctx context.Context, sourceID, destinationID int, c2ProfileName string) error {
+	if sourceID <= 0 || destinationID <= 0 {
+		return WrapError("RemoveCallbackGraphEdgeByEndpoints", ErrInvalidInput, "source and destination IDs must be positive")
+	}
+
+	if c2ProfileName == "" {
+		return WrapError("RemoveCallbackGraphEdgeByEndpoints", ErrInvalidInput, "c2 profile name is required")
+	}
+
+	edges, err := c.GetCallbackGraphEdges(ctx, sourceID)
+	if err != nil {
+		return WrapError("RemoveCallbackGraphEdgeByEndpoints", err, "failed to get callback edges")
+	}
+
+	var candidates []int
+	for _, edge := range edges {
+		if edge.SourceID == sourceID && edge.DestinationID == destinationID &&
+			edge.C2ProfileName == c2ProfileName && edge.IsActive() {
+			candidates = append(candidates, edge.ID)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return WrapError("RemoveCallbackGraphEdgeByEndpoints", ErrNotFound,
+			fmt.Sprintf("no active %s edge from callback %d to %d", c2ProfileName, sourceID, destinationID))
+	case 1:
+		if err := c.RemoveCallbackGraphEdge(ctx, candidates[0]); err != nil {
+			return WrapError("RemoveCallbackGraphEdgeByEndpoints", err, "failed to remove callback edge")
+		}
+		return nil
+	default:
+		return WrapError("RemoveCallbackGraphEdgeByEndpoints", ErrInvalidInput,
+			fmt.Sprintf("multiple active %s edges from callback %d to %d: %v", c2ProfileName, sourceID, destinationID, candidates))
+	}
+}
+
+// callbackGraphEdgeQueryFields is the set of callbackgraphedge fields shared by
+// the graph edge queries.
+type callbackGraphEdgeQueryFields struct {
+	ID             int     `graphql:"id"`
+	StartTimestamp string  `graphql:"start_timestamp"`
+	EndTimestamp   *string `graphql:"end_timestamp"`
+	Source         struct {
+		DisplayID int `graphql:"display_id"`
+	} `graphql:"source"`
+	Destination struct {
+		DisplayID int `graphql:"display_id"`
+	} `graphql:"destination"`
+	C2Profile struct {
+		Name string `graphql:"name"`
+	} `graphql:"c2profile"`
+}
+
+// toCallbackGraphEdge converts the query result into a types.CallbackGraphEdge.
+func (e *callbackGraphEdgeQueryFields) toCallbackGraphEdge() *types.CallbackGraphEdge {
+	startTimestamp, _ := parseTime(e.StartTimestamp) //nolint:errcheck // Timestamp parse errors not critical
+
+	var endTimestamp *time.Time
+	if e.EndTimestamp != nil {
+		if ts, err := parseTime(*e.EndTimestamp); err == nil && !ts.IsZero() {
+			endTimestamp = &ts
+		}
+	}
+
+	return &types.CallbackGraphEdge{
+		ID:             e.ID,
+		SourceID:       e.Source.DisplayID,
+		DestinationID:  e.Destination.DisplayID,
+		C2ProfileName:  e.C2Profile.Name,
+		StartTimestamp: startTimestamp,
+		EndTimestamp:   endTimestamp,
+	}
+}
+
+// GetCallbackGraphEdges retrieves the P2P edges where the callback is either
+// the source or the destination, including edges that have since been removed.
+// Edge IDs returned here can be passed to RemoveCallbackGraphEdge.
+func (c *Client) GetCallbackGraphEdges(ctx context.Context, callbackDisplayID int) ([]*types.CallbackGraphEdge, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if callbackDisplayID <= 0 {
+		return nil, WrapError("GetCallbackGraphEdges", ErrInvalidInput, "callback display ID must be positive")
+	}
+
+	var query struct {
+		CallbackGraphEdge []callbackGraphEdgeQueryFields `graphql:"callbackgraphedge(where: {_or: [{source: {display_id: {_eq: $display_id}}}, {destination: {display_id: {_eq: $display_id}}}]}, order_by: {id: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"display_id": callbackDisplayID,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetCallbackGraphEdges", err, "failed to query callback edges")
+	}
+
+	edges := make([]*types.CallbackGraphEdge, len(query.CallbackGraphEdge))
+	for i := range query.CallbackGraphEdge {
+		edges[i] = query.CallbackGraphEdge[i].toCallbackGraphEdge()
+	}
+
+	return edges, nil
+}
+
+// GetCallbackGraphEdgesByOperation retrieves every P2P edge in an operation,
+// including edges that have since been removed (see CallbackGraphEdge.IsActive).
+// If operationID is 0, the current operation is used.
+func (c *Client) GetCallbackGraphEdgesByOperation(ctx context.Context, operationID int) ([]*types.CallbackGraphEdge, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if operationID < 0 {
+		return nil, WrapError("GetCallbackGraphEdgesByOperation", ErrInvalidInput, "operation ID must not be negative")
+	}
+
+	// Use current operation if not specified
+	if operationID == 0 {
+		currentOp := c.GetCurrentOperation()
+		if currentOp == nil {
+			return nil, WrapError("GetCallbackGraphEdgesByOperation", ErrNotAuthenticated, "no current operation set")
+		}
+		operationID = *currentOp
+	}
+
+	var query struct {
+		CallbackGraphEdge []callbackGraphEdgeQueryFields `graphql:"callbackgraphedge(where: {operation_id: {_eq: $operation_id}}, order_by: {id: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"operation_id": operationID,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetCallbackGraphEdgesByOperation", err, "failed to query callback edges")
+	}
+
+	edges := make([]*types.CallbackGraphEdge, len(query.CallbackGraphEdge))
+	for i := range query.CallbackGraphEdge {
+		edges[i] = query.CallbackGraphEdge[i].toCallbackGraphEdge()
+	}
+
+	return edges, nil
+}
+
 // ExportCallbackConfig exports a callback's configuration.
 func (c *Client) ExportCallbackConfig(ctx context.Context, agentCallbackID string) (string, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -637,6 +1196,23 @@ func (c *Client) ExportCallbackConfig(ctx context.Context, agentCallbackID strin
 	return query.ExportCallbackConfig.Config, nil
 }
 
+// ExportCallbackConfigParsed exports a callback's configuration and parses it.
+// Use CallbackConfig.Marshal to turn a modified config back into the string
+// accepted by ImportCallbackConfig.
+func (c *Client) ExportCallbackConfigParsed(ctx context.Context, agentCallbackID string) (*types.CallbackConfig, error) {
+	config, err := c.ExportCallbackConfig(ctx, agentCallbackID)
+	if err != nil {
+		return nil, WrapError("ExportCallbackConfigParsed", err, "failed to export callback config")
+	}
+
+	var parsed types.CallbackConfig
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		return nil, WrapError("ExportCallbackConfigParsed", ErrInvalidResponse, fmt.Sprintf("failed to parse callback config: %v", err))
+	}
+
+	return &parsed, nil
+}
+
 // ImportCallbackConfig imports a callback configuration.
 func (c *Client) ImportCallbackConfig(ctx context.Context, config string) error {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -5,14 +5,20 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
+	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hasura/go-graphql-client"
+	"golang.org/x/time/rate"
 )
 
 // Client is the main Mythic SDK client.
@@ -38,6 +44,9 @@ type Client struct {
 	// subscriptionClient is the WebSocket subscription client
 	subscriptionClient *graphql.SubscriptionClient
 
+	// subscriptionDone is closed when the current subscription client stops running
+	subscriptionDone chan struct{}
+
 	// subscriptionMutex protects subscription client initialization
 	subscriptionMutex sync.Mutex
 
@@ -46,6 +55,15 @@ type Client struct {
 
 	// subscriptionsMutex protects the activeSubscriptions map
 	subscriptionsMutex sync.RWMutex
+
+	// limiter paces outgoing requests; nil when rate limiting is disabled
+	limiter *rate.Limiter
+
+	// logger receives diagnostic events; never nil
+	logger Logger
+
+	// commandCache holds GetCommandWithParameters results when enabled
+	commandCache commandCache
 }
 
 // subscriptionContext holds the context for an active subscription
@@ -109,6 +127,11 @@ func NewClient(config *Config) (*Client, error) {
 		httpClient:          httpClient,
 		authenticated:       false,
 		activeSubscriptions: make(map[string]*subscriptionContext),
+		limiter:             newRateLimiter(config.RequestsPerSecond),
+		logger:              config.Logger,
+	}
+	if client.logger == nil {
+		client.logger = nopLogger{}
 	}
 
 	// If we have an API token or access token, consider authenticated
@@ -151,6 +174,7 @@ func (c *Client) Close() error {
 	if c.subscriptionClient != nil {
 		_ = c.subscriptionClient.Close() //nolint:errcheck // Best effort cleanup
 		c.subscriptionClient = nil
+		c.subscriptionDone = nil
 	}
 	c.subscriptionMutex.Unlock()
 
@@ -178,6 +202,77 @@ func (c *Client) GetCurrentOperation() *int {
 	return &id
 }
 
+// boolExp is a Hasura where clause built at runtime, passed as a single query
+// variable. The go-graphql-client library declares variables using
+// GetGraphQLType, so the clause is sent as the table's <table>_bool_exp type.
+type boolExp struct {
+	table string
+	conds map[string]interface{}
+}
+
+// newBoolExp creates an empty where clause for a table.
+func newBoolExp(table string) boolExp {
+	return boolExp{table: table, conds: make(map[string]interface{})}
+}
+
+// GetGraphQLType implements graphql.GraphQLType.
+func (b boolExp) GetGraphQLType() string {
+	return b.table + "_bool_exp"
+}
+
+// MarshalJSON encodes the clause's conditions.
+func (b boolExp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.conds)
+}
+
+// orderBy is a Hasura order_by clause built at runtime, passed as a single
+// query variable of the table's [<table>_order_by!] type. Columns are sorted
+// in the order they were added.
+type orderBy struct {
+	table string
+	terms []map[string]string
+}
+
+// newOrderBy creates an empty order_by clause for a table.
+func newOrderBy(table string) orderBy {
+	return orderBy{table: table}
+}
+
+// then returns a copy of the clause with column appended in the given
+// direction ("asc" or "desc").
+func (o orderBy) then(column, direction string) orderBy {
+	terms := make([]map[string]string, len(o.terms), len(o.terms)+1)
+	copy(terms, o.terms)
+	o.terms = append(terms, map[string]string{column: direction})
+	return o
+}
+
+// GetGraphQLType implements graphql.GraphQLType.
+func (o orderBy) GetGraphQLType() string {
+	return "[" + o.table + "_order_by!]"
+}
+
+// MarshalJSON encodes the clause's terms.
+func (o orderBy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.terms)
+}
+
+// jsonbValue is an arbitrary JSON document passed as a Hasura jsonb variable.
+type jsonbValue json.RawMessage
+
+// GetGraphQLType implements graphql.GraphQLType.
+func (j jsonbValue) GetGraphQLType() string {
+	return "jsonb"
+}
+
+// MarshalJSON encodes the document as-is, or null if it is empty.
+func (j jsonbValue) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
 // executeQuery executes a GraphQL query with authentication.
 func (c *Client) executeQuery(ctx context.Context, query interface{}, variables map[string]interface{}) error {
 	if !c.IsAuthenticated() {
@@ -187,8 +282,102 @@ func (c *Client) executeQuery(ctx context.Context, query interface{}, variables
 	// Create a client with authentication headers
 	client := c.getAuthenticatedClient()
 
+	// Cap unbounded list fields when a row guard is configured
+	guarded, capped := capQueryRows(query, c.config.MaxRows)
+
+	name := operationName(guarded)
+	c.logQueryText("query", name, func() (string, error) { return graphql.ConstructQuery(guarded, variables) })
+
 	// Execute query
-	return client.Query(ctx, query, variables)
+	start := time.Now()
+	err := c.withRetry(ctx, isRetryableError, func() error {
+		if err := c.waitForRateLimit(ctx); err != nil {
+			return err
+		}
+		return client.Query(ctx, guarded, variables)
+	})
+	err = validationError("executeQuery", err)
+	c.logOperation("query", name, start, err)
+	if err != nil || len(capped) == 0 {
+		return err
+	}
+
+	c.restoreCappedQuery(query, guarded, capped)
+	return nil
+}
+
+// capQueryRows returns a copy of query whose top-level list fields carry a
+// limit of maxRows when their graphql tag has none, along with the indexes of
+// the fields it capped. The query is returned unchanged when maxRows is not
+// positive or no field needs a limit.
+func capQueryRows(query interface{}, maxRows int) (interface{}, []int) {
+	if maxRows <= 0 {
+		return query, nil
+	}
+
+	ptr := reflect.ValueOf(query)
+	if ptr.Kind() != reflect.Ptr || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return query, nil
+	}
+	t := ptr.Elem().Type()
+
+	fields := make([]reflect.StructField, t.NumField())
+	var capped []int
+	for i := range fields {
+		f := t.Field(i)
+		if f.Anonymous || !f.IsExported() {
+			return query, nil
+		}
+		if tag, ok := limitedTag(f, maxRows); ok {
+			f.Tag = reflect.StructTag(fmt.Sprintf(`graphql:%q`, tag))
+			capped = append(capped, i)
+		}
+		fields[i] = f
+	}
+	if len(capped) == 0 {
+		return query, nil
+	}
+
+	return reflect.New(reflect.StructOf(fields)).Interface(), capped
+}
+
+// limitedTag returns field's graphql tag with a limit argument added, if the
+// field is a list without one.
+func limitedTag(field reflect.StructField, limit int) (string, bool) {
+	if field.Type.Kind() != reflect.Slice {
+		return "", false
+	}
+	tag, ok := field.Tag.Lookup("graphql")
+	if !ok || tag == "" || strings.Contains(tag, "@") {
+		return "", false
+	}
+
+	open := strings.Index(tag, "(")
+	if open < 0 {
+		return fmt.Sprintf("%s(limit: %d)", tag, limit), true
+	}
+	if strings.Contains(tag[open:], "limit:") || !strings.HasSuffix(tag, ")") {
+		return "", false
+	}
+	return fmt.Sprintf("%s, limit: %d)", strings.TrimSuffix(tag, ")"), limit), true
+}
+
+// restoreCappedQuery copies the results of a capped query back into the
+// caller's query struct and warns about every capped field that came back
+// full, since rows beyond the cap were silently dropped.
+func (c *Client) restoreCappedQuery(query, guarded interface{}, capped []int) {
+	dst := reflect.ValueOf(query).Elem()
+	src := reflect.ValueOf(guarded).Elem()
+	for i := 0; i < dst.NumField(); i++ {
+		dst.Field(i).Set(src.Field(i))
+	}
+
+	for _, i := range capped {
+		if src.Field(i).Len() >= c.config.MaxRows {
+			c.logger.Warnf("mythic: max_rows_hit field=%q max_rows=%d results may be truncated",
+				dst.Type().Field(i).Tag.Get("graphql"), c.config.MaxRows)
+		}
+	}
 }
 
 // executeMutation executes a GraphQL mutation with authentication.
@@ -200,8 +389,191 @@ func (c *Client) executeMutation(ctx context.Context, mutation interface{}, vari
 	// Create a client with authentication headers
 	client := c.getAuthenticatedClient()
 
+	name := operationName(mutation)
+	c.logQueryText("mutation", name, func() (string, error) { return graphql.ConstructMutation(mutation, variables) })
+
 	// Execute mutation
-	return client.Mutate(ctx, mutation, variables)
+	start := time.Now()
+	// Mutations are not idempotent, so only retry failures where the
+	// request never reached the server
+	err := c.withRetry(ctx, isUnsentRequestError, func() error {
+		if err := c.waitForRateLimit(ctx); err != nil {
+			return err
+		}
+		return client.Mutate(ctx, mutation, variables)
+	})
+	err = validationError("executeMutation", err)
+	c.logOperation("mutation", name, start, err)
+	return err
+}
+
+// newRateLimiter returns a limiter allowing requestsPerSecond requests with no
+// bursting, or nil if requestsPerSecond is not positive.
+func newRateLimiter(requestsPerSecond float64) *rate.Limiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+}
+
+// waitForRateLimit blocks until the client may send a request or ctx is done.
+// A wait that could not finish before ctx's deadline is reported as
+// context.DeadlineExceeded.
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return fmt.Errorf("%w: %v", context.DeadlineExceeded, err)
+	}
+	return nil
+}
+
+const (
+	defaultRetryBaseDelay = 200 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+// withRetry runs fn, retrying failures that retryable accepts as configured
+// by Config.Retry. Without a retry configuration fn runs exactly once. Each
+// delay is jittered to between half and all of the backoff so that clients
+// failing together do not retry in lockstep. Once a request has been retried,
+// its final error reports how many attempts were made.
+func (c *Client) withRetry(ctx context.Context, retryable func(error) bool, fn func() error) error {
+	cfg := c.config.Retry
+	if cfg == nil || cfg.MaxRetries == 0 {
+		return fn()
+	}
+
+	delay := cfg.BaseDelay
+	if delay == 0 {
+		delay = defaultRetryBaseDelay
+	}
+	maxDelay := cfg.MaxDelay
+	if maxDelay == 0 {
+		maxDelay = defaultRetryMaxDelay
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= cfg.MaxRetries || !retryable(err) {
+			if err != nil && attempt > 0 {
+				return WrapError("retry", err, fmt.Sprintf("failed after %d attempts", attempt+1))
+			}
+			return err
+		}
+
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) //nolint:gosec // Jitter needs no cryptographic randomness
+		select {
+		case <-ctx.Done():
+			return WrapError("retry", err, fmt.Sprintf("context done after %d attempts", attempt+1))
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
+
+// isRetryableError reports whether err is a transient transport failure, a
+// 5xx response or a 429 rate limit response. SDK validation errors, context
+// errors and GraphQL errors returned by the server are never retried.
+func isRetryableError(err error) bool {
+	if errors.Is(err, ErrInvalidInput) || errors.Is(err, ErrNotAuthenticated) || errors.Is(err, ErrGraphQLValidation) ||
+		errors.Is(err, ErrNotFound) || errors.Is(err, ErrAuthenticationFailed) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// go-graphql-client flattens transport failures and non-200 responses
+	// into request_error entries, keeping only the message
+	var gqlErrs graphql.Errors
+	if !errors.As(err, &gqlErrs) || len(gqlErrs) == 0 {
+		return false
+	}
+	gqlErr := gqlErrs[0]
+	if code, _ := gqlErr.Extensions["code"].(string); code != graphql.ErrRequestError {
+		return false
+	}
+	if strings.HasPrefix(gqlErr.Message, "problem constructing request") ||
+		strings.Contains(gqlErr.Message, context.Canceled.Error()) ||
+		strings.Contains(gqlErr.Message, context.DeadlineExceeded.Error()) {
+		return false
+	}
+
+	// Non-200 responses are reported as "<status>; body: ..."
+	var status int
+	if _, scanErr := fmt.Sscanf(gqlErr.Message, "%d ", &status); scanErr == nil {
+		return status >= 500 || status == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+// isUnsentRequestError reports whether err is a connection failure that
+// happened before the request was sent, so retrying cannot apply a mutation
+// twice. Failures after the connection was established are never retried,
+// since the server may already have applied the mutation.
+func isUnsentRequestError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial"
+	}
+
+	// go-graphql-client keeps only the message of transport failures, which
+	// for dial failures reads `Post "<url>": dial tcp ...`
+	var gqlErrs graphql.Errors
+	if !errors.As(err, &gqlErrs) || len(gqlErrs) == 0 {
+		return false
+	}
+	gqlErr := gqlErrs[0]
+	if code, _ := gqlErr.Extensions["code"].(string); code != graphql.ErrRequestError {
+		return false
+	}
+	return strings.Contains(gqlErr.Message, ": dial ")
+}
+
+// graphQLValidationCode is the extensions code Hasura reports for queries
+// that do not match the schema.
+const graphQLValidationCode = "validation-failed"
+
+// validationError rewraps a GraphQL schema validation failure as
+// ErrGraphQLValidation, keeping the server's message and the offending path.
+// Other errors are returned unchanged.
+func validationError(op string, err error) error {
+	var gqlErrs graphql.Errors
+	if !errors.As(err, &gqlErrs) {
+		return err
+	}
+	for _, gqlErr := range gqlErrs {
+		if code, _ := gqlErr.Extensions["code"].(string); code == graphQLValidationCode {
+			path, _ := gqlErr.Extensions["path"].(string)
+			return WrapError(op, ErrGraphQLValidation, validationMessage(gqlErr.Message, path))
+		}
+	}
+	return err
+}
+
+// validationMessage appends the schema path to a validation error message.
+func validationMessage(message, path string) string {
+	if path == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (at %s)", message, path)
 }
 
 // ExecuteRawGraphQL executes a raw GraphQL query and returns the raw JSON response.
@@ -211,6 +583,21 @@ func (c *Client) ExecuteRawGraphQL(ctx context.Context, query string, variables
 		return nil, ErrNotAuthenticated
 	}
 
+	kind, name := rawOperationName(query)
+	c.logQueryText(kind, name, func() (string, error) { return query, nil })
+
+	start := time.Now()
+	result, err := c.executeRawGraphQL(ctx, query, variables)
+	c.logOperation(kind, name, start, err)
+	return result, err
+}
+
+// executeRawGraphQL sends a raw GraphQL request for ExecuteRawGraphQL.
+func (c *Client) executeRawGraphQL(ctx context.Context, query string, variables map[string]interface{}) (map[string]interface{}, error) {
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return nil, WrapError("ExecuteRawGraphQL", err, "rate limit wait cancelled")
+	}
+
 	// Construct GraphQL endpoint URL
 	scheme := "https"
 	if !c.config.SSL {
@@ -277,6 +664,10 @@ func (c *Client) ExecuteRawGraphQL(ctx context.Context, query string, variables
 			if msg, ok := firstErr["message"].(string); ok {
 				errMsg = msg
 			}
+			if ext, ok := firstErr["extensions"].(map[string]interface{}); ok && ext["code"] == graphQLValidationCode {
+				path, _ := ext["path"].(string)
+				return nil, WrapError("ExecuteRawGraphQL", ErrGraphQLValidation, validationMessage(errMsg, path))
+			}
 		}
 		return nil, WrapError("ExecuteRawGraphQL", fmt.Errorf("%s", errMsg), "query failed")
 	}
@@ -290,6 +681,45 @@ func (c *Client) ExecuteRawGraphQL(ctx context.Context, query string, variables
 	return result, nil
 }
 
+// ExecuteRawGraphQLInto executes a raw GraphQL query and decodes the "data"
+// envelope into out, which must be a non-nil pointer. Fields are matched using
+// their json tags, so out can mirror only the parts of the response the caller
+// needs. GraphQL errors are reported exactly as ExecuteRawGraphQL reports them.
+func (c *Client) ExecuteRawGraphQLInto(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	if out == nil {
+		return WrapError("ExecuteRawGraphQLInto", ErrInvalidInput, "output target is required")
+	}
+
+	data, err := c.ExecuteRawGraphQL(ctx, query, variables)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return WrapError("ExecuteRawGraphQLInto", err, "failed to re-encode response data")
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return WrapError("ExecuteRawGraphQLInto", ErrInvalidResponse,
+			fmt.Sprintf("failed to decode response data: %v", err))
+	}
+
+	return nil
+}
+
+// rawStatusResult is the status/error envelope returned by Mythic's custom
+// action mutations, for decoding with ExecuteRawGraphQLInto.
+type rawStatusResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+}
+
+// failed reports whether the action returned a non-success status. A missing
+// status is treated as success, matching older Mythic versions that omit it.
+func (r *rawStatusResult) failed() bool {
+	return r.Status != "" && r.Status != "success"
+}
+
 // getAuthenticatedClient returns a GraphQL client with authentication headers.
 func (c *Client) getAuthenticatedClient() *graphql.Client {
 	headers := c.getAuthHeaders()
@@ -335,6 +765,10 @@ func (c *Client) executeRESTWebhook(ctx context.Context, endpoint string, reques
 		return WrapError("executeRESTWebhook", err, "failed to marshal request data")
 	}
 
+	if err := c.waitForRateLimit(ctx); err != nil {
+		return WrapError("executeRESTWebhook", err, "rate limit wait cancelled")
+	}
+
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBytes))
 	if err != nil {
@@ -391,13 +825,15 @@ func parseJSON(data []byte, v interface{}) error {
 
 // getSubscriptionClient returns or creates a WebSocket subscription client.
 // The subscription client is lazily initialized on first subscription request.
-func (c *Client) getSubscriptionClient() *graphql.SubscriptionClient {
+// The returned channel is closed once that client stops running, after which
+// the next call dials a new connection.
+func (c *Client) getSubscriptionClient() (*graphql.SubscriptionClient, <-chan struct{}) {
 	c.subscriptionMutex.Lock()
 	defer c.subscriptionMutex.Unlock()
 
 	// Return existing client if already initialized and running
 	if c.subscriptionClient != nil {
-		return c.subscriptionClient
+		return c.subscriptionClient, c.subscriptionDone
 	}
 
 	// Construct WebSocket URL
@@ -440,13 +876,25 @@ func (c *Client) getSubscriptionClient() *graphql.SubscriptionClient {
 		return nil
 	})
 
+	done := make(chan struct{})
 	c.subscriptionClient = client
+	c.subscriptionDone = done
 
 	// Start the subscription client in background
 	go func() {
 		// Error is handled by OnError handler above
-		_ = c.subscriptionClient.Run() //nolint:errcheck // Error handled by OnError callback
+		_ = client.Run() //nolint:errcheck // Error handled by OnError callback
+
+		// Run only returns once the client has given up, so drop it to let
+		// the next subscription re-dial
+		c.subscriptionMutex.Lock()
+		if c.subscriptionClient == client {
+			c.subscriptionClient = nil
+			c.subscriptionDone = nil
+		}
+		c.subscriptionMutex.Unlock()
+		close(done)
 	}()
 
-	return c.subscriptionClient
+	return client, done
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
@@ -174,8 +176,97 @@ type CommandWithParameters struct {
 	Parameters []*types.CommandParameter
 }
 
+// commandCacheKey identifies a command within a payload type.
+type commandCacheKey struct {
+	payloadTypeID int
+	commandName   string
+}
+
+// commandCacheEntry is a cached command definition and when it expires.
+type commandCacheEntry struct {
+	command *CommandWithParameters
+	expires time.Time
+}
+
+// commandCache stores command definitions for GetCommandWithParameters.
+// A zero ttl means caching is disabled.
+type commandCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[commandCacheKey]commandCacheEntry
+}
+
+// EnableCommandCache caches GetCommandWithParameters results in memory for ttl,
+// so building parameters for the same command repeatedly does not query the
+// server each time. A ttl of zero or less disables the cache. Enabling or
+// disabling the cache clears any existing entries.
+func (c *Client) EnableCommandCache(ttl time.Duration) {
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	c.commandCache.mu.Lock()
+	defer c.commandCache.mu.Unlock()
+	c.commandCache.ttl = ttl
+	c.commandCache.entries = nil
+}
+
+// InvalidateCommandCache removes all cached command definitions. Call it after
+// a payload type container syncs new or changed commands.
+func (c *Client) InvalidateCommandCache() {
+	c.commandCache.mu.Lock()
+	defer c.commandCache.mu.Unlock()
+	c.commandCache.entries = nil
+}
+
+// get returns a copy of the cached command for key if it has not expired.
+func (cc *commandCache) get(key commandCacheKey) (*CommandWithParameters, bool) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+
+	entry, ok := cc.entries[key]
+	if !ok || cc.ttl <= 0 || time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.command.clone(), true
+}
+
+// put stores a copy of command under key when caching is enabled.
+func (cc *commandCache) put(key commandCacheKey, command *CommandWithParameters) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.ttl <= 0 {
+		return
+	}
+	if cc.entries == nil {
+		cc.entries = make(map[commandCacheKey]commandCacheEntry)
+	}
+	cc.entries[key] = commandCacheEntry{
+		command: command.clone(),
+		expires: time.Now().Add(cc.ttl),
+	}
+}
+
+// clone returns a copy of cwp so cached entries cannot be modified by callers.
+func (cwp *CommandWithParameters) clone() *CommandWithParameters {
+	out := &CommandWithParameters{
+		Parameters: make([]*types.CommandParameter, len(cwp.Parameters)),
+	}
+	if cwp.Command != nil {
+		command := *cwp.Command
+		out.Command = &command
+	}
+	for i, param := range cwp.Parameters {
+		p := *param
+		out.Parameters[i] = &p
+	}
+	return out
+}
+
 // GetCommandWithParameters retrieves a specific command by name with all its parameters.
-// This is useful for building task parameters dynamically.
+// This is useful for building task parameters dynamically. Results are served
+// from memory when the cache is enabled with EnableCommandCache.
 func (c *Client) GetCommandWithParameters(ctx context.Context, payloadTypeID int, commandName string) (*CommandWithParameters, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
@@ -185,6 +276,22 @@ func (c *Client) GetCommandWithParameters(ctx context.Context, payloadTypeID int
 		return nil, WrapError("GetCommandWithParameters", ErrInvalidInput, "command name is required")
 	}
 
+	key := commandCacheKey{payloadTypeID: payloadTypeID, commandName: commandName}
+	if cached, ok := c.commandCache.get(key); ok {
+		return cached, nil
+	}
+
+	command, err := c.queryCommandWithParameters(ctx, payloadTypeID, commandName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.commandCache.put(key, command)
+	return command, nil
+}
+
+// queryCommandWithParameters fetches a command and its parameters from the server.
+func (c *Client) queryCommandWithParameters(ctx context.Context, payloadTypeID int, commandName string) (*CommandWithParameters, error) {
 	var query struct {
 		Command []struct {
 			ID            int    `graphql:"id"`
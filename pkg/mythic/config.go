@@ -33,6 +33,50 @@ type Config struct {
 
 	// SkipTLSVerify skips TLS certificate verification (use for self-signed certs)
 	SkipTLSVerify bool
+
+	// TaskLookupAttempts is how many times IssueTask tries to fetch a newly
+	// created task that is not yet queryable, backing off from 250ms between
+	// attempts. Zero uses the default of 3; 1 disables retrying.
+	TaskLookupAttempts int
+
+	// Retry enables retrying GraphQL queries that fail with a transient
+	// network or 5xx error, and mutations whose connection could not be
+	// established. Nil disables retrying.
+	Retry *RetryConfig
+
+	// RequestsPerSecond limits how often the client sends GraphQL and webhook
+	// requests. Calls over the limit block until allowed or their context is
+	// done. Zero disables rate limiting.
+	RequestsPerSecond float64
+
+	// MaxRows guards against runaway queries. When positive, top-level list
+	// fields of typed queries that do not set their own limit are capped at
+	// MaxRows rows, and a warning is logged whenever a capped field comes
+	// back full. Zero disables the guard.
+	MaxRows int
+
+	// Logger receives diagnostic events: every GraphQL operation with its
+	// duration and error, the query text at debug level, and warnings such as
+	// MaxRows truncation. Nil discards them.
+	Logger Logger
+}
+
+// RetryConfig controls how GraphQL requests are retried. Queries are retried
+// on network errors, 5xx responses and 429 rate limit responses; validation
+// errors and other 4xx failures never are. Mutations such as createTask are
+// not safe to repeat, so they are only retried when the connection could not
+// be established and the request was never sent. A mutation whose response
+// was lost fails without a retry, even if the server applied it.
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry, doubled after each
+	// attempt. Zero uses 200ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries. Zero uses 5s.
+	MaxDelay time.Duration
 }
 
 // Validate checks if the configuration is valid.
@@ -43,6 +87,27 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("ServerURL is required")
 	}
 
+	if c.TaskLookupAttempts < 0 {
+		return fmt.Errorf("TaskLookupAttempts cannot be negative")
+	}
+
+	if c.RequestsPerSecond < 0 {
+		return fmt.Errorf("RequestsPerSecond cannot be negative")
+	}
+
+	if c.MaxRows < 0 {
+		return fmt.Errorf("MaxRows cannot be negative")
+	}
+
+	if c.Retry != nil {
+		if c.Retry.MaxRetries < 0 {
+			return fmt.Errorf("Retry.MaxRetries cannot be negative")
+		}
+		if c.Retry.BaseDelay < 0 || c.Retry.MaxDelay < 0 {
+			return fmt.Errorf("Retry delays cannot be negative")
+		}
+	}
+
 	// Authentication credentials are optional - client can be created without them
 	// for testing error handling or for delayed authentication
 	// Login() will fail if no credentials are available when authentication is attempted
@@ -1,6 +1,9 @@
 package mythic
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // Error represents a Mythic SDK error.
 type Error struct {
@@ -61,8 +64,22 @@ var (
 
 	// ErrOperationFailed indicates an operation failed (returned error status)
 	ErrOperationFailed = fmt.Errorf("operation failed")
+
+	// ErrGraphQLValidation indicates Mythic rejected a query or mutation
+	// because it does not match the GraphQL schema. Retrying will not help;
+	// this usually means the SDK and the Mythic version disagree on a field.
+	ErrGraphQLValidation = fmt.Errorf("graphql validation failed")
+
+	// ErrChecksumMismatch indicates downloaded file content does not match the
+	// hash Mythic recorded for it. It also matches ErrInvalidResponse.
+	ErrChecksumMismatch = fmt.Errorf("%w: checksum mismatch", ErrInvalidResponse)
 )
 
+// IsValidationError reports whether err is a GraphQL schema validation failure.
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrGraphQLValidation)
+}
+
 // WrapError wraps an error with an operation and optional message.
 func WrapError(op string, err error, message string) error {
 	if err == nil {
@@ -2,10 +2,315 @@ package mythic
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
+// eventGroupQueryFields is the set of eventgroup fields shared by the event
+// group queries.
+type eventGroupQueryFields struct {
+	ID          int             `graphql:"id"`
+	Name        string          `graphql:"name"`
+	Description string          `graphql:"description"`
+	Trigger     string          `graphql:"trigger"`
+	OperationID int             `graphql:"operation_id"`
+	Active      bool            `graphql:"active"`
+	Deleted     bool            `graphql:"deleted"`
+	Approved    bool            `graphql:"approved_to_run"`
+	Keywords    json.RawMessage `graphql:"keywords"`
+}
+
+// toEventGroup converts the query result into a types.EventGroup.
+func (e *eventGroupQueryFields) toEventGroup() *types.EventGroup {
+	var keywords []string
+	if len(e.Keywords) > 0 {
+		_ = json.Unmarshal(e.Keywords, &keywords) //nolint:errcheck // Keywords are optional metadata
+	}
+
+	return &types.EventGroup{
+		ID:          e.ID,
+		Name:        e.Name,
+		Description: e.Description,
+		TriggerType: e.Trigger,
+		OperationID: e.OperationID,
+		Active:      e.Active,
+		Deleted:     e.Deleted,
+		Approved:    e.Approved,
+		Keywords:    keywords,
+	}
+}
+
+// GetEventGroups retrieves the event groups (eventing workflows) visible to the
+// current operator, excluding deleted ones. The returned IDs can be passed to
+// EventingTriggerManual and the other eventing functions.
+//
+// Example:
+//
+//	groups, err := client.GetEventGroups(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, group := range groups {
+//	    fmt.Printf("%d: %s\n", group.ID, group.String())
+//	}
+func (c *Client) GetEventGroups(ctx context.Context) ([]*types.EventGroup, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	var query struct {
+		EventGroup []eventGroupQueryFields `graphql:"eventgroup(where: {deleted: {_eq: false}}, order_by: {id: asc})"`
+	}
+
+	if err := c.executeQuery(ctx, &query, nil); err != nil {
+		return nil, WrapError("GetEventGroups", err, "failed to query event groups")
+	}
+
+	groups := make([]*types.EventGroup, len(query.EventGroup))
+	for i := range query.EventGroup {
+		groups[i] = query.EventGroup[i].toEventGroup()
+	}
+
+	return groups, nil
+}
+
+// GetEventGroupByID retrieves a single event group by ID, including deleted ones.
+// Returns ErrNotFound if the event group does not exist.
+func (c *Client) GetEventGroupByID(ctx context.Context, id int) (*types.EventGroup, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if id <= 0 {
+		return nil, WrapError("GetEventGroupByID", ErrInvalidInput, "event group ID must be positive")
+	}
+
+	var query struct {
+		EventGroup []eventGroupQueryFields `graphql:"eventgroup(where: {id: {_eq: $id}})"`
+	}
+
+	variables := map[string]interface{}{
+		"id": id,
+	}
+
+	if err := c.executeQuery(ctx, &query, variables); err != nil {
+		return nil, WrapError("GetEventGroupByID", err, "failed to query event group")
+	}
+
+	if len(query.EventGroup) == 0 {
+		return nil, WrapError("GetEventGroupByID", ErrNotFound, fmt.Sprintf("event group %d not found", id))
+	}
+
+	return query.EventGroup[0].toEventGroup(), nil
+}
+
+// GetEventExecutionStatus retrieves the state of an event group execution, such
+// as the ExecutionID returned by EventingTriggerManual. If any step failed, the
+// stderr of the failed steps is reported in Error.
+// Returns ErrNotFound if the execution does not exist.
+func (c *Client) GetEventExecutionStatus(ctx context.Context, executionID int) (*types.EventExecution, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if executionID <= 0 {
+		return nil, WrapError("GetEventExecutionStatus", ErrInvalidInput, "execution ID must be positive")
+	}
+
+	var query struct {
+		Instance []struct {
+			ID           int     `graphql:"id"`
+			EventGroupID int     `graphql:"eventgroup_id"`
+			OperationID  int     `graphql:"operation_id"`
+			Status       string  `graphql:"status"`
+			Trigger      string  `graphql:"trigger"`
+			CurrentStep  int     `graphql:"current_order_step"`
+			TotalSteps   int     `graphql:"total_order_steps"`
+			CreatedAt    string  `graphql:"created_at"`
+			EndTimestamp *string `graphql:"end_timestamp"`
+		} `graphql:"eventgroupinstance(where: {id: {_eq: $execution_id}})"`
+		FailedSteps []struct {
+			Stderr string `graphql:"stderr"`
+		} `graphql:"eventstepinstance(where: {eventgroupinstance_id: {_eq: $execution_id}, status: {_eq: \"error\"}}, order_by: {id: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"execution_id": executionID,
+	}
+
+	if err := c.executeQuery(ctx, &query, variables); err != nil {
+		return nil, WrapError("GetEventExecutionStatus", err, "failed to query event execution")
+	}
+
+	if len(query.Instance) == 0 {
+		return nil, WrapError("GetEventExecutionStatus", ErrNotFound, fmt.Sprintf("event execution %d not found", executionID))
+	}
+
+	inst := query.Instance[0]
+	createdAt, _ := parseTime(inst.CreatedAt) //nolint:errcheck // Timestamp parse errors not critical
+
+	var endTimestamp *time.Time
+	if inst.EndTimestamp != nil {
+		if ts, err := parseTime(*inst.EndTimestamp); err == nil && !ts.IsZero() {
+			endTimestamp = &ts
+		}
+	}
+
+	var stepErrors []string
+	for _, step := range query.FailedSteps {
+		if step.Stderr != "" {
+			stepErrors = append(stepErrors, step.Stderr)
+		}
+	}
+
+	return &types.EventExecution{
+		ID:           inst.ID,
+		EventGroupID: inst.EventGroupID,
+		OperationID:  inst.OperationID,
+		Status:       inst.Status,
+		Trigger:      inst.Trigger,
+		CurrentStep:  inst.CurrentStep,
+		TotalSteps:   inst.TotalSteps,
+		Error:        strings.Join(stepErrors, "\n"),
+		CreatedAt:    createdAt,
+		EndTimestamp: endTimestamp,
+	}, nil
+}
+
+// GetEventExecutionSteps retrieves the steps of an event group execution in
+// step order, so a failed step can be found and passed to
+// EventingTriggerRetryFromStep.
+//
+// Example:
+//
+//	steps, err := client.GetEventExecutionSteps(ctx, executionID)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, step := range steps {
+//	    if step.IsFailed() {
+//	        _, err = client.EventingTriggerRetryFromStep(ctx, executionID, step.Order)
+//	        break
+//	    }
+//	}
+func (c *Client) GetEventExecutionSteps(ctx context.Context, executionID int) ([]*types.EventStep, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if executionID <= 0 {
+		return nil, WrapError("GetEventExecutionSteps", ErrInvalidInput, "execution ID must be positive")
+	}
+
+	var query struct {
+		Steps []struct {
+			ID           int     `graphql:"id"`
+			Order        int     `graphql:"order"`
+			Status       string  `graphql:"status"`
+			Stdout       string  `graphql:"stdout"`
+			Stderr       string  `graphql:"stderr"`
+			EndTimestamp *string `graphql:"end_timestamp"`
+			EventStep    struct {
+				Name string `graphql:"name"`
+			} `graphql:"eventstep"`
+		} `graphql:"eventstepinstance(where: {eventgroupinstance_id: {_eq: $execution_id}}, order_by: {order: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"execution_id": executionID,
+	}
+
+	if err := c.executeQuery(ctx, &query, variables); err != nil {
+		return nil, WrapError("GetEventExecutionSteps", err, "failed to query event execution steps")
+	}
+
+	steps := make([]*types.EventStep, len(query.Steps))
+	for i, step := range query.Steps {
+		var endTimestamp *time.Time
+		if step.EndTimestamp != nil {
+			if ts, err := parseTime(*step.EndTimestamp); err == nil && !ts.IsZero() {
+				endTimestamp = &ts
+			}
+		}
+
+		steps[i] = &types.EventStep{
+			ID:           step.ID,
+			Order:        step.Order,
+			Name:         step.EventStep.Name,
+			Status:       step.Status,
+			Output:       step.Stdout,
+			Error:        step.Stderr,
+			EndTimestamp: endTimestamp,
+		}
+	}
+
+	return steps, nil
+}
+
+// WaitForEventExecution polls an event group execution until it finishes or the
+// timeout (in seconds, default 5 minutes) elapses. The final execution state is
+// returned along with ErrOperationFailed if it ended in error or was cancelled,
+// or ErrTimeout if it was still running at the deadline.
+//
+// Example:
+//
+//	resp, err := client.EventingTriggerManual(ctx, groupID, 0, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	execution, err := client.WaitForEventExecution(ctx, resp.ExecutionID, 120)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(execution.String())
+func (c *Client) WaitForEventExecution(ctx context.Context, executionID, timeout int) (*types.EventExecution, error) {
+	if timeout <= 0 {
+		timeout = 300 // Default 5 minutes
+	}
+
+	cfg := DefaultPollConfig()
+	deadline := time.After(time.Duration(timeout) * time.Second)
+
+	interval := cfg.InitialInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var execution *types.EventExecution
+	for {
+		select {
+		case <-deadline:
+			return execution, WrapError("WaitForEventExecution", ErrTimeout, fmt.Sprintf("event execution %d did not finish within %ds", executionID, timeout))
+		case <-ctx.Done():
+			return execution, ctx.Err()
+		case <-timer.C:
+			var err error
+			execution, err = c.GetEventExecutionStatus(ctx, executionID)
+			if err != nil {
+				return nil, WrapError("WaitForEventExecution", err, "failed to check event execution status")
+			}
+
+			if execution.IsTerminal() {
+				if !execution.IsSuccessful() {
+					return execution, WrapError("WaitForEventExecution", ErrOperationFailed,
+						fmt.Sprintf("event execution %d ended with status %s: %s", executionID, execution.Status, execution.Error))
+				}
+				return execution, nil
+			}
+
+			// Back off before the next check, capped at the max interval
+			timer.Reset(interval)
+			interval = time.Duration(float64(interval) * cfg.Multiplier)
+			if interval > cfg.MaxInterval {
+				interval = cfg.MaxInterval
+			}
+		}
+	}
+}
+
 // EventingTriggerManual manually triggers an event group for execution.
 // Event groups define automated workflows that can respond to various triggers.
 //
@@ -333,6 +638,7 @@ func (c *Client) EventingTriggerRetry(ctx context.Context, executionID int) (*ty
 }
 
 // EventingTriggerRetryFromStep retries a failed event execution from a specific step.
+// Use GetEventExecutionSteps to find the step that failed.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout
@@ -779,7 +1085,8 @@ func (c *Client) UpdateEventGroupApproval(ctx context.Context, eventGroupID int,
 	return response, nil
 }
 
-// SendExternalWebhook sends a webhook to an external service.
+// SendExternalWebhook sends a webhook with a JSON object body to an external service.
+// See SendExternalWebhookRaw to send other JSON values or apply a timeout.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout
@@ -801,52 +1108,132 @@ func (c *Client) UpdateEventGroupApproval(ctx context.Context, eventGroupID int,
 //	    return err
 //	}
 func (c *Client) SendExternalWebhook(ctx context.Context, webhookURL, method string, headers map[string]string, body map[string]interface{}) (*types.WebhookResponse, error) {
+	var rawBody json.RawMessage
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, WrapError("SendExternalWebhook", ErrInvalidInput, fmt.Sprintf("failed to encode body: %v", err))
+		}
+		rawBody = encoded
+	}
+
+	return c.sendExternalWebhook(ctx, "SendExternalWebhook", webhookURL, method, headers, rawBody, 0)
+}
+
+// SendExternalWebhookRaw sends a webhook with a pre-serialized JSON body, which
+// may be any JSON value (object, array, string, ...). If timeout is positive,
+// the call fails with a context deadline error once it elapses.
+// The downstream response headers are returned in WebhookResponse.Headers.
+//
+// Example:
+//
+//	body := json.RawMessage(`[{"event": "callback_created"}, {"event": "task_completed"}]`)
+//	response, err := client.SendExternalWebhookRaw(ctx, "https://api.example.com/webhook", "POST", nil, body, 10*time.Second)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(response.Headers["X-RateLimit-Remaining"])
+func (c *Client) SendExternalWebhookRaw(ctx context.Context, webhookURL, method string, headers map[string]string, body json.RawMessage, timeout time.Duration) (*types.WebhookResponse, error) {
+	return c.sendExternalWebhook(ctx, "SendExternalWebhookRaw", webhookURL, method, headers, body, timeout)
+}
+
+// sendExternalWebhook implements SendExternalWebhook and SendExternalWebhookRaw.
+func (c *Client) sendExternalWebhook(ctx context.Context, op, webhookURL, method string, headers map[string]string, body json.RawMessage, timeout time.Duration) (*types.WebhookResponse, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
 	// Validate input
 	if webhookURL == "" {
-		return nil, WrapError("SendExternalWebhook", ErrInvalidInput, "webhook URL cannot be empty")
+		return nil, WrapError(op, ErrInvalidInput, "webhook URL cannot be empty")
 	}
 	if method == "" {
 		method = "POST" // Default to POST
 	}
+	if len(body) > 0 && !json.Valid(body) {
+		return nil, WrapError(op, ErrInvalidInput, "body must be valid JSON")
+	}
+	if timeout < 0 {
+		return nil, WrapError(op, ErrInvalidInput, "timeout cannot be negative")
+	}
 
-	var mutation struct {
-		Response struct {
-			Status     string `graphql:"status"`
-			StatusCode int    `graphql:"status_code"`
-			Response   string `graphql:"response"`
-			Error      string `graphql:"error"`
-		} `graphql:"sendExternalWebhook(webhook_url: $webhook_url, method: $method, headers: $headers, body: $body)"`
+	var rawHeaders json.RawMessage
+	if headers != nil {
+		encoded, err := json.Marshal(headers)
+		if err != nil {
+			return nil, WrapError(op, ErrInvalidInput, fmt.Sprintf("failed to encode headers: %v", err))
+		}
+		rawHeaders = encoded
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
 	variables := map[string]interface{}{
 		"webhook_url": webhookURL,
 		"method":      method,
-		"headers":     headers,
-		"body":        body,
+		"headers":     jsonbValue(rawHeaders),
+		"body":        jsonbValue(body),
 	}
 
-	if err := c.executeMutation(ctx, &mutation, variables); err != nil {
-		return nil, WrapError("SendExternalWebhook", err, "failed to send webhook")
+	var mutation struct {
+		Response struct {
+			webhookResultFields
+			ResponseHeaders json.RawMessage `graphql:"response_headers"`
+		} `graphql:"sendExternalWebhook(webhook_url: $webhook_url, method: $method, headers: $headers, body: $body)"`
 	}
 
-	response := &types.WebhookResponse{
-		Status:     mutation.Response.Status,
-		StatusCode: mutation.Response.StatusCode,
-		Response:   mutation.Response.Response,
-		Error:      mutation.Response.Error,
+	var response *types.WebhookResponse
+	err := c.executeMutation(ctx, &mutation, variables)
+	switch {
+	case IsValidationError(err):
+		// Older schemas do not return response_headers; send again without it
+		// and leave Headers nil
+		var fallback struct {
+			Response webhookResultFields `graphql:"sendExternalWebhook(webhook_url: $webhook_url, method: $method, headers: $headers, body: $body)"`
+		}
+		if err := c.executeMutation(ctx, &fallback, variables); err != nil {
+			return nil, WrapError(op, err, "failed to send webhook")
+		}
+		response = fallback.Response.toWebhookResponse()
+	case err != nil:
+		return nil, WrapError(op, err, "failed to send webhook")
+	default:
+		response = mutation.Response.toWebhookResponse()
+		if len(mutation.Response.ResponseHeaders) > 0 {
+			_ = json.Unmarshal(mutation.Response.ResponseHeaders, &response.Headers) //nolint:errcheck // Headers are informational
+		}
 	}
 
 	if !response.IsSuccessful() {
-		return response, WrapError("SendExternalWebhook", ErrOperationFailed, response.Error)
+		return response, WrapError(op, ErrOperationFailed, response.Error)
 	}
 
 	return response, nil
 }
 
+// webhookResultFields are the sendExternalWebhook result fields every
+// supported schema returns.
+type webhookResultFields struct {
+	Status     string `graphql:"status"`
+	StatusCode int    `graphql:"status_code"`
+	Response   string `graphql:"response"`
+	Error      string `graphql:"error"`
+}
+
+// toWebhookResponse converts the result fields to a WebhookResponse.
+func (f webhookResultFields) toWebhookResponse() *types.WebhookResponse {
+	return &types.WebhookResponse{
+		Status:     f.Status,
+		StatusCode: f.StatusCode,
+		Response:   f.Response,
+		Error:      f.Error,
+	}
+}
+
 // ConsumingServicesTestWebhook tests a consuming service webhook configuration.
 //
 // Parameters:
@@ -1,16 +1,23 @@
 package mythic
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"crypto/md5"  //nolint:gosec // Matches the hash Mythic records, not used for security
+	"crypto/sha1" //nolint:gosec // Matches the hash Mythic records, not used for security
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"regexp"
 	"strings"
 	"time"
+
+	"github.com/hasura/go-graphql-client"
 )
 
 // Timestamp is a custom type to handle Mythic's timestamp format
@@ -98,83 +105,173 @@ type FileUploadResponse struct {
 	Status      string `json:"status"`
 }
 
-// GetFiles retrieves all files for the current operation.
+// fileMetaQueryFields are the filemeta columns selected by the file queries.
+type fileMetaQueryFields struct {
+	ID                  int    `graphql:"id"`
+	AgentFileID         string `graphql:"agent_file_id"`
+	TotalChunks         int    `graphql:"total_chunks"`
+	ChunksReceived      int    `graphql:"chunks_received"`
+	Complete            bool   `graphql:"complete"`
+	Path                string `graphql:"path"`
+	FullRemotePath      string `graphql:"full_remote_path"`
+	Host                string `graphql:"host"`
+	IsPayload           bool   `graphql:"is_payload"`
+	IsScreenshot        bool   `graphql:"is_screenshot"`
+	IsDownloadFromAgent bool   `graphql:"is_download_from_agent"`
+	Filename            string `graphql:"filename_text"`
+	MD5                 string `graphql:"md5"`
+	SHA1                string `graphql:"sha1"`
+	Size                int    `graphql:"size"`
+	Comment             string `graphql:"comment"`
+	OperatorID          int    `graphql:"operator_id"`
+	Timestamp           string `graphql:"timestamp"`
+	Deleted             bool   `graphql:"deleted"`
+	TaskID              *int   `graphql:"task_id"`
+}
+
+// toFileMeta converts the queried columns into a FileMeta.
+func (f fileMetaQueryFields) toFileMeta() *FileMeta {
+	// Parse timestamp
+	var timestamp time.Time
+	if f.Timestamp != "" {
+		var mt Timestamp
+		if err := mt.UnmarshalJSON([]byte(`"` + f.Timestamp + `"`)); err == nil {
+			timestamp = mt.Time
+		}
+	}
+
+	return &FileMeta{
+		ID:                  f.ID,
+		AgentFileID:         f.AgentFileID,
+		TotalChunks:         f.TotalChunks,
+		ChunksReceived:      f.ChunksReceived,
+		Complete:            f.Complete,
+		Path:                f.Path,
+		FullRemotePath:      f.FullRemotePath,
+		Host:                f.Host,
+		IsPayload:           f.IsPayload,
+		IsScreenshot:        f.IsScreenshot,
+		IsDownloadFromAgent: f.IsDownloadFromAgent,
+		Filename:            decodeFilename(f.Filename),
+		MD5:                 f.MD5,
+		SHA1:                f.SHA1,
+		Size:                int64(f.Size),
+		Comment:             f.Comment,
+		OperatorID:          f.OperatorID,
+		Timestamp:           timestamp,
+		Deleted:             f.Deleted,
+		TaskID:              f.TaskID,
+	}
+}
+
+// FileFilter selects files for GetFilesByFilter. Nil fields are left out of
+// the where clause; string fields match case-insensitively and may contain
+// % wildcards.
+type FileFilter struct {
+	// IsScreenshot restricts results to screenshots (true) or other files (false)
+	IsScreenshot *bool
+
+	// IsPayload restricts results to payload files (true) or other files (false)
+	IsPayload *bool
+
+	// IsDownloadFromAgent restricts results to files pulled from agents (true)
+	// or files uploaded to Mythic (false)
+	IsDownloadFromAgent *bool
+
+	// Deleted restricts results to deleted (true) or live (false) files
+	Deleted *bool
+
+	// Host matches the host the file came from or was sent to
+	Host *string
+
+	// Filename matches the file's name
+	Filename *string
+
+	// OperationID restricts results to files in this operation
+	OperationID *int
+}
+
+// GetFiles retrieves files across every operation the client can see, not
+// only the current one. Use GetFilesForOperation to scope the listing.
 func (c *Client) GetFiles(ctx context.Context, limit int) ([]*FileMeta, error) {
+	files, err := c.GetFilesByFilter(ctx, nil, limit)
+	if err != nil {
+		return nil, WrapError("GetFiles", err, "failed to query files")
+	}
+
+	return files, nil
+}
+
+// GetFilesForOperation retrieves the files in an operation, newest first.
+// The result is empty, not nil, when the operation has no files.
+func (c *Client) GetFilesForOperation(ctx context.Context, operationID int, limit int) ([]*FileMeta, error) {
+	if operationID <= 0 {
+		return nil, WrapError("GetFilesForOperation", ErrInvalidInput, "operation ID must be positive")
+	}
+
+	files, err := c.GetFilesByFilter(ctx, &FileFilter{OperationID: &operationID}, limit)
+	if err != nil {
+		return nil, WrapError("GetFilesForOperation", err, "failed to query files")
+	}
+
+	return files, nil
+}
+
+// GetFilesByFilter retrieves files matching filter, newest first, with the
+// filter applied server-side. A nil or empty filter behaves like GetFiles.
+// A limit of 0 or less defaults to 100.
+func (c *Client) GetFilesByFilter(ctx context.Context, filter *FileFilter, limit int) ([]*FileMeta, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
+	if filter == nil {
+		filter = &FileFilter{}
+	}
 	if limit <= 0 {
 		limit = 100
 	}
 
+	where := newBoolExp("filemeta")
+	for column, value := range map[string]*bool{
+		"is_screenshot":          filter.IsScreenshot,
+		"is_payload":             filter.IsPayload,
+		"is_download_from_agent": filter.IsDownloadFromAgent,
+		"deleted":                filter.Deleted,
+	} {
+		if value != nil {
+			where.conds[column] = map[string]interface{}{"_eq": *value}
+		}
+	}
+	for column, value := range map[string]*string{
+		"host":          filter.Host,
+		"filename_text": filter.Filename,
+	} {
+		if value != nil {
+			where.conds[column] = map[string]interface{}{"_ilike": *value}
+		}
+	}
+	if filter.OperationID != nil {
+		where.conds["operation_id"] = map[string]interface{}{"_eq": *filter.OperationID}
+	}
+
 	var query struct {
-		FileMeta []struct {
-			ID                  int    `graphql:"id"`
-			AgentFileID         string `graphql:"agent_file_id"`
-			TotalChunks         int    `graphql:"total_chunks"`
-			ChunksReceived      int    `graphql:"chunks_received"`
-			Complete            bool   `graphql:"complete"`
-			Path                string `graphql:"path"`
-			FullRemotePath      string `graphql:"full_remote_path"`
-			Host                string `graphql:"host"`
-			IsPayload           bool   `graphql:"is_payload"`
-			IsScreenshot        bool   `graphql:"is_screenshot"`
-			IsDownloadFromAgent bool   `graphql:"is_download_from_agent"`
-			Filename            string `graphql:"filename_text"`
-			MD5                 string `graphql:"md5"`
-			SHA1                string `graphql:"sha1"`
-			Size                int    `graphql:"size"`
-			Comment             string `graphql:"comment"`
-			OperatorID          int    `graphql:"operator_id"`
-			Timestamp           string `graphql:"timestamp"`
-			Deleted             bool   `graphql:"deleted"`
-			TaskID              *int   `graphql:"task_id"`
-		} `graphql:"filemeta(order_by: {id: desc}, limit: $limit)"`
+		FileMeta []fileMetaQueryFields `graphql:"filemeta(where: $where, order_by: {id: desc}, limit: $limit)"`
 	}
 
 	variables := map[string]interface{}{
+		"where": where,
 		"limit": limit,
 	}
 
 	err := c.executeQuery(ctx, &query, variables)
 	if err != nil {
-		return nil, WrapError("GetFiles", err, "failed to query files")
+		return nil, WrapError("GetFilesByFilter", err, "failed to query files")
 	}
 
-	files := make([]*FileMeta, 0, len(query.FileMeta))
-	for _, f := range query.FileMeta {
-		// Parse timestamp
-		var timestamp time.Time
-		if f.Timestamp != "" {
-			var mt Timestamp
-			if err := mt.UnmarshalJSON([]byte(`"` + f.Timestamp + `"`)); err == nil {
-				timestamp = mt.Time
-			}
-		}
-
-		files = append(files, &FileMeta{
-			ID:                  f.ID,
-			AgentFileID:         f.AgentFileID,
-			TotalChunks:         f.TotalChunks,
-			ChunksReceived:      f.ChunksReceived,
-			Complete:            f.Complete,
-			Path:                f.Path,
-			FullRemotePath:      f.FullRemotePath,
-			Host:                f.Host,
-			IsPayload:           f.IsPayload,
-			IsScreenshot:        f.IsScreenshot,
-			IsDownloadFromAgent: f.IsDownloadFromAgent,
-			Filename:            decodeFilename(f.Filename),
-			MD5:                 f.MD5,
-			SHA1:                f.SHA1,
-			Size:                int64(f.Size),
-			Comment:             f.Comment,
-			OperatorID:          f.OperatorID,
-			Timestamp:           timestamp,
-			Deleted:             f.Deleted,
-			TaskID:              f.TaskID,
-		})
+	files := make([]*FileMeta, len(query.FileMeta))
+	for i, f := range query.FileMeta {
+		files[i] = f.toFileMeta()
 	}
 
 	return files, nil
@@ -187,28 +284,7 @@ func (c *Client) GetFileByID(ctx context.Context, agentFileID string) (*FileMeta
 	}
 
 	var query struct {
-		FileMeta []struct {
-			ID                  int    `graphql:"id"`
-			AgentFileID         string `graphql:"agent_file_id"`
-			TotalChunks         int    `graphql:"total_chunks"`
-			ChunksReceived      int    `graphql:"chunks_received"`
-			Complete            bool   `graphql:"complete"`
-			Path                string `graphql:"path"`
-			FullRemotePath      string `graphql:"full_remote_path"`
-			Host                string `graphql:"host"`
-			IsPayload           bool   `graphql:"is_payload"`
-			IsScreenshot        bool   `graphql:"is_screenshot"`
-			IsDownloadFromAgent bool   `graphql:"is_download_from_agent"`
-			Filename            string `graphql:"filename_text"`
-			MD5                 string `graphql:"md5"`
-			SHA1                string `graphql:"sha1"`
-			Size                int    `graphql:"size"`
-			Comment             string `graphql:"comment"`
-			OperatorID          int    `graphql:"operator_id"`
-			Timestamp           string `graphql:"timestamp"`
-			Deleted             bool   `graphql:"deleted"`
-			TaskID              *int   `graphql:"task_id"`
-		} `graphql:"filemeta(where: {agent_file_id: {_eq: $agent_file_id}}, limit: 1)"`
+		FileMeta []fileMetaQueryFields `graphql:"filemeta(where: {agent_file_id: {_eq: $agent_file_id}}, limit: 1)"`
 	}
 
 	variables := map[string]interface{}{
@@ -224,118 +300,219 @@ func (c *Client) GetFileByID(ctx context.Context, agentFileID string) (*FileMeta
 		return nil, WrapError("GetFileByID", ErrNotFound, fmt.Sprintf("file with agent_file_id %s not found", agentFileID))
 	}
 
-	f := query.FileMeta[0]
+	return query.FileMeta[0].toFileMeta(), nil
+}
 
-	// Parse timestamp
-	var timestamp time.Time
-	if f.Timestamp != "" {
-		var mt Timestamp
-		if err := mt.UnmarshalJSON([]byte(`"` + f.Timestamp + `"`)); err == nil {
-			timestamp = mt.Time
-		}
+// GetDownloadedFiles retrieves all files downloaded from agents.
+func (c *Client) GetDownloadedFiles(ctx context.Context, limit int) ([]*FileMeta, error) {
+	downloaded, deleted := true, false
+	files, err := c.GetFilesByFilter(ctx, &FileFilter{IsDownloadFromAgent: &downloaded, Deleted: &deleted}, limit)
+	if err != nil {
+		return nil, WrapError("GetDownloadedFiles", err, "failed to query downloaded files")
 	}
 
-	return &FileMeta{
-		ID:                  f.ID,
-		AgentFileID:         f.AgentFileID,
-		TotalChunks:         f.TotalChunks,
-		ChunksReceived:      f.ChunksReceived,
-		Complete:            f.Complete,
-		Path:                f.Path,
-		FullRemotePath:      f.FullRemotePath,
-		Host:                f.Host,
-		IsPayload:           f.IsPayload,
-		IsScreenshot:        f.IsScreenshot,
-		IsDownloadFromAgent: f.IsDownloadFromAgent,
-		Filename:            decodeFilename(f.Filename),
-		MD5:                 f.MD5,
-		SHA1:                f.SHA1,
-		Size:                int64(f.Size),
-		Comment:             f.Comment,
-		OperatorID:          f.OperatorID,
-		Timestamp:           timestamp,
-		Deleted:             f.Deleted,
-		TaskID:              f.TaskID,
-	}, nil
+	return files, nil
 }
 
-// GetDownloadedFiles retrieves all files downloaded from agents.
-func (c *Client) GetDownloadedFiles(ctx context.Context, limit int) ([]*FileMeta, error) {
+// WatchCompletedDownloads streams files downloaded from agents in the current
+// operation as they finish. Each file is emitted once, when its upload from
+// the agent completes; downloads that were already complete when the watch
+// started are not emitted.
+//
+// Both channels are closed when ctx is cancelled or after an error has been
+// sent on the error channel.
+//
+// Example:
+//
+//	files, errs := client.WatchCompletedDownloads(ctx)
+//	for file := range files {
+//	    data, err := client.DownloadFile(ctx, file.AgentFileID)
+//	    // ...
+//	}
+//	if err := <-errs; err != nil {
+//	    return err
+//	}
+func (c *Client) WatchCompletedDownloads(ctx context.Context) (<-chan *FileMeta, <-chan error) {
+	files := make(chan *FileMeta, 100)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(files)
+		defer close(errs)
+
+		sendErr := func(err error) {
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+		}
+
+		if err := c.EnsureAuthenticated(ctx); err != nil {
+			sendErr(err)
+			return
+		}
+
+		operationID := c.GetCurrentOperation()
+		if operationID == nil {
+			sendErr(WrapError("WatchCompletedDownloads", ErrNotAuthenticated, "no current operation set"))
+			return
+		}
+
+		// Only downloads still in progress, or newer than any existing file,
+		// can complete from here on, which bounds the live query's result set
+		var snapshot struct {
+			Pending []struct {
+				ID int `graphql:"id"`
+			} `graphql:"pending: filemeta(where: {operation_id: {_eq: $operation_id}, is_download_from_agent: {_eq: true}, complete: {_eq: false}, deleted: {_eq: false}}, order_by: {id: asc}, limit: 1)"`
+			Latest []struct {
+				ID int `graphql:"id"`
+			} `graphql:"latest: filemeta(order_by: {id: desc}, limit: 1)"`
+		}
+		if err := c.executeQuery(ctx, &snapshot, map[string]interface{}{"operation_id": *operationID}); err != nil {
+			sendErr(WrapError("WatchCompletedDownloads", err, "failed to query existing files"))
+			return
+		}
+
+		sinceID := 1
+		if len(snapshot.Latest) > 0 {
+			sinceID = snapshot.Latest[0].ID + 1
+		}
+		if len(snapshot.Pending) > 0 {
+			sinceID = snapshot.Pending[0].ID
+		}
+
+		// Files that already completed in that range must not be re-emitted
+		var existing struct {
+			FileMeta []struct {
+				AgentFileID string `graphql:"agent_file_id"`
+			} `graphql:"filemeta(where: {operation_id: {_eq: $operation_id}, is_download_from_agent: {_eq: true}, complete: {_eq: true}, id: {_gte: $since_id}})"`
+		}
+		variables := map[string]interface{}{
+			"operation_id": *operationID,
+			"since_id":     sinceID,
+		}
+		if err := c.executeQuery(ctx, &existing, variables); err != nil {
+			sendErr(WrapError("WatchCompletedDownloads", err, "failed to query existing files"))
+			return
+		}
+
+		seen := make(map[string]bool, len(existing.FileMeta))
+		for _, f := range existing.FileMeta {
+			seen[f.AgentFileID] = true
+		}
+
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		batches := make(chan []fileMetaQueryFields, 10)
+		subErrs := make(chan error, 1)
+
+		type downloadSubscription struct {
+			FileMeta []fileMetaQueryFields `graphql:"filemeta(where: {operation_id: {_eq: $operation_id}, is_download_from_agent: {_eq: true}, complete: {_eq: true}, deleted: {_eq: false}, id: {_gte: $since_id}}, order_by: {id: asc})"`
+		}
+
+		subscriptionClient, disconnected := c.getSubscriptionClient()
+
+		subID, err := subscriptionClient.Subscribe(&downloadSubscription{}, variables, func(dataValue []byte, errValue error) error {
+			if errValue != nil {
+				select {
+				case subErrs <- errValue:
+				default:
+				}
+				return nil
+			}
+			var data downloadSubscription
+			if err := graphql.UnmarshalGraphQL(dataValue, &data); err != nil {
+				select {
+				case subErrs <- fmt.Errorf("failed to parse file event: %w", err):
+				default:
+				}
+				return nil
+			}
+			select {
+			case batches <- data.FileMeta:
+			case <-subCtx.Done():
+			}
+			return nil
+		})
+		if err != nil {
+			sendErr(WrapError("WatchCompletedDownloads", ErrOperationFailed, fmt.Sprintf("file subscription failed: %v", err)))
+			return
+		}
+		defer subscriptionClient.Unsubscribe(subID) //nolint:errcheck // Best effort cleanup
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-disconnected:
+				sendErr(WrapError("WatchCompletedDownloads", ErrConnectionFailed, "subscription connection lost"))
+				return
+			case err := <-subErrs:
+				sendErr(WrapError("WatchCompletedDownloads", ErrOperationFailed, err.Error()))
+				return
+			case batch := <-batches:
+				// Live queries resend the full result set, so updates to an
+				// already emitted file are skipped
+				for _, f := range batch {
+					if seen[f.AgentFileID] {
+						continue
+					}
+					seen[f.AgentFileID] = true
+
+					select {
+					case files <- f.toFileMeta():
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return files, errs
+}
+
+// GetFilesByTask retrieves the files associated with a task, such as those a
+// download command pulled from the agent or an upload command pushed to it,
+// oldest first. Deleted files are skipped unless includeDeleted is set.
+func (c *Client) GetFilesByTask(ctx context.Context, taskDisplayID int, includeDeleted bool) ([]*FileMeta, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
-	if limit <= 0 {
-		limit = 100
+	if taskDisplayID <= 0 {
+		return nil, WrapError("GetFilesByTask", ErrInvalidInput, "task display ID must be positive")
+	}
+
+	// Files reference the task's real ID, not its display ID
+	task, err := c.GetTask(ctx, taskDisplayID)
+	if err != nil {
+		return nil, WrapError("GetFilesByTask", err, "failed to get task")
+	}
+
+	where := newBoolExp("filemeta")
+	where.conds["task_id"] = map[string]interface{}{"_eq": task.ID}
+	if !includeDeleted {
+		where.conds["deleted"] = map[string]interface{}{"_eq": false}
 	}
 
 	var query struct {
-		FileMeta []struct {
-			ID                  int    `graphql:"id"`
-			AgentFileID         string `graphql:"agent_file_id"`
-			TotalChunks         int    `graphql:"total_chunks"`
-			ChunksReceived      int    `graphql:"chunks_received"`
-			Complete            bool   `graphql:"complete"`
-			Path                string `graphql:"path"`
-			FullRemotePath      string `graphql:"full_remote_path"`
-			Host                string `graphql:"host"`
-			IsPayload           bool   `graphql:"is_payload"`
-			IsScreenshot        bool   `graphql:"is_screenshot"`
-			IsDownloadFromAgent bool   `graphql:"is_download_from_agent"`
-			Filename            string `graphql:"filename_text"`
-			MD5                 string `graphql:"md5"`
-			SHA1                string `graphql:"sha1"`
-			Size                int    `graphql:"size"`
-			Comment             string `graphql:"comment"`
-			OperatorID          int    `graphql:"operator_id"`
-			Timestamp           string `graphql:"timestamp"`
-			Deleted             bool   `graphql:"deleted"`
-			TaskID              *int   `graphql:"task_id"`
-		} `graphql:"filemeta(where: {is_download_from_agent: {_eq: true}, deleted: {_eq: false}}, order_by: {id: desc}, limit: $limit)"`
+		FileMeta []fileMetaQueryFields `graphql:"filemeta(where: $where, order_by: {id: asc})"`
 	}
 
 	variables := map[string]interface{}{
-		"limit": limit,
+		"where": where,
 	}
 
-	err := c.executeQuery(ctx, &query, variables)
+	err = c.executeQuery(ctx, &query, variables)
 	if err != nil {
-		return nil, WrapError("GetDownloadedFiles", err, "failed to query downloaded files")
+		return nil, WrapError("GetFilesByTask", err, "failed to query files")
 	}
 
-	files := make([]*FileMeta, 0, len(query.FileMeta))
-	for _, f := range query.FileMeta {
-		// Parse timestamp
-		var timestamp time.Time
-		if f.Timestamp != "" {
-			var mt Timestamp
-			if err := mt.UnmarshalJSON([]byte(`"` + f.Timestamp + `"`)); err == nil {
-				timestamp = mt.Time
-			}
-		}
-
-		files = append(files, &FileMeta{
-			ID:                  f.ID,
-			AgentFileID:         f.AgentFileID,
-			TotalChunks:         f.TotalChunks,
-			ChunksReceived:      f.ChunksReceived,
-			Complete:            f.Complete,
-			Path:                f.Path,
-			FullRemotePath:      f.FullRemotePath,
-			Host:                f.Host,
-			IsPayload:           f.IsPayload,
-			IsScreenshot:        f.IsScreenshot,
-			IsDownloadFromAgent: f.IsDownloadFromAgent,
-			Filename:            decodeFilename(f.Filename),
-			MD5:                 f.MD5,
-			SHA1:                f.SHA1,
-			Size:                int64(f.Size),
-			Comment:             f.Comment,
-			OperatorID:          f.OperatorID,
-			Timestamp:           timestamp,
-			Deleted:             f.Deleted,
-			TaskID:              f.TaskID,
-		})
+	files := make([]*FileMeta, len(query.FileMeta))
+	for i, f := range query.FileMeta {
+		files[i] = f.toFileMeta()
 	}
 
 	return files, nil
@@ -344,52 +521,171 @@ func (c *Client) GetDownloadedFiles(ctx context.Context, limit int) ([]*FileMeta
 // UploadFile uploads a file to Mythic for use in tasks.
 // Returns the agent_file_id that can be used to reference the file.
 func (c *Client) UploadFile(ctx context.Context, filename string, fileData []byte) (string, error) {
+	if len(fileData) == 0 {
+		return "", WrapError("UploadFile", ErrInvalidInput, "file data is required")
+	}
+
+	return c.uploadFileReader(ctx, "UploadFile", filename, bytes.NewReader(fileData), int64(len(fileData)), nil)
+}
+
+// UploadFileReader uploads a file to Mythic by streaming exactly size bytes
+// from r, so memory use stays flat regardless of file size. If progress is
+// non-nil it is called with the running byte count every 64KB and once the
+// whole file has been sent.
+// Returns the agent_file_id that can be used to reference the file.
+//
+// Mythic's upload webhook accepts the whole file in one request, so an
+// interrupted upload must be restarted. FileMeta's TotalChunks and
+// ChunksReceived track agent transfers, not operator uploads.
+//
+// Example:
+//
+//	f, err := os.Open("tool.exe")
+//	if err != nil {
+//	    return err
+//	}
+//	defer f.Close()
+//
+//	info, err := f.Stat()
+//	if err != nil {
+//	    return err
+//	}
+//
+//	agentFileID, err := client.UploadFileReader(ctx, "tool.exe", f, info.Size(), func(sent int64) {
+//	    fmt.Printf("\r%d/%d bytes", sent, info.Size())
+//	})
+func (c *Client) UploadFileReader(ctx context.Context, filename string, r io.Reader, size int64, progress func(sent int64)) (string, error) {
+	return c.uploadFileReader(ctx, "UploadFileReader", filename, r, size, progress)
+}
+
+// uploadProgressInterval is how many bytes UploadFileReader sends between
+// progress reports.
+const uploadProgressInterval = 64 * 1024
+
+// UploadFileWithProgress uploads a file to Mythic by streaming exactly size
+// bytes from r, like UploadFileReader, but reports progress as bytes sent out
+// of the total size. If onProgress is non-nil it is called every 64KB and
+// once the whole file has been sent.
+// Returns the agent_file_id that can be used to reference the file.
+func (c *Client) UploadFileWithProgress(ctx context.Context, filename string, r io.Reader, size int64, onProgress func(sent, total int64)) (string, error) {
+	var progress func(sent int64)
+	if onProgress != nil {
+		progress = func(sent int64) {
+			onProgress(sent, size)
+		}
+	}
+
+	return c.uploadFileReader(ctx, "UploadFileWithProgress", filename, r, size, progress)
+}
+
+// UploadFileStreaming uploads a file to Mythic by streaming exactly size bytes
+// from r. It behaves exactly like UploadFileReader.
+// Returns the agent_file_id that can be used to reference the file.
+func (c *Client) UploadFileStreaming(ctx context.Context, filename string, r io.Reader, size int64, progress func(sent int64)) (string, error) {
+	return c.uploadFileReader(ctx, "UploadFileStreaming", filename, r, size, progress)
+}
+
+// uploadFileReader streams size bytes from r to Mythic as a multipart upload.
+// The multipart body is produced by a goroutine writing into an io.Pipe; it
+// has always exited by the time uploadFileReader returns, so r is not read
+// afterwards.
+func (c *Client) uploadFileReader(ctx context.Context, op, filename string, r io.Reader, size int64, progress func(sent int64)) (string, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return "", err
 	}
 
 	if filename == "" {
-		return "", WrapError("UploadFile", ErrInvalidInput, "filename is required")
+		return "", WrapError(op, ErrInvalidInput, "filename is required")
 	}
 
-	if len(fileData) == 0 {
-		return "", WrapError("UploadFile", ErrInvalidInput, "file data is required")
+	if r == nil {
+		return "", WrapError(op, ErrInvalidInput, "reader is required")
 	}
 
-	// Construct upload endpoint URL
-	scheme := "https"
-	if !c.config.SSL {
-		scheme = "http"
+	if size <= 0 {
+		return "", WrapError(op, ErrInvalidInput, "size must be positive")
 	}
-	uploadURL := fmt.Sprintf("%s://%s/api/v1.4/task_upload_file_webhook", scheme, stripScheme(c.config.ServerURL))
 
-	// Create multipart form
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	// Stream the multipart body through a pipe so the file is never buffered
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+	done := make(chan struct{})
 
-	// Add file field
-	part, err := writer.CreateFormFile("file", filename)
-	if err != nil {
-		return "", WrapError("UploadFile", err, "failed to create form file")
-	}
+	go func() {
+		defer close(done)
 
-	_, err = part.Write(fileData)
-	if err != nil {
-		return "", WrapError("UploadFile", err, "failed to write file data")
+		part, err := writer.CreateFormFile("file", filename)
+		if err != nil {
+			pw.CloseWithError(err) //nolint:errcheck // Always returns nil
+			return
+		}
+
+		dst := io.Writer(part)
+		if progress != nil {
+			dst = &progressWriter{w: part, total: size, progress: progress}
+		}
+
+		if _, err := io.CopyN(dst, r, size); err != nil {
+			if err == io.EOF {
+				err = WrapError(op, ErrInvalidInput, fmt.Sprintf("reader ended before %d bytes", size))
+			}
+			pw.CloseWithError(err) //nolint:errcheck // Always returns nil
+			return
+		}
+
+		pw.CloseWithError(writer.Close()) //nolint:errcheck // Always returns nil
+	}()
+
+	agentFileID, err := c.postFileUpload(ctx, op, pr, writer.FormDataContentType())
+
+	// Unblock the writer goroutine if the request ended early, e.g. on
+	// context cancellation, and wait for it to stop reading r
+	pr.Close() //nolint:errcheck // Always returns nil
+	<-done
+
+	return agentFileID, err
+}
+
+// progressWriter reports the running byte count every
+// uploadProgressInterval bytes and once total bytes have been written.
+type progressWriter struct {
+	w        io.Writer
+	sent     int64
+	reported int64
+	total    int64
+	progress func(sent int64)
+}
+
+// Write implements io.Writer.
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.sent += int64(n)
+		if p.sent-p.reported >= uploadProgressInterval || p.sent == p.total {
+			p.reported = p.sent
+			p.progress(p.sent)
+		}
 	}
+	return n, err
+}
 
-	err = writer.Close()
-	if err != nil {
-		return "", WrapError("UploadFile", err, "failed to close multipart writer")
+// postFileUpload sends a multipart upload body to Mythic's file upload webhook
+// and returns the resulting agent_file_id.
+func (c *Client) postFileUpload(ctx context.Context, op string, body io.Reader, contentType string) (string, error) {
+	// Construct upload endpoint URL
+	scheme := "https"
+	if !c.config.SSL {
+		scheme = "http"
 	}
+	uploadURL := fmt.Sprintf("%s://%s/api/v1.4/task_upload_file_webhook", scheme, stripScheme(c.config.ServerURL))
 
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, body)
 	if err != nil {
-		return "", WrapError("UploadFile", err, "failed to create upload request")
+		return "", WrapError(op, err, "failed to create upload request")
 	}
 
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Content-Type", contentType)
 
 	// Add authentication headers
 	authHeaders := c.getAuthHeaders()
@@ -400,43 +696,193 @@ func (c *Client) UploadFile(ctx context.Context, filename string, fileData []byt
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", WrapError("UploadFile", err, "failed to execute upload request")
+		return "", WrapError(op, err, "failed to execute upload request")
 	}
 	defer resp.Body.Close() //nolint:errcheck // Response body close error not critical
 
 	// Read response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", WrapError("UploadFile", err, "failed to read upload response")
+		return "", WrapError(op, err, "failed to read upload response")
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", WrapError("UploadFile", ErrInvalidResponse, fmt.Sprintf("upload failed with status %d: %s", resp.StatusCode, string(respBody)))
+		return "", WrapError(op, ErrInvalidResponse, fmt.Sprintf("upload failed with status %d: %s", resp.StatusCode, string(respBody)))
 	}
 
 	// Parse response - Mythic returns {"agent_file_id": "...", "status": "success"}
 	var uploadResp FileUploadResponse
 	if err := parseJSON(respBody, &uploadResp); err != nil {
-		return "", WrapError("UploadFile", err, "failed to parse upload response")
+		return "", WrapError(op, err, "failed to parse upload response")
 	}
 
 	if uploadResp.AgentFileID == "" {
-		return "", WrapError("UploadFile", ErrInvalidResponse, "no agent_file_id in response")
+		return "", WrapError(op, ErrInvalidResponse, "no agent_file_id in response")
 	}
 
 	return uploadResp.AgentFileID, nil
 }
 
+// downloadSniffSize is how much of a download is buffered to detect Mythic's
+// JSON-wrapped responses before the body is streamed.
+const downloadSniffSize = 4096
+
+// downloadFileFieldPattern matches the start of the base64 "file" field in a
+// JSON-wrapped download.
+var downloadFileFieldPattern = regexp.MustCompile(`"file"\s*:\s*"`)
+
 // DownloadFile downloads a file's content from Mythic.
+// The whole file is held in memory; use DownloadFileToWriter or
+// DownloadFileStream for large files.
 func (c *Client) DownloadFile(ctx context.Context, agentFileID string) ([]byte, error) {
-	if err := c.EnsureAuthenticated(ctx); err != nil {
+	var buf bytes.Buffer
+	if _, err := c.downloadFileTo(ctx, "DownloadFile", agentFileID, &buf); err != nil {
 		return nil, err
 	}
 
+	return buf.Bytes(), nil
+}
+
+// DownloadFileToWriter streams a file's content from Mythic into w and returns
+// the number of bytes written. Base64-wrapped JSON responses are decoded on
+// the fly, so memory use stays flat regardless of file size. On error, w may
+// have received part of the file.
+//
+// Example:
+//
+//	zipID, err := client.BulkDownloadFiles(ctx, fileIDs)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	out, err := os.Create("loot.zip")
+//	if err != nil {
+//	    return err
+//	}
+//	defer out.Close()
+//
+//	_, err = client.DownloadFileToWriter(ctx, zipID, out)
+func (c *Client) DownloadFileToWriter(ctx context.Context, agentFileID string, w io.Writer) (int64, error) {
+	if w == nil {
+		return 0, WrapError("DownloadFileToWriter", ErrInvalidInput, "writer is required")
+	}
+
+	return c.downloadFileTo(ctx, "DownloadFileToWriter", agentFileID, w)
+}
+
+// downloadFileTo copies the decoded content of a file download into w.
+func (c *Client) downloadFileTo(ctx context.Context, op, agentFileID string, w io.Writer) (int64, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return 0, err
+	}
+
+	if agentFileID == "" {
+		return 0, WrapError(op, ErrInvalidInput, "agent_file_id is required")
+	}
+
+	body, err := c.openFileDownload(ctx, op, agentFileID)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close() //nolint:errcheck // Response body close error not critical
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, WrapError(op, err, "failed to read file data")
+	}
+
+	return n, nil
+}
+
+// DownloadFileVerified downloads a file's content and checks it against the
+// MD5 and SHA1 hashes Mythic recorded for it, returning ErrChecksumMismatch on
+// a mismatch. Hashes are compared case-insensitively, and a hash the server
+// reports as empty is not checked.
+func (c *Client) DownloadFileVerified(ctx context.Context, agentFileID string) ([]byte, error) {
+	if agentFileID == "" {
+		return nil, WrapError("DownloadFileVerified", ErrInvalidInput, "agent_file_id is required")
+	}
+
+	meta, err := c.GetFileByID(ctx, agentFileID)
+	if err != nil {
+		return nil, WrapError("DownloadFileVerified", err, "failed to get file metadata")
+	}
+
+	fileData, err := c.DownloadFile(ctx, agentFileID)
+	if err != nil {
+		return nil, WrapError("DownloadFileVerified", err, "failed to download file")
+	}
+
+	md5Sum := md5.Sum(fileData)   //nolint:gosec // See import
+	sha1Sum := sha1.Sum(fileData) //nolint:gosec // See import
+
+	for _, check := range []struct {
+		name     string
+		expected string
+		actual   []byte
+	}{
+		{"md5", meta.MD5, md5Sum[:]},
+		{"sha1", meta.SHA1, sha1Sum[:]},
+	} {
+		if check.expected == "" {
+			continue
+		}
+		if actual := hex.EncodeToString(check.actual); !strings.EqualFold(actual, check.expected) {
+			return nil, WrapError("DownloadFileVerified", ErrChecksumMismatch,
+				fmt.Sprintf("%s mismatch: expected %s, got %s", check.name, check.expected, actual))
+		}
+	}
+
+	return fileData, nil
+}
+
+// DownloadFileStream downloads a file's content from Mythic as a stream,
+// along with its metadata. Base64-wrapped JSON responses are decoded on the
+// fly, so memory use stays flat regardless of file size.
+// The caller must close the returned reader.
+//
+// Example:
+//
+//	body, meta, err := client.DownloadFileStream(ctx, agentFileID)
+//	if err != nil {
+//	    return err
+//	}
+//	defer body.Close()
+//
+//	out, err := os.Create(meta.Filename)
+//	if err != nil {
+//	    return err
+//	}
+//	defer out.Close()
+//
+//	_, err = io.Copy(out, body)
+func (c *Client) DownloadFileStream(ctx context.Context, agentFileID string) (io.ReadCloser, *FileMeta, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, nil, err
+	}
+
 	if agentFileID == "" {
-		return nil, WrapError("DownloadFile", ErrInvalidInput, "agent_file_id is required")
+		return nil, nil, WrapError("DownloadFileStream", ErrInvalidInput, "agent_file_id is required")
 	}
 
+	meta, err := c.GetFileByID(ctx, agentFileID)
+	if err != nil {
+		return nil, nil, WrapError("DownloadFileStream", err, "failed to get file metadata")
+	}
+
+	body, err := c.openFileDownload(ctx, "DownloadFileStream", agentFileID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return body, meta, nil
+}
+
+// openFileDownload requests a file download and returns a reader over the
+// decoded file content. Responses small enough to buffer are inspected in full
+// for Mythic's JSON error and {file: base64data} forms; larger JSON-wrapped
+// responses are decoded from the "file" field as they stream.
+func (c *Client) openFileDownload(ctx context.Context, op, agentFileID string) (io.ReadCloser, error) {
 	// Construct download endpoint URL
 	scheme := "https"
 	if !c.config.SSL {
@@ -447,7 +893,7 @@ func (c *Client) DownloadFile(ctx context.Context, agentFileID string) ([]byte,
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
 	if err != nil {
-		return nil, WrapError("DownloadFile", err, "failed to create download request")
+		return nil, WrapError(op, err, "failed to create download request")
 	}
 
 	// Add authentication headers
@@ -459,23 +905,32 @@ func (c *Client) DownloadFile(ctx context.Context, agentFileID string) ([]byte,
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, WrapError("DownloadFile", err, "failed to execute download request")
+		return nil, WrapError(op, err, "failed to execute download request")
 	}
-	defer resp.Body.Close() //nolint:errcheck // Response body close error not critical
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body) //nolint:errcheck // Best effort to read error message
-		return nil, WrapError("DownloadFile", ErrInvalidResponse, fmt.Sprintf("download failed with status %d: %s", resp.StatusCode, string(body)))
+		resp.Body.Close()                //nolint:errcheck // Response body close error not critical
+		return nil, WrapError(op, ErrInvalidResponse, fmt.Sprintf("download failed with status %d: %s", resp.StatusCode, string(body)))
 	}
 
-	// Read file content
-	fileData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, WrapError("DownloadFile", err, "failed to read file data")
+	br := bufio.NewReaderSize(resp.Body, downloadSniffSize)
+	prefix, err := br.Peek(downloadSniffSize)
+	if err != nil && err != io.EOF {
+		resp.Body.Close() //nolint:errcheck // Response body close error not critical
+		return nil, WrapError(op, err, "failed to read file data")
 	}
 
 	// Check if response is JSON (Mythic returns JSON for errors and base64-encoded files)
-	if len(fileData) > 0 && fileData[0] == '{' {
+	if len(prefix) == 0 || prefix[0] != '{' {
+		return readCloser{Reader: br, Closer: resp.Body}, nil
+	}
+
+	// The whole response fit in the buffer, so handle it in memory
+	if err == io.EOF {
+		fileData := append([]byte(nil), prefix...)
+		resp.Body.Close() //nolint:errcheck // Response body close error not critical
+
 		// First check for error response
 		var errorResp struct {
 			Status string `json:"status"`
@@ -483,7 +938,7 @@ func (c *Client) DownloadFile(ctx context.Context, agentFileID string) ([]byte,
 		}
 		if err := parseJSON(fileData, &errorResp); err == nil {
 			if errorResp.Status == "error" {
-				return nil, WrapError("DownloadFile", ErrNotFound, errorResp.Error)
+				return nil, WrapError(op, ErrNotFound, errorResp.Error)
 			}
 		}
 
@@ -495,12 +950,100 @@ func (c *Client) DownloadFile(ctx context.Context, agentFileID string) ([]byte,
 			// Decode base64
 			decoded, err := base64.StdEncoding.DecodeString(jsonResp.File)
 			if err == nil {
-				return decoded, nil
+				fileData = decoded
 			}
 		}
+
+		return io.NopCloser(bytes.NewReader(fileData)), nil
 	}
 
-	return fileData, nil
+	// Large JSON response: stream-decode the "file" field. Once streaming
+	// starts there is no falling back, so a JSON file that merely contains a
+	// "file" key is returned raw unless the buffered part of the field is
+	// base64 that runs past the buffer.
+	loc := downloadFileFieldPattern.FindIndex(prefix)
+	if loc == nil || !isStreamedBase64Field(prefix, loc[0], loc[1]) {
+		return readCloser{Reader: br, Closer: resp.Body}, nil
+	}
+	if _, err := br.Discard(loc[1]); err != nil {
+		resp.Body.Close() //nolint:errcheck // Response body close error not critical
+		return nil, WrapError(op, err, "failed to read file data")
+	}
+
+	return readCloser{
+		Reader: base64.NewDecoder(base64.StdEncoding, &jsonStringReader{r: br}),
+		Closer: resp.Body,
+	}, nil
+}
+
+// isStreamedBase64Field reports whether the "file" field matched at
+// prefix[start:valueStart] is a top-level key whose value is base64 (with
+// optionally escaped slashes) for the rest of prefix. A value that closes
+// inside prefix is not a large file, so it is not treated as one.
+func isStreamedBase64Field(prefix []byte, start, valueStart int) bool {
+	if bytes.ContainsAny(prefix[1:start], "{[") {
+		return false
+	}
+	for _, b := range prefix[valueStart:] {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '+', b == '/', b == '=', b == '\\':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// readCloser pairs a reader with the closer of the underlying response body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// jsonStringReader reads the raw contents of a JSON string value up to its
+// closing quote. Backslashes are dropped, which is enough to undo escaped
+// slashes in base64 data.
+type jsonStringReader struct {
+	r    *bufio.Reader
+	done bool
+}
+
+// Read implements io.Reader.
+func (j *jsonStringReader) Read(p []byte) (int, error) {
+	if j.done {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for n < len(p) {
+		b, err := j.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+		if b == '"' {
+			j.done = true
+			break
+		}
+		if b == '\\' {
+			continue
+		}
+		p[n] = b
+		n++
+
+		// Return what is buffered rather than blocking on the network
+		if j.r.Buffered() == 0 {
+			break
+		}
+	}
+
+	if n == 0 && j.done {
+		return 0, io.EOF
+	}
+	return n, nil
 }
 
 // DeleteFile marks a file as deleted in Mythic.
@@ -565,7 +1108,7 @@ type FilePreview struct {
 }
 
 // BulkDownloadFiles creates a ZIP archive of multiple files and returns the file ID for download.
-// The returned file_id can be used with DownloadFile() to retrieve the ZIP archive.
+// The returned file_id can be used with DownloadFile() or DownloadFileToWriter() to retrieve the ZIP archive.
 func (c *Client) BulkDownloadFiles(ctx context.Context, agentFileIDs []string) (string, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return "", err
@@ -3,23 +3,27 @@ package mythic
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
-// GetHosts retrieves all hosts tracked in an operation.
+// GetHosts retrieves all hosts seen in an operation.
 //
-// Hosts represent compromised or discovered systems across the network.
-// This is distinct from callbacks - a host may have multiple callbacks
-// or be discovered through reconnaissance before compromise.
+// Mythic has no host table, so hosts are derived from the distinct host values
+// of the operation's callbacks, plus hosts that only appear on files (for
+// example downloads from a host reached through another callback). Each host
+// carries its total and active callback counts, when it was first seen and
+// when a callback on it last checked in.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout
 //   - operationID: ID of the operation (0 for current operation)
 //
 // Returns:
-//   - []*types.HostInfo: List of hosts in the operation
+//   - []*types.HostInfo: List of hosts in the operation, sorted by hostname
 //   - error: Error if operation ID is invalid or query fails
 //
 // Example:
@@ -29,8 +33,8 @@ import (
 //	    return err
 //	}
 //	for _, host := range hosts {
-//	    fmt.Printf("Host: %s (%s) - %d active callbacks\n",
-//	        host.Hostname, host.IP, host.GetCallbackCount())
+//	    fmt.Printf("Host: %s (%s) - %d active callbacks, last seen %s\n",
+//	        host.Hostname, host.IP, host.GetCallbackCount(), host.LastSeen)
 //	}
 func (c *Client) GetHosts(ctx context.Context, operationID int) ([]*types.HostInfo, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -46,8 +50,9 @@ func (c *Client) GetHosts(ctx context.Context, operationID int) ([]*types.HostIn
 		operationID = *currentOp
 	}
 
-	// Mythic has no dedicated "host" table. Hosts are derived from unique
-	// host values in the callback table and enriched with mythictree data.
+	// One row per distinct host value, taken from its most recent callback.
+	// Counts and check-in times come from aggregates so they stay correct no
+	// matter how many callbacks a host has.
 	var query struct {
 		Callback []struct {
 			Host         string `graphql:"host"`
@@ -56,8 +61,11 @@ func (c *Client) GetHosts(ctx context.Context, operationID int) ([]*types.HostIn
 			Architecture string `graphql:"architecture"`
 			IP           string `graphql:"ip"`
 			OperationID  int    `graphql:"operation_id"`
-			LastCheckin  string `graphql:"last_checkin"`
-		} `graphql:"callback(where: {operation_id: {_eq: $operation_id}}, distinct_on: host, order_by: {host: asc})"`
+		} `graphql:"callback(where: {operation_id: {_eq: $operation_id}, host: {_neq: \"\"}}, distinct_on: host, order_by: [{host: asc}, {last_checkin: desc}])"`
+		FileMeta []struct {
+			Host      string `graphql:"host"`
+			Timestamp string `graphql:"timestamp"`
+		} `graphql:"filemeta(where: {operation_id: {_eq: $operation_id}, deleted: {_eq: false}, host: {_neq: \"\"}}, distinct_on: host, order_by: [{host: asc}, {timestamp: asc}])"`
 	}
 
 	variables := map[string]interface{}{
@@ -69,24 +77,141 @@ func (c *Client) GetHosts(ctx context.Context, operationID int) ([]*types.HostIn
 		return nil, WrapError("GetHosts", err, "failed to query hosts")
 	}
 
-	hosts := make([]*types.HostInfo, len(query.Callback))
+	hostNames := make([]string, len(query.Callback))
 	for i, cbData := range query.Callback {
-		ts, _ := parseTimestamp(cbData.LastCheckin)
-		hosts[i] = &types.HostInfo{
-			ID:           i + 1, // synthetic ID since hosts are derived
-			Hostname:     cbData.Host,
-			IP:           cbData.IP,
-			Domain:       cbData.Domain,
-			OS:           cbData.Os,
-			Architecture: cbData.Architecture,
-			OperationID:  cbData.OperationID,
-			Timestamp:    ts,
+		hostNames[i] = cbData.Host
+	}
+	counts, err := c.queryHostCallbackCounts(ctx, operationID, hostNames)
+	if err != nil {
+		return nil, WrapError("GetHosts", err, "failed to query host callback counts")
+	}
+
+	// Host names are reported by agents with inconsistent casing, so they are
+	// grouped case-insensitively. The spelling with the most recent check-in
+	// supplies the host's details.
+	byHost := make(map[string]*types.HostInfo)
+	for i, cbData := range query.Callback {
+		count := counts[i]
+		key := strings.ToUpper(cbData.Host)
+		host, ok := byHost[key]
+		if !ok || count.lastSeen.After(host.LastSeen) {
+			details := &types.HostInfo{
+				Hostname:     cbData.Host,
+				IP:           cbData.IP,
+				Domain:       cbData.Domain,
+				OS:           cbData.Os,
+				Architecture: cbData.Architecture,
+				OperationID:  cbData.OperationID,
+				LastSeen:     count.lastSeen,
+			}
+			if ok {
+				details.CallbackCount = host.CallbackCount
+				details.ActiveCallbackCount = host.ActiveCallbackCount
+				details.Timestamp = host.Timestamp
+			}
+			host = details
+			byHost[key] = host
+		}
+
+		host.CallbackCount += count.total
+		host.ActiveCallbackCount += count.active
+		if !count.firstSeen.IsZero() && (host.Timestamp.IsZero() || count.firstSeen.Before(host.Timestamp)) {
+			host.Timestamp = count.firstSeen
 		}
 	}
 
+	for _, file := range query.FileMeta {
+		ts, _ := parseTimestamp(file.Timestamp) //nolint:errcheck // Timestamp parse errors not critical
+
+		key := strings.ToUpper(file.Host)
+		host, ok := byHost[key]
+		if !ok {
+			byHost[key] = &types.HostInfo{
+				Hostname:    file.Host,
+				OperationID: operationID,
+				Timestamp:   ts,
+				LastSeen:    ts,
+			}
+			continue
+		}
+		if !ts.IsZero() && ts.Before(host.Timestamp) {
+			host.Timestamp = ts
+		}
+	}
+
+	hosts := make([]*types.HostInfo, 0, len(byHost))
+	for _, host := range byHost {
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		return strings.ToUpper(hosts[i].Hostname) < strings.ToUpper(hosts[j].Hostname)
+	})
+	for i, host := range hosts {
+		host.ID = i + 1 // synthetic ID since hosts are derived
+	}
+
 	return hosts, nil
 }
 
+// hostCallbackCounts summarises the callbacks reported for one host value.
+type hostCallbackCounts struct {
+	total     int
+	active    int
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// queryHostCallbackCounts aggregates the callbacks of each host in one request.
+// The result is indexed like hosts.
+func (c *Client) queryHostCallbackCounts(ctx context.Context, operationID int, hosts []string) ([]hostCallbackCounts, error) {
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+
+	var params, fields strings.Builder
+	params.WriteString("$operation_id: Int!")
+	variables := map[string]interface{}{
+		"operation_id": operationID,
+	}
+	for i, host := range hosts {
+		name := fmt.Sprintf("host_%d", i)
+		variables[name] = host
+		fmt.Fprintf(&params, ", $%s: String!", name)
+		fmt.Fprintf(&fields, "h%d_all: callback_aggregate(where: {operation_id: {_eq: $operation_id}, host: {_eq: $%s}}) { aggregate { count min { init_callback } max { last_checkin } } }\n", i, name)
+		fmt.Fprintf(&fields, "h%d_active: callback_aggregate(where: {operation_id: {_eq: $operation_id}, host: {_eq: $%s}, active: {_eq: true}}) { aggregate { count } }\n", i, name)
+	}
+	query := fmt.Sprintf("query HostCallbackCounts(%s) {\n%s}", params.String(), fields.String())
+
+	var aggregates map[string]struct {
+		Aggregate struct {
+			Count int `json:"count"`
+			Min   struct {
+				InitCallback string `json:"init_callback"`
+			} `json:"min"`
+			Max struct {
+				LastCheckin string `json:"last_checkin"`
+			} `json:"max"`
+		} `json:"aggregate"`
+	}
+	if err := c.ExecuteRawGraphQLInto(ctx, query, variables, &aggregates); err != nil {
+		return nil, err
+	}
+
+	counts := make([]hostCallbackCounts, len(hosts))
+	for i := range hosts {
+		all := aggregates[fmt.Sprintf("h%d_all", i)].Aggregate
+		firstSeen, _ := parseTimestamp(all.Min.InitCallback) //nolint:errcheck // Timestamp parse errors not critical
+		lastSeen, _ := parseTimestamp(all.Max.LastCheckin)   //nolint:errcheck // Timestamp parse errors not critical
+		counts[i] = hostCallbackCounts{
+			total:     all.Count,
+			active:    aggregates[fmt.Sprintf("h%d_active", i)].Aggregate.Count,
+			firstSeen: firstSeen,
+			lastSeen:  lastSeen,
+		}
+	}
+	return counts, nil
+}
+
 // GetHostByID retrieves a specific host by its database ID.
 //
 // Parameters:
@@ -2,11 +2,100 @@ package mythic
 
 import (
 	"context"
+	"encoding/base64"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
+// keylogQueryFields is the GraphQL selection shared by keylog queries. keylog
+// has no callback_id column, so the callback is selected through its relationship.
+type keylogQueryFields struct {
+	ID          int       `graphql:"id"`
+	TaskID      int       `graphql:"task_id"`
+	Keystrokes  string    `graphql:"keystrokes"`
+	Window      string    `graphql:"window"`
+	Timestamp   time.Time `graphql:"timestamp"`
+	OperationID int       `graphql:"operation_id"`
+	User        string    `graphql:"user"`
+	Callback    struct {
+		ID        int    `graphql:"id"`
+		DisplayID int    `graphql:"display_id"`
+		Host      string `graphql:"host"`
+		User      string `graphql:"user"`
+	} `graphql:"callback"`
+}
+
+// toKeylog converts the query result into a types.Keylog.
+func (k keylogQueryFields) toKeylog() *types.Keylog {
+	return &types.Keylog{
+		ID:                k.ID,
+		TaskID:            k.TaskID,
+		Keystrokes:        decodeKeystrokes(k.Keystrokes),
+		Window:            k.Window,
+		Timestamp:         k.Timestamp,
+		OperationID:       k.OperationID,
+		User:              k.User,
+		CallbackID:        k.Callback.ID,
+		CallbackDisplayID: k.Callback.DisplayID,
+		Callback: &types.Callback{
+			ID:        k.Callback.ID,
+			DisplayID: k.Callback.DisplayID,
+			Host:      k.Callback.Host,
+			User:      k.Callback.User,
+		},
+	}
+}
+
+// decodeKeystrokes decodes keystrokes stored as base64. The schema does not
+// say whether an agent encoded them, so the decoded value is only used when it
+// is printable text; plaintext that happens to be valid base64, such as
+// "AAAA", is returned unchanged.
+func decodeKeystrokes(raw string) string {
+	decoded, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || !isPrintableText(decoded) {
+		return raw
+	}
+	return string(decoded)
+}
+
+// isPrintableText reports whether b is non-empty UTF-8 made up of printable
+// characters and common whitespace.
+func isPrintableText(b []byte) bool {
+	if len(b) == 0 || !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && r != '\n' && r != '\r' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// KeylogQueryOptions filters SearchKeylogs. Zero-value fields are not applied.
+type KeylogQueryOptions struct {
+	// OperationID restricts results to an operation (0 for the current operation)
+	OperationID int
+
+	// CallbackID restricts results to a callback by its database ID
+	CallbackID int
+
+	// CallbackDisplayID restricts results to a callback by its display ID
+	CallbackDisplayID int
+
+	// Since restricts results to keylogs captured at or after this time
+	Since time.Time
+
+	// Until restricts results to keylogs captured at or before this time
+	Until time.Time
+
+	// Limit is the maximum number of keylogs to return (0 for no limit)
+	Limit int
+}
+
 // GetKeylogs retrieves all keylog entries for the current operation.
 func (c *Client) GetKeylogs(ctx context.Context) ([]*types.Keylog, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -14,18 +103,7 @@ func (c *Client) GetKeylogs(ctx context.Context) ([]*types.Keylog, error) {
 	}
 
 	var query struct {
-		Keylog []struct {
-			ID          int       `graphql:"id"`
-			TaskID      int       `graphql:"task_id"`
-			Keystrokes  string    `graphql:"keystrokes"`
-			Window      string    `graphql:"window"`
-			Timestamp   time.Time `graphql:"timestamp"`
-			OperationID int       `graphql:"operation_id"`
-			User        string    `graphql:"user"`
-			Task        struct {
-				CallbackID int `graphql:"callback_id"`
-			} `graphql:"task"`
-		} `graphql:"keylog(order_by: {timestamp: desc})"`
+		Keylog []keylogQueryFields `graphql:"keylog(order_by: {timestamp: desc})"`
 	}
 
 	err := c.executeQuery(ctx, &query, nil)
@@ -33,21 +111,7 @@ func (c *Client) GetKeylogs(ctx context.Context) ([]*types.Keylog, error) {
 		return nil, WrapError("GetKeylogs", err, "failed to query keylogs")
 	}
 
-	keylogs := make([]*types.Keylog, len(query.Keylog))
-	for i, kl := range query.Keylog {
-		keylogs[i] = &types.Keylog{
-			ID:          kl.ID,
-			TaskID:      kl.TaskID,
-			Keystrokes:  kl.Keystrokes,
-			Window:      kl.Window,
-			Timestamp:   kl.Timestamp,
-			OperationID: kl.OperationID,
-			User:        kl.User,
-			CallbackID:  kl.Task.CallbackID,
-		}
-	}
-
-	return keylogs, nil
+	return toKeylogs(query.Keylog), nil
 }
 
 // GetKeylogsByOperation retrieves keylog entries for a specific operation.
@@ -61,18 +125,7 @@ func (c *Client) GetKeylogsByOperation(ctx context.Context, operationID int) ([]
 	}
 
 	var query struct {
-		Keylog []struct {
-			ID          int       `graphql:"id"`
-			TaskID      int       `graphql:"task_id"`
-			Keystrokes  string    `graphql:"keystrokes"`
-			Window      string    `graphql:"window"`
-			Timestamp   time.Time `graphql:"timestamp"`
-			OperationID int       `graphql:"operation_id"`
-			User        string    `graphql:"user"`
-			Task        struct {
-				CallbackID int `graphql:"callback_id"`
-			} `graphql:"task"`
-		} `graphql:"keylog(where: {operation_id: {_eq: $operation_id}}, order_by: {timestamp: desc})"`
+		Keylog []keylogQueryFields `graphql:"keylog(where: {operation_id: {_eq: $operation_id}}, order_by: {timestamp: desc})"`
 	}
 
 	variables := map[string]interface{}{
@@ -84,21 +137,7 @@ func (c *Client) GetKeylogsByOperation(ctx context.Context, operationID int) ([]
 		return nil, WrapError("GetKeylogsByOperation", err, "failed to query keylogs")
 	}
 
-	keylogs := make([]*types.Keylog, len(query.Keylog))
-	for i, kl := range query.Keylog {
-		keylogs[i] = &types.Keylog{
-			ID:          kl.ID,
-			TaskID:      kl.TaskID,
-			Keystrokes:  kl.Keystrokes,
-			Window:      kl.Window,
-			Timestamp:   kl.Timestamp,
-			OperationID: kl.OperationID,
-			User:        kl.User,
-			CallbackID:  kl.Task.CallbackID,
-		}
-	}
-
-	return keylogs, nil
+	return toKeylogs(query.Keylog), nil
 }
 
 // GetKeylogsByCallback retrieves keylog entries for a specific callback.
@@ -112,18 +151,7 @@ func (c *Client) GetKeylogsByCallback(ctx context.Context, callbackID int) ([]*t
 	}
 
 	var query struct {
-		Keylog []struct {
-			ID          int       `graphql:"id"`
-			TaskID      int       `graphql:"task_id"`
-			Keystrokes  string    `graphql:"keystrokes"`
-			Window      string    `graphql:"window"`
-			Timestamp   time.Time `graphql:"timestamp"`
-			OperationID int       `graphql:"operation_id"`
-			User        string    `graphql:"user"`
-			Task        struct {
-				CallbackID int `graphql:"callback_id"`
-			} `graphql:"task"`
-		} `graphql:"keylog(where: {task: {callback_id: {_eq: $callback_id}}}, order_by: {timestamp: desc})"`
+		Keylog []keylogQueryFields `graphql:"keylog(where: {callback: {id: {_eq: $callback_id}}}, order_by: {timestamp: desc})"`
 	}
 
 	variables := map[string]interface{}{
@@ -135,19 +163,98 @@ func (c *Client) GetKeylogsByCallback(ctx context.Context, callbackID int) ([]*t
 		return nil, WrapError("GetKeylogsByCallback", err, "failed to query keylogs")
 	}
 
-	keylogs := make([]*types.Keylog, len(query.Keylog))
-	for i, kl := range query.Keylog {
-		keylogs[i] = &types.Keylog{
-			ID:          kl.ID,
-			TaskID:      kl.TaskID,
-			Keystrokes:  kl.Keystrokes,
-			Window:      kl.Window,
-			Timestamp:   kl.Timestamp,
-			OperationID: kl.OperationID,
-			User:        kl.User,
-			CallbackID:  kl.Task.CallbackID,
+	return toKeylogs(query.Keylog), nil
+}
+
+// SearchKeylogs retrieves keylog entries matching opts, newest first.
+// Filtering is applied server-side. A nil opts returns every keylog in the
+// current operation.
+func (c *Client) SearchKeylogs(ctx context.Context, opts *KeylogQueryOptions) ([]*types.Keylog, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = &KeylogQueryOptions{}
+	}
+
+	if opts.Limit < 0 || opts.OperationID < 0 || opts.CallbackID < 0 || opts.CallbackDisplayID < 0 {
+		return nil, WrapError("SearchKeylogs", ErrInvalidInput, "IDs and limit cannot be negative")
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && opts.Until.Before(opts.Since) {
+		return nil, WrapError("SearchKeylogs", ErrInvalidInput, "until must not be before since")
+	}
+
+	operationID := opts.OperationID
+	if operationID == 0 {
+		currentOp := c.GetCurrentOperation()
+		if currentOp == nil {
+			return nil, WrapError("SearchKeylogs", ErrNotAuthenticated, "no current operation set")
+		}
+		operationID = *currentOp
+	}
+
+	where := newBoolExp("keylog")
+	where.conds["operation_id"] = map[string]interface{}{"_eq": operationID}
+
+	callback := map[string]interface{}{}
+	if opts.CallbackID > 0 {
+		callback["id"] = map[string]interface{}{"_eq": opts.CallbackID}
+	}
+	if opts.CallbackDisplayID > 0 {
+		callback["display_id"] = map[string]interface{}{"_eq": opts.CallbackDisplayID}
+	}
+	if len(callback) > 0 {
+		where.conds["callback"] = callback
+	}
+
+	// timestamp is stored in UTC without a timezone
+	timestamp := map[string]interface{}{}
+	if !opts.Since.IsZero() {
+		timestamp["_gte"] = opts.Since.UTC().Format(time.RFC3339)
+	}
+	if !opts.Until.IsZero() {
+		timestamp["_lte"] = opts.Until.UTC().Format(time.RFC3339)
+	}
+	if len(timestamp) > 0 {
+		where.conds["timestamp"] = timestamp
+	}
+
+	variables := map[string]interface{}{
+		"where": where,
+	}
+
+	// Hasura has no "unlimited" value for limit, so it is only included when set
+	var rows []keylogQueryFields
+	if opts.Limit > 0 {
+		var query struct {
+			Keylog []keylogQueryFields `graphql:"keylog(where: $where, order_by: {timestamp: desc}, limit: $limit)"`
 		}
+		variables["limit"] = opts.Limit
+
+		if err := c.executeQuery(ctx, &query, variables); err != nil {
+			return nil, WrapError("SearchKeylogs", err, "failed to query keylogs")
+		}
+		rows = query.Keylog
+	} else {
+		var query struct {
+			Keylog []keylogQueryFields `graphql:"keylog(where: $where, order_by: {timestamp: desc})"`
+		}
+
+		if err := c.executeQuery(ctx, &query, variables); err != nil {
+			return nil, WrapError("SearchKeylogs", err, "failed to query keylogs")
+		}
+		rows = query.Keylog
 	}
 
-	return keylogs, nil
+	return toKeylogs(rows), nil
+}
+
+// toKeylogs converts keylog query rows into types.Keylog values.
+func toKeylogs(rows []keylogQueryFields) []*types.Keylog {
+	keylogs := make([]*types.Keylog, len(rows))
+	for i, kl := range rows {
+		keylogs[i] = kl.toKeylog()
+	}
+	return keylogs
 }
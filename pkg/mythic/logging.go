@@ -0,0 +1,119 @@
+package mythic
+
+import (
+	"log"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Logger receives diagnostic events from the client. Messages are formatted
+// as a short event name followed by key=value pairs.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// nopLogger discards every event. It is used when Config.Logger is nil.
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+// stdLogger adapts a standard library logger, prefixing each message with its level.
+type stdLogger struct {
+	l *log.Logger
+}
+
+// NewStdLogger returns a Logger that writes every level to l, or to the
+// standard library's default logger if l is nil.
+func NewStdLogger(l *log.Logger) Logger {
+	if l == nil {
+		l = log.Default()
+	}
+	return stdLogger{l: l}
+}
+
+func (s stdLogger) Debugf(format string, args ...interface{}) { s.l.Printf("DEBUG "+format, args...) }
+func (s stdLogger) Infof(format string, args ...interface{})  { s.l.Printf("INFO "+format, args...) }
+func (s stdLogger) Warnf(format string, args ...interface{})  { s.l.Printf("WARN "+format, args...) }
+func (s stdLogger) Errorf(format string, args ...interface{}) { s.l.Printf("ERROR "+format, args...) }
+
+// logOperation reports a finished GraphQL operation: failures at error level
+// with the raw error, successes at debug level.
+func (c *Client) logOperation(kind, name string, start time.Time, err error) {
+	duration := time.Since(start)
+	if err != nil {
+		c.logger.Errorf("mythic: graphql_error op=%s name=%s duration=%s error=%q", kind, name, duration, err.Error())
+		return
+	}
+	c.logger.Debugf("mythic: graphql_ok op=%s name=%s duration=%s", kind, name, duration)
+}
+
+// logQueryText reports the query about to be sent at debug level. Building the
+// text is skipped entirely when logging is disabled.
+func (c *Client) logQueryText(kind, name string, build func() (string, error)) {
+	if _, off := c.logger.(nopLogger); off {
+		return
+	}
+	text, err := build()
+	if err != nil {
+		return
+	}
+	c.logger.Debugf("mythic: graphql_request op=%s name=%s query=%q", kind, name, text)
+}
+
+// operationName names a typed query or mutation after its top-level fields,
+// e.g. "callback" or "task,response_aggregate".
+func operationName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return "unknown"
+	}
+
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := field.Tag.Get("graphql")
+		if name == "" {
+			name = field.Name
+		}
+		if paren := strings.Index(name, "("); paren >= 0 {
+			name = name[:paren]
+		}
+		if colon := strings.LastIndex(name, ":"); colon >= 0 {
+			name = name[colon+1:]
+		}
+		names = append(names, strings.TrimSpace(name))
+	}
+	if len(names) == 0 {
+		return "unknown"
+	}
+	return strings.Join(names, ",")
+}
+
+// rawOperationRegex matches the operation keyword and optional name that open
+// a GraphQL document.
+var rawOperationRegex = regexp.MustCompile(`^\s*(query|mutation|subscription)\b\s*([_A-Za-z][_0-9A-Za-z]*)?`)
+
+// rawOperationName returns the operation kind and name of a raw GraphQL
+// document. Shorthand queries ("{ ... }") and unnamed operations are reported
+// as "anonymous".
+func rawOperationName(query string) (kind, name string) {
+	m := rawOperationRegex.FindStringSubmatch(query)
+	if m == nil {
+		return "query", "anonymous"
+	}
+	if m[2] == "" {
+		return m[1], "anonymous"
+	}
+	return m[1], m[2]
+}
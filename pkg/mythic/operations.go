@@ -217,25 +217,22 @@ func (c *Client) UpdateOperation(ctx context.Context, req *types.UpdateOperation
 		}
 	}`, strings.Join(varDecls, ", "), strings.Join(argParts, ", "))
 
-	result, err := c.ExecuteRawGraphQL(ctx, query, variables)
-	if err != nil {
+	var result struct {
+		UpdateOperation *rawStatusResult `json:"updateOperation"`
+	}
+	if err := c.ExecuteRawGraphQLInto(ctx, query, variables, &result); err != nil {
 		if strings.Contains(err.Error(), "field 'updateOperation' not found") {
 			return c.updateOperationByPKFallback(ctx, req)
 		}
 		return nil, WrapError("UpdateOperation", err, "failed to update operation")
 	}
 
-	if updateResult, ok := result["updateOperation"].(map[string]interface{}); ok {
-		if status, ok := updateResult["status"].(string); ok && status != "success" {
-			errMsg := ""
-			if e, ok := updateResult["error"].(string); ok {
-				errMsg = e
-			}
-			return nil, WrapError("UpdateOperation", ErrOperationFailed, errMsg)
-		}
-	} else {
+	if result.UpdateOperation == nil {
 		return c.updateOperationByPKFallback(ctx, req)
 	}
+	if result.UpdateOperation.failed() {
+		return nil, WrapError("UpdateOperation", ErrOperationFailed, result.UpdateOperation.Error)
+	}
 
 	// Fetch the updated operation
 	return c.GetOperationByID(ctx, req.OperationID)
@@ -272,16 +269,20 @@ func (c *Client) updateOperationByPKFallback(ctx context.Context, req *types.Upd
 		}
 	}`
 
-	result, err := c.ExecuteRawGraphQL(ctx, query, map[string]interface{}{
+	var result struct {
+		UpdateOperationByPK *struct {
+			ID int `json:"id"`
+		} `json:"update_operation_by_pk"`
+	}
+	err := c.ExecuteRawGraphQLInto(ctx, query, map[string]interface{}{
 		"operation_id": req.OperationID,
 		"set":          setFields,
-	})
+	}, &result)
 	if err != nil {
 		return nil, WrapError("UpdateOperation", err, "failed to update operation via update_operation_by_pk fallback")
 	}
 
-	node, exists := result["update_operation_by_pk"]
-	if !exists || node == nil {
+	if result.UpdateOperationByPK == nil {
 		return nil, WrapError("UpdateOperation", ErrNotFound, fmt.Sprintf("operation %d not found", req.OperationID))
 	}
 
@@ -457,19 +458,15 @@ func (c *Client) UpdateOperatorOperation(ctx context.Context, req *types.UpdateO
 		}
 	}`
 
-	result, err := c.ExecuteRawGraphQL(ctx, query, variables)
-	if err != nil {
+	var result struct {
+		UpdateOperatorOperation *rawStatusResult `json:"updateOperatorOperation"`
+	}
+	if err := c.ExecuteRawGraphQLInto(ctx, query, variables, &result); err != nil {
 		return WrapError("UpdateOperatorOperation", err, "failed to update operator in operation")
 	}
 
-	if updateResult, ok := result["updateOperatorOperation"].(map[string]interface{}); ok {
-		if status, ok := updateResult["status"].(string); ok && status != "success" {
-			errMsg := ""
-			if e, ok := updateResult["error"].(string); ok {
-				errMsg = e
-			}
-			return WrapError("UpdateOperatorOperation", ErrOperationFailed, errMsg)
-		}
+	if result.UpdateOperatorOperation != nil && result.UpdateOperatorOperation.failed() {
+		return WrapError("UpdateOperatorOperation", ErrOperationFailed, result.UpdateOperatorOperation.Error)
 	}
 
 	return nil
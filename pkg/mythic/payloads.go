@@ -5,68 +5,152 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
+	"strings"
 	"time"
 
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
-// GetPayloads retrieves all payloads.
+// payloadQueryFields is the GraphQL selection used when listing payloads.
+type payloadQueryFields struct {
+	ID             int    `graphql:"id"`
+	UUID           string `graphql:"uuid"`
+	Description    string `graphql:"description"`
+	OperatorID     int    `graphql:"operator_id"`
+	OperationID    int    `graphql:"operation_id"`
+	CreationTime   string `graphql:"creation_time"`
+	PayloadTypeID  int    `graphql:"payload_type_id"`
+	OS             string `graphql:"os"`
+	BuildContainer string `graphql:"build_container"`
+	BuildPhase     string `graphql:"build_phase"`
+	BuildMessage   string `graphql:"build_message"`
+	Deleted        bool   `graphql:"deleted"`
+	PayloadType    struct {
+		ID            int    `graphql:"id"`
+		Name          string `graphql:"name"`
+		FileExtension string `graphql:"file_extension"`
+	} `graphql:"payloadtype"`
+}
+
+// toPayload converts the query result into a types.Payload.
+func (p payloadQueryFields) toPayload() *types.Payload {
+	creationTime, _ := parseTime(p.CreationTime) //nolint:errcheck // Timestamp parse errors not critical
+	return &types.Payload{
+		ID:             p.ID,
+		UUID:           p.UUID,
+		Description:    p.Description,
+		OperatorID:     p.OperatorID,
+		OperationID:    p.OperationID,
+		CreationTime:   creationTime,
+		PayloadTypeID:  p.PayloadTypeID,
+		OS:             p.OS,
+		BuildContainer: p.BuildContainer,
+		BuildPhase:     p.BuildPhase,
+		BuildMessage:   p.BuildMessage,
+		Deleted:        p.Deleted,
+		TagStr:         "", // tag field not available in schema
+		PayloadType: &types.PayloadType{
+			ID:            p.PayloadType.ID,
+			Name:          p.PayloadType.Name,
+			FileExtension: p.PayloadType.FileExtension,
+		},
+	}
+}
+
+// PayloadQueryOptions filters and paginates SearchPayloads. Zero-value fields
+// are not applied.
+type PayloadQueryOptions struct {
+	// Deleted restricts results to deleted (true) or live (false) payloads; nil returns both
+	Deleted *bool
+
+	// BuildPhase matches the build phase exactly (e.g. "success", "building", "error")
+	BuildPhase string
+
+	// PayloadType matches the payload type name (e.g. "poseidon")
+	PayloadType string
+
+	// OS matches the selected OS case-insensitively (e.g. "linux")
+	OS string
+
+	// Limit is the maximum number of payloads to return (0 for no limit)
+	Limit int
+}
+
+// GetPayloads retrieves all payloads, including deleted ones.
 func (c *Client) GetPayloads(ctx context.Context) ([]*types.Payload, error) {
+	return c.SearchPayloads(ctx, nil)
+}
+
+// SearchPayloads retrieves payloads matching opts, newest first. Filtering is
+// applied server-side. A nil opts returns every payload.
+//
+// Example:
+//
+//	live := false
+//	payloads, err := client.SearchPayloads(ctx, &mythic.PayloadQueryOptions{
+//	    Deleted:     &live,
+//	    BuildPhase:  "success",
+//	    PayloadType: "poseidon",
+//	})
+func (c *Client) SearchPayloads(ctx context.Context, opts *PayloadQueryOptions) ([]*types.Payload, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
-	var query struct {
-		Payload []struct {
-			ID             int    `graphql:"id"`
-			UUID           string `graphql:"uuid"`
-			Description    string `graphql:"description"`
-			OperatorID     int    `graphql:"operator_id"`
-			OperationID    int    `graphql:"operation_id"`
-			CreationTime   string `graphql:"creation_time"`
-			PayloadTypeID  int    `graphql:"payload_type_id"`
-			OS             string `graphql:"os"`
-			BuildContainer string `graphql:"build_container"`
-			BuildPhase     string `graphql:"build_phase"`
-			BuildMessage   string `graphql:"build_message"`
-			Deleted        bool   `graphql:"deleted"`
-			PayloadType    struct {
-				ID            int    `graphql:"id"`
-				Name          string `graphql:"name"`
-				FileExtension string `graphql:"file_extension"`
-			} `graphql:"payloadtype"`
-		} `graphql:"payload(order_by: {id: desc})"`
+	if opts == nil {
+		opts = &PayloadQueryOptions{}
 	}
 
-	err := c.executeQuery(ctx, &query, nil)
-	if err != nil {
-		return nil, WrapError("GetPayloads", err, "failed to query payloads")
-	}
-
-	payloads := make([]*types.Payload, len(query.Payload))
-	for i, p := range query.Payload {
-		creationTime, _ := parseTime(p.CreationTime) //nolint:errcheck // Timestamp parse errors not critical
-		payloads[i] = &types.Payload{
-			ID:             p.ID,
-			UUID:           p.UUID,
-			Description:    p.Description,
-			OperatorID:     p.OperatorID,
-			OperationID:    p.OperationID,
-			CreationTime:   creationTime,
-			PayloadTypeID:  p.PayloadTypeID,
-			OS:             p.OS,
-			BuildContainer: p.BuildContainer,
-			BuildPhase:     p.BuildPhase,
-			BuildMessage:   p.BuildMessage,
-			Deleted:        p.Deleted,
-			TagStr:         "", // tag field not available in schema
-			PayloadType: &types.PayloadType{
-				ID:            p.PayloadType.ID,
-				Name:          p.PayloadType.Name,
-				FileExtension: p.PayloadType.FileExtension,
-			},
+	if opts.Limit < 0 {
+		return nil, WrapError("SearchPayloads", ErrInvalidInput, "limit cannot be negative")
+	}
+
+	where := newBoolExp("payload")
+	if opts.Deleted != nil {
+		where.conds["deleted"] = map[string]interface{}{"_eq": *opts.Deleted}
+	}
+	if opts.BuildPhase != "" {
+		where.conds["build_phase"] = map[string]interface{}{"_eq": opts.BuildPhase}
+	}
+	if opts.PayloadType != "" {
+		where.conds["payloadtype"] = map[string]interface{}{
+			"name": map[string]interface{}{"_eq": opts.PayloadType},
+		}
+	}
+	if opts.OS != "" {
+		where.conds["os"] = map[string]interface{}{"_ilike": escapeLikePattern(opts.OS)}
+	}
+
+	variables := map[string]interface{}{
+		"where": where,
+	}
+
+	// Hasura has no "unlimited" value for limit, so it is only included when set
+	var rows []payloadQueryFields
+	if opts.Limit > 0 {
+		var query struct {
+			Payload []payloadQueryFields `graphql:"payload(where: $where, order_by: {id: desc}, limit: $limit)"`
+		}
+		variables["limit"] = opts.Limit
+
+		if err := c.executeQuery(ctx, &query, variables); err != nil {
+			return nil, WrapError("SearchPayloads", err, "failed to query payloads")
+		}
+		rows = query.Payload
+	} else {
+		var query struct {
+			Payload []payloadQueryFields `graphql:"payload(where: $where, order_by: {id: desc})"`
+		}
+
+		if err := c.executeQuery(ctx, &query, variables); err != nil {
+			return nil, WrapError("SearchPayloads", err, "failed to query payloads")
 		}
+		rows = query.Payload
+	}
+
+	payloads := make([]*types.Payload, len(rows))
+	for i, p := range rows {
+		payloads[i] = p.toPayload()
 	}
 
 	return payloads, nil
@@ -168,6 +252,11 @@ func normalizeOS(os string) string {
 }
 
 // CreatePayload builds a new payload.
+//
+// When req.Validate is set the request is checked with ValidatePayloadRequest
+// before it is submitted, so a build missing required build or C2 parameters
+// fails immediately with ErrInvalidInput naming every missing parameter.
+// Otherwise Mythic validates the build itself.
 func (c *Client) CreatePayload(ctx context.Context, req *types.CreatePayloadRequest) (*types.Payload, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
@@ -177,6 +266,17 @@ func (c *Client) CreatePayload(ctx context.Context, req *types.CreatePayloadRequ
 		return nil, WrapError("CreatePayload", ErrInvalidInput, "payload type is required")
 	}
 
+	var p2pProfiles map[string]bool
+	if req.Validate {
+		var err error
+		p2pProfiles, err = c.validatePayloadRequest(ctx, "CreatePayload", req)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		p2pProfiles = c.lookupP2PProfiles(ctx, req.C2Profiles)
+	}
+
 	// Determine selected_os with proper normalization
 	selectedOS := req.SelectedOS
 	if selectedOS == "" && req.OS != "" {
@@ -215,7 +315,7 @@ func (c *Client) CreatePayload(ctx context.Context, req *types.CreatePayloadRequ
 		for i, c2 := range req.C2Profiles {
 			c2Profiles[i] = map[string]interface{}{
 				"c2_profile":            c2.Name,
-				"c2_profile_is_p2p":     false, // Assume not P2P by default
+				"c2_profile_is_p2p":     p2pProfiles[c2.Name],
 				"c2_profile_parameters": c2.Parameters,
 			}
 		}
@@ -267,6 +367,123 @@ func (c *Client) CreatePayload(ctx context.Context, req *types.CreatePayloadRequ
 	return c.GetPayloadByUUID(ctx, mutation.CreatePayload.UUID)
 }
 
+// ValidatePayloadRequest checks a payload build request against the server's
+// definitions without building anything. It verifies that the payload type
+// exists, that it supports every selected C2 profile, and that every required
+// build and C2 profile parameter is provided. Parameters that have a default,
+// or that Mythic generates itself (randomized and crypto parameters), may be
+// omitted.
+//
+// Missing parameters are reported together in a single ErrInvalidInput error,
+// for example "missing required parameters: build[mode]; c2 http[callback_host]".
+func (c *Client) ValidatePayloadRequest(ctx context.Context, req *types.CreatePayloadRequest) error {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return err
+	}
+
+	if req == nil || req.PayloadType == "" {
+		return WrapError("ValidatePayloadRequest", ErrInvalidInput, "payload type is required")
+	}
+
+	_, err := c.validatePayloadRequest(ctx, "ValidatePayloadRequest", req)
+	return err
+}
+
+// validatePayloadRequest implements ValidatePayloadRequest, returning which of
+// the selected C2 profiles are peer-to-peer.
+func (c *Client) validatePayloadRequest(ctx context.Context, op string, req *types.CreatePayloadRequest) (map[string]bool, error) {
+	payloadTypes, err := c.GetPayloadTypes(ctx)
+	if err != nil {
+		return nil, WrapError(op, err, "failed to look up payload type")
+	}
+	var payloadType *types.PayloadType
+	for _, pt := range payloadTypes {
+		if pt.Name == req.PayloadType {
+			payloadType = pt
+			break
+		}
+	}
+	if payloadType == nil {
+		return nil, WrapError(op, ErrNotFound, fmt.Sprintf("payload type %q not found", req.PayloadType))
+	}
+
+	var missing []string
+
+	buildParams, err := c.GetBuildParametersByPayloadType(ctx, payloadType.ID)
+	if err != nil {
+		return nil, WrapError(op, err, "failed to look up build parameters")
+	}
+	var missingBuild []string
+	for _, param := range buildParams {
+		if _, ok := req.BuildParameters[param.Name]; ok {
+			continue
+		}
+		if param.Required && param.DefaultValue == "" && !param.Randomize && !param.IsCryptoType {
+			missingBuild = append(missingBuild, param.Name)
+		}
+	}
+	if len(missingBuild) > 0 {
+		missing = append(missing, fmt.Sprintf("build[%s]", strings.Join(missingBuild, ", ")))
+	}
+
+	supported := make(map[string]bool, len(payloadType.SupportedC2Profiles))
+	for _, name := range payloadType.SupportedC2Profiles {
+		supported[name] = true
+	}
+
+	p2pProfiles := make(map[string]bool, len(req.C2Profiles))
+	for _, c2 := range req.C2Profiles {
+		if !supported[c2.Name] {
+			return nil, WrapError(op, ErrInvalidInput,
+				fmt.Sprintf("payload type %q does not support C2 profile %q", req.PayloadType, c2.Name))
+		}
+
+		profile, err := c.GetC2ProfileByName(ctx, c2.Name)
+		if err != nil {
+			return nil, WrapError(op, err, fmt.Sprintf("failed to look up C2 profile %q", c2.Name))
+		}
+		p2pProfiles[c2.Name] = profile.IsP2P
+
+		c2Params, err := c.GetC2ProfileParameters(ctx, profile.ID)
+		if err != nil {
+			return nil, WrapError(op, err, fmt.Sprintf("failed to look up %s parameters", c2.Name))
+		}
+		var missingC2 []string
+		for _, param := range c2Params {
+			if _, ok := c2.Parameters[param.Name]; ok {
+				continue
+			}
+			if param.Required && param.DefaultValue == "" && !param.Randomize && !param.IsCryptoType {
+				missingC2 = append(missingC2, param.Name)
+			}
+		}
+		if len(missingC2) > 0 {
+			missing = append(missing, fmt.Sprintf("c2 %s[%s]", c2.Name, strings.Join(missingC2, ", ")))
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, WrapError(op, ErrInvalidInput, "missing required parameters: "+strings.Join(missing, "; "))
+	}
+
+	return p2pProfiles, nil
+}
+
+// lookupP2PProfiles reports which of the selected C2 profiles are P2P.
+// Profiles that cannot be looked up are treated as not P2P so the build is
+// still submitted.
+func (c *Client) lookupP2PProfiles(ctx context.Context, profiles []types.C2ProfileConfig) map[string]bool {
+	p2pProfiles := make(map[string]bool, len(profiles))
+	for _, c2 := range profiles {
+		profile, err := c.GetC2ProfileByName(ctx, c2.Name)
+		if err != nil {
+			continue
+		}
+		p2pProfiles[c2.Name] = profile.IsP2P
+	}
+	return p2pProfiles
+}
+
 // UpdatePayload updates payload settings.
 func (c *Client) UpdatePayload(ctx context.Context, req *types.UpdatePayloadRequest) (*types.Payload, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -283,21 +500,49 @@ func (c *Client) UpdatePayload(ctx context.Context, req *types.UpdatePayloadRequ
 		return nil, WrapError("UpdatePayload", ErrInvalidInput, "currently only description field updates are supported")
 	}
 
-	// Note: update_payload GraphQL mutation not available in all Mythic versions
-	// For now, skip the update and just return the current payload
-	// In a full implementation, this would use a REST webhook endpoint
-	_, err := c.GetPayloadByUUID(ctx, req.UUID)
-	if err != nil {
-		return nil, WrapError("UpdatePayload", err, "failed to verify payload exists")
+	if err := c.UpdatePayloadDescription(ctx, req.UUID, *req.Description); err != nil {
+		return nil, err
 	}
 
-	// Update not performed - would need REST API endpoint
-
 	// Fetch the updated payload
 	return c.GetPayloadByUUID(ctx, req.UUID)
 }
 
-// DeletePayload marks a payload as deleted.
+// UpdatePayloadDescription sets the description of the payload with the given UUID.
+func (c *Client) UpdatePayloadDescription(ctx context.Context, uuid, description string) error {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return err
+	}
+
+	if uuid == "" {
+		return WrapError("UpdatePayloadDescription", ErrInvalidInput, "UUID is required")
+	}
+
+	var mutation struct {
+		UpdatePayload struct {
+			Affected int `graphql:"affected_rows"`
+		} `graphql:"update_payload(where: {uuid: {_eq: $uuid}}, _set: {description: $description})"`
+	}
+
+	variables := map[string]interface{}{
+		"uuid":        uuid,
+		"description": description,
+	}
+
+	err := c.executeMutation(ctx, &mutation, variables)
+	if err != nil {
+		return WrapError("UpdatePayloadDescription", err, "failed to update payload description")
+	}
+
+	if mutation.UpdatePayload.Affected == 0 {
+		return WrapError("UpdatePayloadDescription", ErrNotFound, fmt.Sprintf("payload %s not found", uuid))
+	}
+
+	return nil
+}
+
+// DeletePayload marks a payload as deleted. The payload and its file are kept
+// in Mythic but no longer show up in the default payload views.
 func (c *Client) DeletePayload(ctx context.Context, uuid string) error {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return err
@@ -307,15 +552,25 @@ func (c *Client) DeletePayload(ctx context.Context, uuid string) error {
 		return WrapError("DeletePayload", ErrInvalidInput, "UUID is required")
 	}
 
-	// Note: Payload deletion via GraphQL API is not supported in current schema
-	// UpdatePayload doesn't support the Deleted field
-	// Verify payload exists but don't actually delete it
-	_, err := c.GetPayloadByUUID(ctx, uuid)
+	var mutation struct {
+		UpdatePayload struct {
+			Affected int `graphql:"affected_rows"`
+		} `graphql:"update_payload(where: {uuid: {_eq: $uuid}}, _set: {deleted: true})"`
+	}
+
+	variables := map[string]interface{}{
+		"uuid": uuid,
+	}
+
+	err := c.executeMutation(ctx, &mutation, variables)
 	if err != nil {
-		return WrapError("DeletePayload", err, "failed to verify payload exists")
+		return WrapError("DeletePayload", err, "failed to delete payload")
+	}
+
+	if mutation.UpdatePayload.Affected == 0 {
+		return WrapError("DeletePayload", ErrNotFound, fmt.Sprintf("payload %s not found", uuid))
 	}
 
-	// Would need REST API endpoint or admin panel for actual deletion
 	return nil
 }
 
@@ -532,48 +787,91 @@ func (c *Client) GetPayloadOnHost(ctx context.Context, operationID int) ([]*type
 
 // WaitForPayloadComplete waits for a payload to finish building.
 // It polls the payload status until it's ready, failed, or the timeout is reached.
-// timeout is in seconds.
+// timeout is in seconds. Use WaitForPayloadBuild to also get the final payload.
 func (c *Client) WaitForPayloadComplete(ctx context.Context, uuid string, timeout int) error {
+	_, err := c.WaitForPayloadBuild(ctx, uuid, timeout)
+	return err
+}
+
+// WaitForPayloadBuild polls a payload until its build_phase becomes "success"
+// or "error", backing off between checks, and returns the payload so it can be
+// downloaded. timeout is in seconds (default 300).
+//
+// A failed build returns the payload with ErrTaskFailed carrying the build
+// message and any build output. On timeout or context cancellation the last
+// payload seen is returned with the error, and the timeout error names its
+// build_phase.
+func (c *Client) WaitForPayloadBuild(ctx context.Context, uuid string, timeout int) (*types.Payload, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
 	if uuid == "" {
-		return WrapError("WaitForPayloadComplete", ErrInvalidInput, "UUID is required")
+		return nil, WrapError("WaitForPayloadBuild", ErrInvalidInput, "UUID is required")
 	}
 
-	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	if timeout <= 0 {
+		timeout = 300 // Default 5 minutes
+	}
 
-	for time.Now().Before(deadline) {
-		payload, err := c.GetPayloadByUUID(ctx, uuid)
-		if err != nil {
-			return WrapError("WaitForPayloadComplete", err, "failed to check payload status")
-		}
+	cfg := DefaultPollConfig()
+	deadline := time.After(time.Duration(timeout) * time.Second)
 
-		if payload.IsReady() {
-			return nil
-		}
+	interval := cfg.InitialInterval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
-		if payload.IsFailed() {
-			// Collect all available error details
-			errDetails := payload.BuildMessage
-			if payload.BuildStderr != "" {
-				errDetails += "\nStderr: " + payload.BuildStderr
+	var payload *types.Payload
+	for {
+		select {
+		case <-deadline:
+			phase := "unknown"
+			if payload != nil {
+				phase = payload.BuildPhase
 			}
-			if payload.BuildStdout != "" {
-				errDetails += "\nStdout: " + payload.BuildStdout
+			return payload, WrapError("WaitForPayloadBuild", ErrTimeout,
+				fmt.Sprintf("payload %s did not finish building within %ds (last build_phase: %s)", uuid, timeout, phase))
+		case <-ctx.Done():
+			return payload, ctx.Err()
+		case <-timer.C:
+			current, err := c.GetPayloadByUUID(ctx, uuid)
+			if err != nil {
+				return payload, WrapError("WaitForPayloadBuild", err, "failed to check payload status")
 			}
-			return WrapError("WaitForPayloadComplete", ErrTaskFailed, fmt.Sprintf("payload build failed: %s", errDetails))
-		}
+			payload = current
 
-		// Wait before polling again
-		time.Sleep(2 * time.Second)
-	}
+			if payload.IsReady() {
+				return payload, nil
+			}
+
+			if payload.IsFailed() {
+				// Collect all available error details
+				errDetails := payload.BuildMessage
+				if payload.BuildStderr != "" {
+					errDetails += "\nStderr: " + payload.BuildStderr
+				}
+				if payload.BuildStdout != "" {
+					errDetails += "\nStdout: " + payload.BuildStdout
+				}
+				return payload, WrapError("WaitForPayloadBuild", ErrTaskFailed, fmt.Sprintf("payload build failed: %s", errDetails))
+			}
 
-	return WrapError("WaitForPayloadComplete", ErrTimeout, "timeout waiting for payload to complete")
+			// Back off before the next check, capped at the max interval
+			timer.Reset(interval)
+			interval = time.Duration(float64(interval) * cfg.Multiplier)
+			if interval > cfg.MaxInterval {
+				interval = cfg.MaxInterval
+			}
+		}
+	}
 }
 
 // DownloadPayload downloads the payload binary.
+//
+// The built payload is stored as a Mythic file, so its agent_file_id is
+// looked up with GetPayloadFileID and the content fetched through the file
+// download endpoint, the same way DownloadFile does. Use DownloadFileStream
+// with GetPayloadFileID to stream large payloads instead.
 func (c *Client) DownloadPayload(ctx context.Context, uuid string) ([]byte, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
@@ -583,46 +881,64 @@ func (c *Client) DownloadPayload(ctx context.Context, uuid string) ([]byte, erro
 		return nil, WrapError("DownloadPayload", ErrInvalidInput, "UUID is required")
 	}
 
-	// Construct download URL
-	scheme := "https"
-	if !c.config.SSL {
-		scheme = "http"
+	agentFileID, err := c.GetPayloadFileID(ctx, uuid)
+	if err != nil {
+		return nil, WrapError("DownloadPayload", err, "failed to resolve payload file")
+	}
+
+	body, err := c.openFileDownload(ctx, "DownloadPayload", agentFileID)
+	if err != nil {
+		return nil, err
 	}
-	downloadURL := fmt.Sprintf("%s://%s/direct/download/%s", scheme, stripScheme(c.config.ServerURL), uuid)
+	defer body.Close() //nolint:errcheck // Response body close error not critical
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
+	data, err := io.ReadAll(body)
 	if err != nil {
-		return nil, WrapError("DownloadPayload", err, "failed to create request")
+		return nil, WrapError("DownloadPayload", err, "failed to read payload data")
 	}
 
-	// Add authentication headers
-	headers := c.getAuthHeaders()
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	return data, nil
+}
+
+// GetPayloadFileID returns the agent_file_id of a built payload's file, for
+// use with the file download functions. It returns ErrNotFound if the payload
+// does not exist or has no file yet, for example while it is still building.
+func (c *Client) GetPayloadFileID(ctx context.Context, uuid string) (string, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return "", err
 	}
 
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, WrapError("DownloadPayload", err, "failed to download payload")
+	if uuid == "" {
+		return "", WrapError("GetPayloadFileID", ErrInvalidInput, "UUID is required")
 	}
-	defer resp.Body.Close() //nolint:errcheck // Response body close error not critical
 
-	if resp.StatusCode != http.StatusOK {
-		body, readErr := io.ReadAll(resp.Body)
-		errMsg := fmt.Sprintf("HTTP %d", resp.StatusCode)
-		if readErr == nil && len(body) > 0 {
-			errMsg = fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body))
-		}
-		return nil, WrapError("DownloadPayload", ErrInvalidResponse, errMsg)
+	var query struct {
+		Payload []struct {
+			BuildPhase string `graphql:"build_phase"`
+			FileMeta   *struct {
+				AgentFileID string `graphql:"agent_file_id"`
+			} `graphql:"filemetum"`
+		} `graphql:"payload(where: {uuid: {_eq: $uuid}}, limit: 1)"`
 	}
 
-	// Read payload data
-	data, err := io.ReadAll(resp.Body)
+	variables := map[string]interface{}{
+		"uuid": uuid,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
 	if err != nil {
-		return nil, WrapError("DownloadPayload", err, "failed to read payload data")
+		return "", WrapError("GetPayloadFileID", err, "failed to query payload file")
 	}
 
-	return data, nil
+	if len(query.Payload) == 0 {
+		return "", WrapError("GetPayloadFileID", ErrNotFound, fmt.Sprintf("payload %s not found", uuid))
+	}
+
+	payload := query.Payload[0]
+	if payload.FileMeta == nil || payload.FileMeta.AgentFileID == "" {
+		return "", WrapError("GetPayloadFileID", ErrNotFound,
+			fmt.Sprintf("payload %s has no file (build_phase: %s)", uuid, payload.BuildPhase))
+	}
+
+	return payload.FileMeta.AgentFileID, nil
 }
@@ -3,6 +3,8 @@ package mythic
 import (
 	"context"
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
 	"time"
 
@@ -41,13 +43,7 @@ func (c *Client) GetResponsesByTask(ctx context.Context, taskID int) ([]*types.R
 	}
 
 	var query struct {
-		Response []struct {
-			ID             int    `graphql:"id"`
-			Response       string `graphql:"response_text"`
-			Timestamp      string `graphql:"timestamp"`
-			TaskID         int    `graphql:"task_id"`
-			SequenceNumber *int   `graphql:"sequence_number"`
-		} `graphql:"response(where: {task_id: {_eq: $task_id}}, order_by: {id: asc})"`
+		Response []responseQueryFields `graphql:"response(where: {task_id: {_eq: $task_id}}, order_by: {id: asc})"`
 	}
 
 	variables := map[string]interface{}{
@@ -60,20 +56,8 @@ func (c *Client) GetResponsesByTask(ctx context.Context, taskID int) ([]*types.R
 	}
 
 	responses := make([]*types.Response, len(query.Response))
-	for i, resp := range query.Response {
-		// Parse timestamp - Mythic v3.4.20 returns timestamps without timezone
-		timestamp, err := parseTimestamp(resp.Timestamp)
-		if err != nil {
-			timestamp = time.Time{}
-		}
-
-		responses[i] = &types.Response{
-			ID:             resp.ID,
-			Response:       resp.Response,
-			Timestamp:      timestamp,
-			TaskID:         resp.TaskID,
-			SequenceNumber: resp.SequenceNumber,
-		}
+	for i := range query.Response {
+		responses[i] = query.Response[i].toResponse()
 	}
 
 	return responses, nil
@@ -106,19 +90,7 @@ func (c *Client) GetResponseByID(ctx context.Context, responseID int) (*types.Re
 	}
 
 	var query struct {
-		Response []struct {
-			ID             int    `graphql:"id"`
-			Response       string `graphql:"response_text"`
-			Timestamp      string `graphql:"timestamp"`
-			TaskID         int    `graphql:"task_id"`
-			SequenceNumber *int   `graphql:"sequence_number"`
-			Task           struct {
-				ID          int    `graphql:"id"`
-				CommandName string `graphql:"command_name"`
-				Status      string `graphql:"status"`
-				CallbackID  int    `graphql:"callback_id"`
-			} `graphql:"task"`
-		} `graphql:"response(where: {id: {_eq: $response_id}})"`
+		Response []responseQueryFields `graphql:"response(where: {id: {_eq: $response_id}})"`
 	}
 
 	variables := map[string]interface{}{
@@ -134,23 +106,126 @@ func (c *Client) GetResponseByID(ctx context.Context, responseID int) (*types.Re
 		return nil, WrapError("GetResponseByID", ErrNotFound, fmt.Sprintf("response %d not found", responseID))
 	}
 
-	resp := query.Response[0]
+	return query.Response[0].toResponse(), nil
+}
+
+// maxResponseIDsPerQuery bounds the size of the id _in list GetResponsesByIDs
+// sends in a single query.
+const maxResponseIDsPerQuery = 500
+
+// GetResponsesByIDs retrieves multiple responses by ID.
+//
+// The IDs are fetched with id _in queries of at most 500 IDs each rather than
+// one round trip per response. The result follows the order of ids; IDs that
+// don't exist are skipped.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout
+//   - ids: IDs of the responses to retrieve
+//
+// Returns:
+//   - []*types.Response: Responses in the requested order
+//   - error: Error if ids is empty or a query fails
+//
+// Example:
+//
+//	responses, err := client.GetResponsesByIDs(ctx, []int{123, 456, 789})
+//	if err != nil {
+//	    return err
+//	}
+//	for _, resp := range responses {
+//	    fmt.Printf("Response %d: %s\n", resp.ID, resp.Response)
+//	}
+func (c *Client) GetResponsesByIDs(ctx context.Context, ids []int) ([]*types.Response, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, WrapError("GetResponsesByIDs", ErrInvalidInput, "at least one response ID is required")
+	}
+
+	// Query each distinct ID once
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	found := make(map[int]*types.Response, len(unique))
+	for start := 0; start < len(unique); start += maxResponseIDsPerQuery {
+		end := start + maxResponseIDsPerQuery
+		if end > len(unique) {
+			end = len(unique)
+		}
+
+		var query struct {
+			Response []responseQueryFields `graphql:"response(where: {id: {_in: $response_ids}})"`
+		}
+
+		variables := map[string]interface{}{
+			"response_ids": unique[start:end],
+		}
+
+		err := c.executeQuery(ctx, &query, variables)
+		if err != nil {
+			return nil, WrapError("GetResponsesByIDs", err, "failed to query responses")
+		}
+
+		for i := range query.Response {
+			found[query.Response[i].ID] = query.Response[i].toResponse()
+		}
+	}
+
+	responses := make([]*types.Response, 0, len(found))
+	for _, id := range ids {
+		if resp, ok := found[id]; ok {
+			responses = append(responses, resp)
+		}
+	}
+
+	return responses, nil
+}
+
+// responseQueryFields is the set of response fields, with task details,
+// shared by the response queries.
+type responseQueryFields struct {
+	ID             int    `graphql:"id"`
+	Response       string `graphql:"response_text"`
+	Timestamp      string `graphql:"timestamp"`
+	TaskID         int    `graphql:"task_id"`
+	SequenceNumber *int   `graphql:"sequence_number"`
+	IsError        bool   `graphql:"is_error"`
+	Task           struct {
+		ID          int    `graphql:"id"`
+		CommandName string `graphql:"command_name"`
+		Status      string `graphql:"status"`
+		CallbackID  int    `graphql:"callback_id"`
+	} `graphql:"task"`
+}
+
+// toResponse converts the query result into a types.Response.
+func (r *responseQueryFields) toResponse() *types.Response {
 	// Parse timestamp - Mythic v3.4.20 returns timestamps without timezone
-	timestamp, err := parseTimestamp(resp.Timestamp)
+	timestamp, err := parseTimestamp(r.Timestamp)
 	if err != nil {
 		timestamp = time.Time{}
 	}
 
 	return &types.Response{
-		ID:             resp.ID,
-		Response:       resp.Response,
+		ID:             r.ID,
+		Response:       r.Response,
 		Timestamp:      timestamp,
-		TaskID:         resp.TaskID,
-		SequenceNumber: resp.SequenceNumber,
-		TaskCommand:    resp.Task.CommandName,
-		TaskStatus:     resp.Task.Status,
-		TaskCallbackID: resp.Task.CallbackID,
-	}, nil
+		TaskID:         r.TaskID,
+		SequenceNumber: r.SequenceNumber,
+		TaskCommand:    r.Task.CommandName,
+		TaskStatus:     r.Task.Status,
+		TaskCallbackID: r.Task.CallbackID,
+		IsError:        r.IsError,
+	}
 }
 
 // GetResponsesByCallback retrieves recent responses from a specific callback.
@@ -190,17 +265,7 @@ func (c *Client) GetResponsesByCallback(ctx context.Context, callbackID int, lim
 	}
 
 	var query struct {
-		Response []struct {
-			ID             int    `graphql:"id"`
-			Response       string `graphql:"response_text"`
-			Timestamp      string `graphql:"timestamp"`
-			TaskID         int    `graphql:"task_id"`
-			SequenceNumber *int   `graphql:"sequence_number"`
-			Task           struct {
-				ID          int    `graphql:"id"`
-				CommandName string `graphql:"command_name"`
-			} `graphql:"task"`
-		} `graphql:"response(where: {task: {callback_id: {_eq: $callback_id}}}, order_by: {timestamp: desc}, limit: $limit)"`
+		Response []responseQueryFields `graphql:"response(where: {task: {callback_id: {_eq: $callback_id}}}, order_by: {timestamp: desc}, limit: $limit)"`
 	}
 
 	variables := map[string]interface{}{
@@ -214,24 +279,126 @@ func (c *Client) GetResponsesByCallback(ctx context.Context, callbackID int, lim
 	}
 
 	responses := make([]*types.Response, len(query.Response))
-	for i, resp := range query.Response {
-		// Parse timestamp - Mythic v3.4.20 returns timestamps without timezone
-		timestamp, err := parseTimestamp(resp.Timestamp)
-		if err != nil {
-			timestamp = time.Time{}
-		}
+	for i := range query.Response {
+		responses[i] = query.Response[i].toResponse()
+	}
+
+	return responses, nil
+}
 
-		responses[i] = &types.Response{
-			ID:             resp.ID,
-			Response:       resp.Response,
-			Timestamp:      timestamp,
-			TaskID:         resp.TaskID,
-			SequenceNumber: resp.SequenceNumber,
-			TaskCommand:    resp.Task.CommandName,
+// ResponseIterator pages through a callback's responses, newest first, using
+// the ID of the last response seen as the cursor. Unlike limit/offset paging,
+// responses arriving during iteration do not shift later pages, so every
+// response that existed when iteration began is returned exactly once.
+type ResponseIterator struct {
+	client     *Client
+	callbackID int
+	pageSize   int
+
+	lastID int
+	page   []*types.Response
+	done   bool
+}
+
+// NewResponseIterator creates an iterator over the responses of a callback.
+//
+// Parameters:
+//   - client: Authenticated client used to fetch pages
+//   - callbackID: ID of the callback to iterate responses for
+//   - pageSize: Number of responses fetched per query (0 for default: 100)
+//
+// Example:
+//
+//	it := mythic.NewResponseIterator(client, 5, 500)
+//	for {
+//	    resp, err := it.Next(ctx)
+//	    if err == io.EOF {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Printf("[%d] %s\n", resp.ID, resp.Response)
+//	}
+func NewResponseIterator(client *Client, callbackID int, pageSize int) *ResponseIterator {
+	if pageSize <= 0 {
+		pageSize = 100 // Default page size
+	}
+
+	return &ResponseIterator{
+		client:     client,
+		callbackID: callbackID,
+		pageSize:   pageSize,
+	}
+}
+
+// Next returns the next response, fetching a new page when the current one is
+// exhausted. It returns io.EOF once every response has been returned.
+func (it *ResponseIterator) Next(ctx context.Context) (*types.Response, error) {
+	if len(it.page) == 0 {
+		if it.done {
+			return nil, io.EOF
+		}
+		if err := it.fetch(ctx); err != nil {
+			return nil, err
+		}
+		if len(it.page) == 0 {
+			return nil, io.EOF
 		}
 	}
 
-	return responses, nil
+	resp := it.page[0]
+	it.page = it.page[1:]
+	it.lastID = resp.ID
+
+	return resp, nil
+}
+
+// fetch loads the page of responses older than the cursor.
+func (it *ResponseIterator) fetch(ctx context.Context) error {
+	if it.client == nil {
+		return WrapError("ResponseIterator.Next", ErrInvalidInput, "client is required")
+	}
+	if it.callbackID == 0 {
+		return WrapError("ResponseIterator.Next", ErrInvalidInput, "callback ID is required")
+	}
+
+	if err := it.client.EnsureAuthenticated(ctx); err != nil {
+		return err
+	}
+
+	where := newBoolExp("response")
+	where.conds["task"] = map[string]interface{}{
+		"callback_id": map[string]interface{}{"_eq": it.callbackID},
+	}
+	if it.lastID > 0 {
+		where.conds["id"] = map[string]interface{}{"_lt": it.lastID}
+	}
+
+	var query struct {
+		Response []responseQueryFields `graphql:"response(where: $where, order_by: {id: desc}, limit: $limit)"`
+	}
+
+	variables := map[string]interface{}{
+		"where": where,
+		"limit": it.pageSize,
+	}
+
+	if err := it.client.executeQuery(ctx, &query, variables); err != nil {
+		return WrapError("ResponseIterator.Next", err, "failed to query responses")
+	}
+
+	it.page = make([]*types.Response, len(query.Response))
+	for i := range query.Response {
+		it.page[i] = query.Response[i].toResponse()
+	}
+
+	// A short page means there is nothing older left
+	if len(it.page) < it.pageSize {
+		it.done = true
+	}
+
+	return nil
 }
 
 // SearchResponses performs full-text search across task responses.
@@ -240,9 +407,16 @@ func (c *Client) GetResponsesByCallback(ctx context.Context, callbackID int, lim
 // all responses in the operation. Useful for hunting credentials, paths,
 // or other IOCs in command output.
 //
-// Note: This is a simplified implementation that fetches responses and
-// filters them client-side. For large datasets, consider using more
-// specific filtering (GetResponsesByCallback, GetResponsesByTask, etc.)
+// The SearchMode of the request controls how Query is matched:
+//   - SearchModeCaseInsensitive (default): server-side ILIKE substring match
+//   - SearchModeSubstring: server-side LIKE substring match (case-sensitive)
+//   - SearchModeRegex: Go regular expression matched client-side
+//
+// Hasura cannot evaluate regular expressions efficiently, so regex mode
+// fetches the responses matching the other filters page by page and matches
+// them locally. At most (Limit+Offset)*5 responses, up to 1000, are scanned,
+// so a regex search over a large operation may return fewer matches than
+// exist; narrow it with TaskID, CallbackID or a time range.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout
@@ -282,77 +456,142 @@ func (c *Client) SearchResponses(ctx context.Context, req *types.ResponseSearchR
 	}
 	req.SetDefaults()
 
-	// Determine which base query to use based on filters
-	var allResponses []*types.Response
-	var err error
-
+	where := newBoolExp("response")
+	taskConds := make(map[string]interface{})
 	if req.TaskID != nil {
-		// Most specific: search within a single task
-		allResponses, err = c.GetResponsesByTask(ctx, *req.TaskID)
-	} else if req.CallbackID != nil {
-		// Search within a callback's responses
-		fetchLimit := req.Limit * 5 // Fetch more since we'll filter
-		if fetchLimit > 1000 {
-			fetchLimit = 1000
+		where.conds["task_id"] = map[string]interface{}{"_eq": *req.TaskID}
+	}
+	if req.CallbackID != nil {
+		taskConds["callback_id"] = map[string]interface{}{"_eq": *req.CallbackID}
+	}
+	if req.OperationID != nil {
+		taskConds["operation_id"] = map[string]interface{}{"_eq": *req.OperationID}
+	}
+	if req.TaskID == nil && req.CallbackID == nil && req.OperationID == nil {
+		// Default: search current operation
+		currentOp := c.GetCurrentOperation()
+		if currentOp == nil {
+			return nil, WrapError("SearchResponses", ErrNotAuthenticated, "no current operation set")
 		}
-		allResponses, err = c.GetResponsesByCallback(ctx, *req.CallbackID, fetchLimit)
-	} else if req.OperationID != nil {
-		// Search across operation
-		fetchLimit := req.Limit * 5
-		if fetchLimit > 1000 {
-			fetchLimit = 1000
+		taskConds["operation_id"] = map[string]interface{}{"_eq": *currentOp}
+	}
+	if len(taskConds) > 0 {
+		where.conds["task"] = taskConds
+	}
+
+	timestamp := make(map[string]interface{})
+	if req.StartTime != nil {
+		timestamp["_gte"] = req.StartTime.UTC().Format(time.RFC3339)
+	}
+	if req.EndTime != nil {
+		timestamp["_lte"] = req.EndTime.UTC().Format(time.RFC3339)
+	}
+	if len(timestamp) > 0 {
+		where.conds["timestamp"] = timestamp
+	}
+
+	if req.IsError != nil {
+		where.conds["is_error"] = map[string]interface{}{"_eq": *req.IsError}
+	}
+
+	if req.Query != "" {
+		switch req.SearchMode {
+		case types.SearchModeSubstring:
+			where.conds["response_text"] = map[string]interface{}{"_like": "%" + escapeLikePattern(req.Query) + "%"}
+		case types.SearchModeCaseInsensitive:
+			where.conds["response_text"] = map[string]interface{}{"_ilike": "%" + escapeLikePattern(req.Query) + "%"}
 		}
-		allResponses, err = c.GetLatestResponses(ctx, *req.OperationID, fetchLimit)
-	} else {
-		// Default: search current operation
-		fetchLimit := req.Limit * 5
-		if fetchLimit > 1000 {
-			fetchLimit = 1000
+	}
+
+	// Break timestamp ties by ID so offset pagination is stable
+	order := newOrderBy("response").then(req.SortBy, req.SortOrder)
+	if req.SortBy != "id" {
+		order = order.then("id", req.SortOrder)
+	}
+
+	if req.SearchMode != types.SearchModeRegex || req.Query == "" {
+		responses, err := c.searchResponsePage(ctx, where, order, req.Limit, req.Offset)
+		if err != nil {
+			return nil, WrapError("SearchResponses", err, "failed to search responses")
 		}
-		allResponses, err = c.GetLatestResponses(ctx, 0, fetchLimit)
+		return responses, nil
 	}
 
+	pattern, err := regexp.Compile(req.Query)
 	if err != nil {
-		return nil, WrapError("SearchResponses", err, "failed to fetch responses")
+		return nil, WrapError("SearchResponses", ErrInvalidInput, fmt.Sprintf("invalid regex query: %v", err))
 	}
 
-	// Client-side filtering
-	var filtered []*types.Response
-	for _, resp := range allResponses {
-		// Text search
-		if req.Query != "" {
-			if !strings.Contains(strings.ToLower(resp.Response), strings.ToLower(req.Query)) {
-				continue
-			}
-		}
+	scanLimit := (req.Limit + req.Offset) * 5
+	if scanLimit > 1000 {
+		scanLimit = 1000
+	}
 
-		// Time range filters
-		if req.StartTime != nil && resp.Timestamp.Before(*req.StartTime) {
-			continue
+	var matches []*types.Response
+	for scanned := 0; scanned < scanLimit && len(matches) < req.Limit+req.Offset; {
+		pageSize := scanLimit - scanned
+		if pageSize > 100 {
+			pageSize = 100
 		}
-		if req.EndTime != nil && resp.Timestamp.After(*req.EndTime) {
-			continue
+
+		page, err := c.searchResponsePage(ctx, where, order, pageSize, scanned)
+		if err != nil {
+			return nil, WrapError("SearchResponses", err, "failed to search responses")
 		}
 
-		filtered = append(filtered, resp)
+		for _, resp := range page {
+			if pattern.MatchString(resp.Response) {
+				matches = append(matches, resp)
+			}
+		}
 
-		// Stop if we've hit the limit
-		if len(filtered) >= req.Limit+req.Offset {
+		scanned += len(page)
+		if len(page) < pageSize {
 			break
 		}
 	}
 
 	// Apply pagination
 	start := req.Offset
-	if start > len(filtered) {
-		start = len(filtered)
+	if start > len(matches) {
+		start = len(matches)
 	}
 	end := start + req.Limit
-	if end > len(filtered) {
-		end = len(filtered)
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	return matches[start:end], nil
+}
+
+// searchResponsePage fetches one page of responses matching where.
+func (c *Client) searchResponsePage(ctx context.Context, where boolExp, order orderBy, limit, offset int) ([]*types.Response, error) {
+	var query struct {
+		Response []responseQueryFields `graphql:"response(where: $where, order_by: $order_by, limit: $limit, offset: $offset)"`
+	}
+
+	variables := map[string]interface{}{
+		"where":    where,
+		"order_by": order,
+		"limit":    limit,
+		"offset":   offset,
+	}
+
+	if err := c.executeQuery(ctx, &query, variables); err != nil {
+		return nil, err
 	}
 
-	return filtered[start:end], nil
+	responses := make([]*types.Response, len(query.Response))
+	for i := range query.Response {
+		responses[i] = query.Response[i].toResponse()
+	}
+
+	return responses, nil
+}
+
+// escapeLikePattern escapes the LIKE wildcards in s so it matches literally.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
 }
 
 // GetLatestResponses retrieves the most recent responses across an operation.
@@ -398,24 +637,7 @@ func (c *Client) GetLatestResponses(ctx context.Context, operationID int, limit
 	}
 
 	var query struct {
-		Response []struct {
-			ID             int    `graphql:"id"`
-			Response       string `graphql:"response_text"`
-			Timestamp      string `graphql:"timestamp"`
-			TaskID         int    `graphql:"task_id"`
-			SequenceNumber *int   `graphql:"sequence_number"`
-			Task           struct {
-				ID          int    `graphql:"id"`
-				CommandName string `graphql:"command_name"`
-				Status      string `graphql:"status"`
-				CallbackID  int    `graphql:"callback_id"`
-				Callback    struct {
-					ID   int    `graphql:"id"`
-					Host string `graphql:"host"`
-					User string `graphql:"user"`
-				} `graphql:"callback"`
-			} `graphql:"task"`
-		} `graphql:"response(where: {task: {operation_id: {_eq: $operation_id}}}, order_by: {timestamp: desc}, limit: $limit)"`
+		Response []responseQueryFields `graphql:"response(where: {task: {operation_id: {_eq: $operation_id}}}, order_by: {timestamp: desc}, limit: $limit)"`
 	}
 
 	variables := map[string]interface{}{
@@ -429,23 +651,8 @@ func (c *Client) GetLatestResponses(ctx context.Context, operationID int, limit
 	}
 
 	responses := make([]*types.Response, len(query.Response))
-	for i, resp := range query.Response {
-		// Parse timestamp - Mythic v3.4.20 returns timestamps without timezone
-		timestamp, err := parseTimestamp(resp.Timestamp)
-		if err != nil {
-			timestamp = time.Time{}
-		}
-
-		responses[i] = &types.Response{
-			ID:             resp.ID,
-			Response:       resp.Response,
-			Timestamp:      timestamp,
-			TaskID:         resp.TaskID,
-			SequenceNumber: resp.SequenceNumber,
-			TaskCommand:    resp.Task.CommandName,
-			TaskStatus:     resp.Task.Status,
-			TaskCallbackID: resp.Task.CallbackID,
-		}
+	for i := range query.Response {
+		responses[i] = query.Response[i].toResponse()
 	}
 
 	return responses, nil
@@ -546,3 +753,153 @@ func (c *Client) GetResponseStatistics(ctx context.Context, taskID int) (*types.
 
 	return stats, nil
 }
+
+// GetCallbackResponseStatistics retrieves aggregated response statistics for a callback.
+//
+// Counts, timestamps and the per-command breakdown are computed server-side
+// with aggregate queries, so neither response text nor per-task rows are
+// transferred. Total output size is deliberately not reported: Hasura cannot
+// sum the length of a text column and Mythic stores no size column, so a byte
+// total would mean downloading every response. Use GetResponseStatistics per
+// task when byte counts are needed.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout
+//   - callbackID: ID of the callback to summarize
+//
+// Returns:
+//   - *types.CallbackResponseStats: Totals and a per-command breakdown
+//   - error: Error if callback ID is invalid, not found, or query fails
+//
+// Example:
+//
+//	stats, err := client.GetCallbackResponseStatistics(ctx, 5)
+//	if err != nil {
+//	    return err
+//	}
+//	for command, cmdStats := range stats.ByCommand {
+//	    fmt.Printf("%s: %d tasks, %d responses\n", command, cmdStats.TaskCount, cmdStats.ResponseCount)
+//	}
+func (c *Client) GetCallbackResponseStatistics(ctx context.Context, callbackID int) (*types.CallbackResponseStats, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if callbackID <= 0 {
+		return nil, WrapError("GetCallbackResponseStatistics", ErrInvalidInput, "callback ID must be positive")
+	}
+
+	var query struct {
+		Callback []struct {
+			ID int `graphql:"id"`
+		} `graphql:"callback(where: {id: {_eq: $callback_id}})"`
+		Totals struct {
+			Aggregate struct {
+				Count int `graphql:"count"`
+				Min   struct {
+					Timestamp *string `graphql:"timestamp"`
+				} `graphql:"min"`
+				Max struct {
+					Timestamp *string `graphql:"timestamp"`
+				} `graphql:"max"`
+			} `graphql:"aggregate"`
+		} `graphql:"totals: response_aggregate(where: {task: {callback_id: {_eq: $callback_id}}})"`
+		ErrorTotals struct {
+			Aggregate struct {
+				Count int `graphql:"count"`
+			} `graphql:"aggregate"`
+		} `graphql:"error_totals: response_aggregate(where: {task: {callback_id: {_eq: $callback_id}}, is_error: {_eq: true}})"`
+		// One row per command name, not per task
+		Commands []struct {
+			CommandName string `graphql:"command_name"`
+		} `graphql:"commands: task(where: {callback_id: {_eq: $callback_id}}, distinct_on: command_name, order_by: {command_name: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"callback_id": callbackID,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetCallbackResponseStatistics", err, "failed to query response statistics")
+	}
+
+	if len(query.Callback) == 0 {
+		return nil, WrapError("GetCallbackResponseStatistics", ErrNotFound, fmt.Sprintf("callback %d not found", callbackID))
+	}
+
+	stats := &types.CallbackResponseStats{
+		CallbackID:    callbackID,
+		ResponseCount: query.Totals.Aggregate.Count,
+		ErrorCount:    query.ErrorTotals.Aggregate.Count,
+		ByCommand:     make(map[string]*types.CommandResponseStats),
+	}
+
+	// Parse timestamps - Mythic v3.4.20 returns timestamps without timezone
+	if ts := query.Totals.Aggregate.Min.Timestamp; ts != nil {
+		if parsed, err := parseTimestamp(*ts); err == nil {
+			stats.FirstResponse = parsed
+		}
+	}
+	if ts := query.Totals.Aggregate.Max.Timestamp; ts != nil {
+		if parsed, err := parseTimestamp(*ts); err == nil {
+			stats.LatestResponse = parsed
+		}
+	}
+
+	if len(query.Commands) == 0 {
+		return stats, nil
+	}
+
+	commands := make([]string, len(query.Commands))
+	for i, cmd := range query.Commands {
+		commands[i] = cmd.CommandName
+	}
+
+	byCommand, err := c.queryCommandResponseCounts(ctx, callbackID, commands)
+	if err != nil {
+		return nil, WrapError("GetCallbackResponseStatistics", err, "failed to query per-command statistics")
+	}
+	stats.ByCommand = byCommand
+
+	return stats, nil
+}
+
+// queryCommandResponseCounts aggregates task, response and error counts for
+// each command run on a callback. Hasura has no group-by, so the query holds
+// one set of aliased aggregates per command and runs in a single round trip.
+func (c *Client) queryCommandResponseCounts(ctx context.Context, callbackID int, commands []string) (map[string]*types.CommandResponseStats, error) {
+	var params, fields strings.Builder
+	params.WriteString("$callback_id: Int!")
+	variables := map[string]interface{}{
+		"callback_id": callbackID,
+	}
+	for i, command := range commands {
+		name := fmt.Sprintf("command_%d", i)
+		variables[name] = command
+		fmt.Fprintf(&params, ", $%s: String!", name)
+		fmt.Fprintf(&fields, "c%d_tasks: task_aggregate(where: {callback_id: {_eq: $callback_id}, command_name: {_eq: $%s}}) { aggregate { count } }\n", i, name)
+		fmt.Fprintf(&fields, "c%d_responses: response_aggregate(where: {task: {callback_id: {_eq: $callback_id}, command_name: {_eq: $%s}}}) { aggregate { count } }\n", i, name)
+		fmt.Fprintf(&fields, "c%d_errors: response_aggregate(where: {task: {callback_id: {_eq: $callback_id}, command_name: {_eq: $%s}}, is_error: {_eq: true}}) { aggregate { count } }\n", i, name)
+	}
+	query := fmt.Sprintf("query CommandResponseCounts(%s) {\n%s}", params.String(), fields.String())
+
+	var counts map[string]struct {
+		Aggregate struct {
+			Count int `json:"count"`
+		} `json:"aggregate"`
+	}
+	if err := c.ExecuteRawGraphQLInto(ctx, query, variables, &counts); err != nil {
+		return nil, err
+	}
+
+	byCommand := make(map[string]*types.CommandResponseStats, len(commands))
+	for i, command := range commands {
+		byCommand[command] = &types.CommandResponseStats{
+			TaskCount:     counts[fmt.Sprintf("c%d_tasks", i)].Aggregate.Count,
+			ResponseCount: counts[fmt.Sprintf("c%d_responses", i)].Aggregate.Count,
+			ErrorCount:    counts[fmt.Sprintf("c%d_errors", i)].Aggregate.Count,
+		}
+	}
+	return byCommand, nil
+}
@@ -1,17 +1,19 @@
 package mythic
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg" // Register JPEG decoding for thumbnails
+	"image/png"
+	"math"
+	"sync"
 	"time"
 )
 
-// timestamp is a local GraphQL scalar helper type.
-// Mythic's GraphQL schema uses a `timestamp` scalar (Hasura/Postgres).
-// The go-graphql-client library infers variable types from Go type names;
-// using a type named `timestamp` ensures variables are declared correctly.
-type timestamp string
-
 // GetScreenshots retrieves screenshots from a specific callback with optional filters.
 //
 // Screenshots are stored in the filemeta table with is_screenshot=true and require
@@ -51,29 +53,7 @@ func (c *Client) GetScreenshots(ctx context.Context, callbackID int, limit int)
 	}
 
 	var query struct {
-		FileMeta []struct {
-			ID                  int       `graphql:"id"`
-			AgentFileID         string    `graphql:"agent_file_id"`
-			TotalChunks         int       `graphql:"total_chunks"`
-			ChunksReceived      int       `graphql:"chunks_received"`
-			Complete            bool      `graphql:"complete"`
-			FullRemotePath      string    `graphql:"full_remote_path"`
-			Host                string    `graphql:"host"`
-			IsPayload           bool      `graphql:"is_payload"`
-			IsScreenshot        bool      `graphql:"is_screenshot"`
-			IsDownloadFromAgent bool      `graphql:"is_download_from_agent"`
-			Filename            string    `graphql:"filename_text"`
-			MD5                 string    `graphql:"md5"`
-			SHA1                string    `graphql:"sha1"`
-			Comment             string    `graphql:"comment"`
-			OperatorID          int       `graphql:"operator_id"`
-			Timestamp           time.Time `graphql:"timestamp"`
-			Deleted             bool      `graphql:"deleted"`
-			TaskID              *int      `graphql:"task_id"`
-			Task                *struct {
-				CallbackID int `graphql:"callback_id"`
-			} `graphql:"task"`
-		} `graphql:"filemeta(where: {is_screenshot: {_eq: true}, deleted: {_eq: false}, task: {callback_id: {_eq: $callback_id}}}, order_by: {timestamp: desc}, limit: $limit)"`
+		FileMeta []fileMetaQueryFields `graphql:"filemeta(where: {is_screenshot: {_eq: true}, deleted: {_eq: false}, task: {callback_id: {_eq: $callback_id}}}, order_by: {timestamp: desc}, limit: $limit)"`
 	}
 
 	variables := map[string]interface{}{
@@ -82,31 +62,48 @@ func (c *Client) GetScreenshots(ctx context.Context, callbackID int, limit int)
 	}
 
 	err := c.executeQuery(ctx, &query, variables)
+	if IsValidationError(err) {
+		// Older schemas cannot filter filemeta through the task relationship
+		return c.getScreenshotsUnfiltered(ctx, callbackID, limit)
+	}
 	if err != nil {
 		return nil, WrapError("GetScreenshots", err, "failed to query screenshots")
 	}
 
 	screenshots := make([]*FileMeta, len(query.FileMeta))
 	for i, file := range query.FileMeta {
-		screenshots[i] = &FileMeta{
-			ID:                  file.ID,
-			AgentFileID:         file.AgentFileID,
-			TotalChunks:         file.TotalChunks,
-			ChunksReceived:      file.ChunksReceived,
-			Complete:            file.Complete,
-			FullRemotePath:      file.FullRemotePath,
-			Host:                file.Host,
-			IsPayload:           file.IsPayload,
-			IsScreenshot:        file.IsScreenshot,
-			IsDownloadFromAgent: file.IsDownloadFromAgent,
-			Filename:            decodeFilename(file.Filename),
-			MD5:                 file.MD5,
-			SHA1:                file.SHA1,
-			Comment:             file.Comment,
-			OperatorID:          file.OperatorID,
-			Timestamp:           file.Timestamp,
-			Deleted:             file.Deleted,
-			TaskID:              file.TaskID,
+		screenshots[i] = file.toFileMeta()
+	}
+
+	return screenshots, nil
+}
+
+// getScreenshotsUnfiltered fetches every screenshot and filters by callback
+// client-side, for servers that reject the task relationship filter used by
+// GetScreenshots.
+func (c *Client) getScreenshotsUnfiltered(ctx context.Context, callbackID int, limit int) ([]*FileMeta, error) {
+	var query struct {
+		FileMeta []struct {
+			fileMetaQueryFields
+			Task *struct {
+				CallbackID int `graphql:"callback_id"`
+			} `graphql:"task"`
+		} `graphql:"filemeta(where: {is_screenshot: {_eq: true}, deleted: {_eq: false}}, order_by: {timestamp: desc})"`
+	}
+
+	err := c.executeQuery(ctx, &query, nil)
+	if err != nil {
+		return nil, WrapError("GetScreenshots", err, "failed to query screenshots")
+	}
+
+	screenshots := make([]*FileMeta, 0, limit)
+	for _, file := range query.FileMeta {
+		if file.Task == nil || file.Task.CallbackID != callbackID {
+			continue
+		}
+		screenshots = append(screenshots, file.toFileMeta())
+		if len(screenshots) == limit {
+			break
 		}
 	}
 
@@ -140,26 +137,7 @@ func (c *Client) GetScreenshotByID(ctx context.Context, screenshotID int) (*File
 	}
 
 	var query struct {
-		FileMeta []struct {
-			ID                  int       `graphql:"id"`
-			AgentFileID         string    `graphql:"agent_file_id"`
-			TotalChunks         int       `graphql:"total_chunks"`
-			ChunksReceived      int       `graphql:"chunks_received"`
-			Complete            bool      `graphql:"complete"`
-			FullRemotePath      string    `graphql:"full_remote_path"`
-			Host                string    `graphql:"host"`
-			IsPayload           bool      `graphql:"is_payload"`
-			IsScreenshot        bool      `graphql:"is_screenshot"`
-			IsDownloadFromAgent bool      `graphql:"is_download_from_agent"`
-			Filename            string    `graphql:"filename_text"`
-			MD5                 string    `graphql:"md5"`
-			SHA1                string    `graphql:"sha1"`
-			Comment             string    `graphql:"comment"`
-			OperatorID          int       `graphql:"operator_id"`
-			Timestamp           time.Time `graphql:"timestamp"`
-			Deleted             bool      `graphql:"deleted"`
-			TaskID              *int      `graphql:"task_id"`
-		} `graphql:"filemeta(where: {id: {_eq: $id}, is_screenshot: {_eq: true}})"`
+		FileMeta []fileMetaQueryFields `graphql:"filemeta(where: {id: {_eq: $id}, is_screenshot: {_eq: true}})"`
 	}
 
 	variables := map[string]interface{}{
@@ -175,27 +153,7 @@ func (c *Client) GetScreenshotByID(ctx context.Context, screenshotID int) (*File
 		return nil, WrapError("GetScreenshotByID", ErrNotFound, fmt.Sprintf("screenshot %d not found", screenshotID))
 	}
 
-	file := query.FileMeta[0]
-	return &FileMeta{
-		ID:                  file.ID,
-		AgentFileID:         file.AgentFileID,
-		TotalChunks:         file.TotalChunks,
-		ChunksReceived:      file.ChunksReceived,
-		Complete:            file.Complete,
-		FullRemotePath:      file.FullRemotePath,
-		Host:                file.Host,
-		IsPayload:           file.IsPayload,
-		IsScreenshot:        file.IsScreenshot,
-		IsDownloadFromAgent: file.IsDownloadFromAgent,
-		Filename:            decodeFilename(file.Filename),
-		MD5:                 file.MD5,
-		SHA1:                file.SHA1,
-		Comment:             file.Comment,
-		OperatorID:          file.OperatorID,
-		Timestamp:           file.Timestamp,
-		Deleted:             file.Deleted,
-		TaskID:              file.TaskID,
-	}, nil
+	return query.FileMeta[0].toFileMeta(), nil
 }
 
 // DownloadScreenshot downloads a screenshot file by its agent_file_id.
@@ -246,31 +204,222 @@ func (c *Client) DownloadScreenshot(ctx context.Context, agentFileID string) ([]
 	return data, nil
 }
 
+// downloadScreenshotsWorkers bounds how many screenshots DownloadScreenshots
+// fetches at once.
+const downloadScreenshotsWorkers = 4
+
+// DownloadScreenshots downloads a callback's most recent screenshots concurrently.
+//
+// Screenshot metadata is fetched once with GetScreenshots, so unlike calling
+// DownloadScreenshot in a loop no per-file metadata lookup is made. Screenshots
+// that have not finished transferring (Complete == false) are skipped. Up to
+// 4 screenshots are downloaded at once.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout
+//   - callbackID: ID of the callback to download screenshots from
+//   - limit: Maximum number of screenshots to consider (0 for default: 100)
+//
+// Returns:
+//   - map[string][]byte: Image data keyed by agent_file_id
+//   - error: Error if the metadata query fails, or an aggregate of per-file
+//     failures; the map still holds every screenshot that downloaded
+//
+// Example:
+//
+//	images, err := client.DownloadScreenshots(ctx, 5, 20)
+//	if err != nil {
+//	    log.Printf("some screenshots failed: %v", err)
+//	}
+//	for agentFileID, data := range images {
+//	    fmt.Printf("%s: %d bytes\n", agentFileID, len(data))
+//	}
+func (c *Client) DownloadScreenshots(ctx context.Context, callbackID int, limit int) (map[string][]byte, error) {
+	screenshots, err := c.GetScreenshots(ctx, callbackID, limit)
+	if err != nil {
+		return nil, WrapError("DownloadScreenshots", err, "failed to get screenshots")
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		images = make(map[string][]byte, len(screenshots))
+		errs   []error
+	)
+	sem := make(chan struct{}, downloadScreenshotsWorkers)
+
+	for _, screenshot := range screenshots {
+		if !screenshot.Complete {
+			continue
+		}
+		if !screenshot.IsScreenshot {
+			mu.Lock()
+			errs = append(errs, WrapError("DownloadScreenshots", ErrInvalidInput, fmt.Sprintf("file %s is not a screenshot", screenshot.AgentFileID)))
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(agentFileID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			data, err := c.DownloadFile(ctx, agentFileID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, WrapError("DownloadScreenshots", err, fmt.Sprintf("failed to download screenshot %s", agentFileID)))
+				return
+			}
+			images[agentFileID] = data
+		}(screenshot.AgentFileID)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return images, WrapError("DownloadScreenshots", errors.Join(errs...), fmt.Sprintf("failed to download %d screenshots", len(errs)))
+	}
+
+	return images, nil
+}
+
+// Default bounds for GetScreenshotThumbnail when ThumbnailOptions leaves them unset.
+const (
+	defaultThumbnailMaxWidth  = 256
+	defaultThumbnailMaxHeight = 256
+)
+
+// ThumbnailOptions bounds the size of a generated thumbnail. The image is
+// scaled down to fit within both limits while keeping its aspect ratio, and
+// is never scaled up.
+type ThumbnailOptions struct {
+	// MaxWidth is the maximum thumbnail width in pixels (0 for default: 256)
+	MaxWidth int
+
+	// MaxHeight is the maximum thumbnail height in pixels (0 for default: 256)
+	MaxHeight int
+}
+
 // GetScreenshotThumbnail retrieves a thumbnail version of a screenshot.
 //
-// Note: This is currently a placeholder as Mythic's thumbnail generation
-// capabilities may vary by deployment. This method downloads the full
-// screenshot. Future implementations may add server-side thumbnail support.
+// The screenshot is downloaded and scaled client-side, since Mythic does not
+// generate thumbnails. PNG and JPEG screenshots are returned as a PNG no
+// larger than the configured bounds (256x256 by default). Screenshots in any
+// other format are returned unchanged, not rejected, so a caller always gets
+// something displayable back.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout
 //   - agentFileID: The agent_file_id of the screenshot
+//   - opts: Maximum thumbnail dimensions (nil for defaults)
 //
 // Returns:
-//   - []byte: Screenshot data (currently full-size, may be thumbnail in future)
-//   - error: Error if screenshot not found or download fails
+//   - []byte: PNG thumbnail data, or the original data for unrecognized formats
+//   - error: Error if screenshot not found, download fails, or the image is corrupt
 //
 // Example:
 //
-//	thumbnail, err := client.GetScreenshotThumbnail(ctx, "abc123-screenshot")
+//	thumbnail, err := client.GetScreenshotThumbnail(ctx, "abc123-screenshot",
+//	    &mythic.ThumbnailOptions{MaxWidth: 320, MaxHeight: 180})
 //	if err != nil {
 //	    return err
 //	}
 //	// Display thumbnail in UI
-func (c *Client) GetScreenshotThumbnail(ctx context.Context, agentFileID string) ([]byte, error) {
-	// TODO: Implement server-side thumbnail generation if Mythic adds support
-	// For now, return full screenshot
-	return c.DownloadScreenshot(ctx, agentFileID)
+func (c *Client) GetScreenshotThumbnail(ctx context.Context, agentFileID string, opts *ThumbnailOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &ThumbnailOptions{}
+	}
+
+	if opts.MaxWidth < 0 || opts.MaxHeight < 0 {
+		return nil, WrapError("GetScreenshotThumbnail", ErrInvalidInput, "thumbnail dimensions cannot be negative")
+	}
+
+	maxWidth, maxHeight := opts.MaxWidth, opts.MaxHeight
+	if maxWidth == 0 {
+		maxWidth = defaultThumbnailMaxWidth
+	}
+	if maxHeight == 0 {
+		maxHeight = defaultThumbnailMaxHeight
+	}
+
+	data, err := c.DownloadScreenshot(ctx, agentFileID)
+	if err != nil {
+		return nil, WrapError("GetScreenshotThumbnail", err, "failed to download screenshot")
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return data, nil
+		}
+		return nil, WrapError("GetScreenshotThumbnail", ErrInvalidResponse, fmt.Sprintf("failed to decode screenshot: %v", err))
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaleImage(src, maxWidth, maxHeight)); err != nil {
+		return nil, WrapError("GetScreenshotThumbnail", err, "failed to encode thumbnail")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// scaleImage shrinks src to fit within maxWidth x maxHeight, keeping its
+// aspect ratio. Each output pixel is the average of the source pixels it
+// covers.
+func scaleImage(src image.Image, maxWidth, maxHeight int) *image.RGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := w, h
+	if w > maxWidth || h > maxHeight {
+		ratio := math.Min(float64(maxWidth)/float64(w), float64(maxHeight)/float64(h))
+		dstW = int(math.Max(1, math.Round(float64(w)*ratio)))
+		dstH = int(math.Max(1, math.Round(float64(h)*ratio)))
+	}
+
+	// Normalize to premultiplied RGBA so averaging does not darken edges
+	rgba := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	if dstW == w && dstH == h {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		sy0 := y * h / dstH
+		sy1 := (y + 1) * h / dstH
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < dstW; x++ {
+			sx0 := x * w / dstW
+			sx1 := (x + 1) * w / dstW
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var sum [4]int
+			for sy := sy0; sy < sy1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride+sx0*4 : sy*rgba.Stride+sx1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+
+			n := (sy1 - sy0) * (sx1 - sx0)
+			off := y*dst.Stride + x*4
+			for i := range sum {
+				dst.Pix[off+i] = uint8(sum[i] / n) //nolint:gosec // Average of uint8 values
+			}
+		}
+	}
+
+	return dst
 }
 
 // DeleteScreenshot marks a screenshot as deleted.
@@ -354,186 +503,92 @@ func (c *Client) GetScreenshotTimeline(ctx context.Context, callbackID int, star
 		return nil, WrapError("GetScreenshotTimeline", ErrInvalidInput, "callback ID is required")
 	}
 
-	// Build query with time filters
-	variables := map[string]interface{}{
-		"callback_id": callbackID,
+	where := newBoolExp("filemeta")
+	where.conds["is_screenshot"] = map[string]interface{}{"_eq": true}
+	where.conds["deleted"] = map[string]interface{}{"_eq": false}
+	where.conds["task"] = map[string]interface{}{"callback_id": map[string]interface{}{"_eq": callbackID}}
+	window := map[string]interface{}{}
+	if startTime != nil {
+		window["_gte"] = startTime.Format(time.RFC3339)
+	}
+	if endTime != nil {
+		window["_lte"] = endTime.Format(time.RFC3339)
+	}
+	if len(window) > 0 {
+		where.conds["timestamp"] = window
 	}
 
-	// GraphQL query structure depends on whether we have time filters
-	if startTime != nil && endTime != nil {
-		// Both start and end time
-		var query struct {
-			FileMeta []struct {
-				ID                  int       `graphql:"id"`
-				AgentFileID         string    `graphql:"agent_file_id"`
-				TotalChunks         int       `graphql:"total_chunks"`
-				ChunksReceived      int       `graphql:"chunks_received"`
-				Complete            bool      `graphql:"complete"`
-				FullRemotePath      string    `graphql:"full_remote_path"`
-				Host                string    `graphql:"host"`
-				IsPayload           bool      `graphql:"is_payload"`
-				IsScreenshot        bool      `graphql:"is_screenshot"`
-				IsDownloadFromAgent bool      `graphql:"is_download_from_agent"`
-				Filename            string    `graphql:"filename_text"`
-				MD5                 string    `graphql:"md5"`
-				SHA1                string    `graphql:"sha1"`
-				Comment             string    `graphql:"comment"`
-				OperatorID          int       `graphql:"operator_id"`
-				Timestamp           time.Time `graphql:"timestamp"`
-				Deleted             bool      `graphql:"deleted"`
-				TaskID              *int      `graphql:"task_id"`
-			} `graphql:"filemeta(where: {is_screenshot: {_eq: true}, deleted: {_eq: false}, task: {callback_id: {_eq: $callback_id}}, timestamp: {_gte: $start_time, _lte: $end_time}}, order_by: {timestamp: asc})"`
-		}
-
-		variables["start_time"] = timestamp(startTime.Format(time.RFC3339))
-		variables["end_time"] = timestamp(endTime.Format(time.RFC3339))
-
-		err := c.executeQuery(ctx, &query, variables)
-		if err != nil {
-			return nil, WrapError("GetScreenshotTimeline", err, "failed to query screenshots")
-		}
-
-		screenshots := make([]*FileMeta, len(query.FileMeta))
-		for i, file := range query.FileMeta {
-			screenshots[i] = &FileMeta{
-				ID:                  file.ID,
-				AgentFileID:         file.AgentFileID,
-				TotalChunks:         file.TotalChunks,
-				ChunksReceived:      file.ChunksReceived,
-				Complete:            file.Complete,
-				FullRemotePath:      file.FullRemotePath,
-				Host:                file.Host,
-				IsPayload:           file.IsPayload,
-				IsScreenshot:        file.IsScreenshot,
-				IsDownloadFromAgent: file.IsDownloadFromAgent,
-				Filename:            decodeFilename(file.Filename),
-				MD5:                 file.MD5,
-				SHA1:                file.SHA1,
-				Comment:             file.Comment,
-				OperatorID:          file.OperatorID,
-				Timestamp:           file.Timestamp,
-				Deleted:             file.Deleted,
-				TaskID:              file.TaskID,
-			}
-		}
+	var query struct {
+		FileMeta []fileMetaQueryFields `graphql:"filemeta(where: $where, order_by: {timestamp: asc})"`
+	}
 
-		return screenshots, nil
-	} else if startTime != nil {
-		// Only start time
-		var query struct {
-			FileMeta []struct {
-				ID                  int       `graphql:"id"`
-				AgentFileID         string    `graphql:"agent_file_id"`
-				TotalChunks         int       `graphql:"total_chunks"`
-				ChunksReceived      int       `graphql:"chunks_received"`
-				Complete            bool      `graphql:"complete"`
-				FullRemotePath      string    `graphql:"full_remote_path"`
-				Host                string    `graphql:"host"`
-				IsPayload           bool      `graphql:"is_payload"`
-				IsScreenshot        bool      `graphql:"is_screenshot"`
-				IsDownloadFromAgent bool      `graphql:"is_download_from_agent"`
-				Filename            string    `graphql:"filename_text"`
-				MD5                 string    `graphql:"md5"`
-				SHA1                string    `graphql:"sha1"`
-				Comment             string    `graphql:"comment"`
-				OperatorID          int       `graphql:"operator_id"`
-				Timestamp           time.Time `graphql:"timestamp"`
-				Deleted             bool      `graphql:"deleted"`
-				TaskID              *int      `graphql:"task_id"`
-			} `graphql:"filemeta(where: {is_screenshot: {_eq: true}, deleted: {_eq: false}, task: {callback_id: {_eq: $callback_id}}, timestamp: {_gte: $start_time}}, order_by: {timestamp: asc})"`
-		}
+	variables := map[string]interface{}{
+		"where": where,
+	}
 
-		variables["start_time"] = timestamp(startTime.Format(time.RFC3339))
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetScreenshotTimeline", err, "failed to query screenshots")
+	}
 
-		err := c.executeQuery(ctx, &query, variables)
-		if err != nil {
-			return nil, WrapError("GetScreenshotTimeline", err, "failed to query screenshots")
-		}
+	screenshots := make([]*FileMeta, len(query.FileMeta))
+	for i, file := range query.FileMeta {
+		screenshots[i] = file.toFileMeta()
+	}
 
-		screenshots := make([]*FileMeta, len(query.FileMeta))
-		for i, file := range query.FileMeta {
-			screenshots[i] = &FileMeta{
-				ID:                  file.ID,
-				AgentFileID:         file.AgentFileID,
-				TotalChunks:         file.TotalChunks,
-				ChunksReceived:      file.ChunksReceived,
-				Complete:            file.Complete,
-				FullRemotePath:      file.FullRemotePath,
-				Host:                file.Host,
-				IsPayload:           file.IsPayload,
-				IsScreenshot:        file.IsScreenshot,
-				IsDownloadFromAgent: file.IsDownloadFromAgent,
-				Filename:            decodeFilename(file.Filename),
-				MD5:                 file.MD5,
-				SHA1:                file.SHA1,
-				Comment:             file.Comment,
-				OperatorID:          file.OperatorID,
-				Timestamp:           file.Timestamp,
-				Deleted:             file.Deleted,
-				TaskID:              file.TaskID,
-			}
-		}
+	return screenshots, nil
+}
 
-		return screenshots, nil
-	} else if endTime != nil {
-		// Only end time
-		var query struct {
-			FileMeta []struct {
-				ID                  int       `graphql:"id"`
-				AgentFileID         string    `graphql:"agent_file_id"`
-				TotalChunks         int       `graphql:"total_chunks"`
-				ChunksReceived      int       `graphql:"chunks_received"`
-				Complete            bool      `graphql:"complete"`
-				FullRemotePath      string    `graphql:"full_remote_path"`
-				Host                string    `graphql:"host"`
-				IsPayload           bool      `graphql:"is_payload"`
-				IsScreenshot        bool      `graphql:"is_screenshot"`
-				IsDownloadFromAgent bool      `graphql:"is_download_from_agent"`
-				Filename            string    `graphql:"filename_text"`
-				MD5                 string    `graphql:"md5"`
-				SHA1                string    `graphql:"sha1"`
-				Comment             string    `graphql:"comment"`
-				OperatorID          int       `graphql:"operator_id"`
-				Timestamp           time.Time `graphql:"timestamp"`
-				Deleted             bool      `graphql:"deleted"`
-				TaskID              *int      `graphql:"task_id"`
-			} `graphql:"filemeta(where: {is_screenshot: {_eq: true}, deleted: {_eq: false}, task: {callback_id: {_eq: $callback_id}}, timestamp: {_lte: $end_time}}, order_by: {timestamp: asc})"`
-		}
+// DownloadScreenshotsZip downloads a callback's screenshots within a time
+// range as a single ZIP archive.
+//
+// The screenshots are collected with GetScreenshotTimeline, archived
+// server-side with BulkDownloadFiles, and the archive is then downloaded.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout
+//   - callbackID: ID of the callback
+//   - startTime: Start of time range (nil for no lower bound)
+//   - endTime: End of time range (nil for no upper bound)
+//
+// Returns:
+//   - []byte: ZIP archive data
+//   - error: ErrNotFound if no screenshots fall in the range, or an error if
+//     archiving or the download fails
+//
+// Example:
+//
+//	endTime := time.Now()
+//	startTime := endTime.Add(-24 * time.Hour)
+//	archive, err := client.DownloadScreenshotsZip(ctx, 5, &startTime, &endTime)
+//	if err != nil {
+//	    return err
+//	}
+//	err = os.WriteFile("screenshots.zip", archive, 0644)
+func (c *Client) DownloadScreenshotsZip(ctx context.Context, callbackID int, startTime, endTime *time.Time) ([]byte, error) {
+	screenshots, err := c.GetScreenshotTimeline(ctx, callbackID, startTime, endTime)
+	if err != nil {
+		return nil, WrapError("DownloadScreenshotsZip", err, "failed to get screenshots")
+	}
 
-		variables["end_time"] = timestamp(endTime.Format(time.RFC3339))
+	if len(screenshots) == 0 {
+		return nil, WrapError("DownloadScreenshotsZip", ErrNotFound, fmt.Sprintf("no screenshots for callback %d in the requested range", callbackID))
+	}
 
-		err := c.executeQuery(ctx, &query, variables)
-		if err != nil {
-			return nil, WrapError("GetScreenshotTimeline", err, "failed to query screenshots")
-		}
+	agentFileIDs := make([]string, len(screenshots))
+	for i, screenshot := range screenshots {
+		agentFileIDs[i] = screenshot.AgentFileID
+	}
 
-		screenshots := make([]*FileMeta, len(query.FileMeta))
-		for i, file := range query.FileMeta {
-			screenshots[i] = &FileMeta{
-				ID:                  file.ID,
-				AgentFileID:         file.AgentFileID,
-				TotalChunks:         file.TotalChunks,
-				ChunksReceived:      file.ChunksReceived,
-				Complete:            file.Complete,
-				FullRemotePath:      file.FullRemotePath,
-				Host:                file.Host,
-				IsPayload:           file.IsPayload,
-				IsScreenshot:        file.IsScreenshot,
-				IsDownloadFromAgent: file.IsDownloadFromAgent,
-				Filename:            decodeFilename(file.Filename),
-				MD5:                 file.MD5,
-				SHA1:                file.SHA1,
-				Comment:             file.Comment,
-				OperatorID:          file.OperatorID,
-				Timestamp:           file.Timestamp,
-				Deleted:             file.Deleted,
-				TaskID:              file.TaskID,
-			}
-		}
+	archiveID, err := c.BulkDownloadFiles(ctx, agentFileIDs)
+	if err != nil {
+		return nil, WrapError("DownloadScreenshotsZip", err, "failed to archive screenshots")
+	}
 
-		return screenshots, nil
-	} else {
-		// No time filters - get all screenshots
-		return c.GetScreenshots(ctx, callbackID, 1000) // Use high limit
+	data, err := c.DownloadFile(ctx, archiveID)
+	if err != nil {
+		return nil, WrapError("DownloadScreenshotsZip", err, "failed to download archive")
 	}
+
+	return data, nil
 }
@@ -3,12 +3,21 @@ package mythic
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
+const (
+	// defaultReconnectBackoff is the initial reconnect delay when none is configured
+	defaultReconnectBackoff = time.Second
+	// maxReconnectBackoff caps the exponential reconnect delay
+	maxReconnectBackoff = time.Minute
+)
+
 // Subscribe creates a GraphQL subscription for real-time event updates.
 // Subscriptions use WebSocket connections to stream events like task output,
 // callback status changes, and file uploads in real-time.
@@ -17,6 +26,7 @@ import (
 //   - task_output: Real-time task output as it's generated
 //   - callback: Callback status changes (new, active, dead, etc.)
 //   - file: New file uploads and downloads
+//   - new_task: Tasks as they are created, by any operator
 //   - all: All events across the operation
 //
 // The subscription runs in a goroutine and calls the provided handler for each event.
@@ -69,6 +79,12 @@ import (
 // Subscriptions use the graphql-transport-ws protocol over WebSocket connections.
 // The connection is automatically established on first subscription and reused
 // for subsequent subscriptions. Authentication is handled via connection parameters.
+//
+// If the connection is lost, Done is closed after an ErrConnectionFailed error.
+// With AutoReconnect set, the subscription instead re-dials with exponential
+// backoff and keeps delivering to the same Events channel; each drop is reported
+// on Errors as ErrConnectionFailed while Done stays open. Reconnecting stops
+// when ctx is cancelled or the subscription is closed.
 func (c *Client) Subscribe(ctx context.Context, config *types.SubscriptionConfig) (*types.Subscription, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
@@ -97,6 +113,12 @@ func (c *Client) Subscribe(ctx context.Context, config *types.SubscriptionConfig
 		operationID = *opID
 	}
 
+	// Build GraphQL subscription query based on type
+	query, variables, err := buildSubscriptionQuery(config.Type, operationID, config.Filter)
+	if err != nil {
+		return nil, WrapError("Subscribe", ErrInvalidInput, err.Error())
+	}
+
 	// Generate unique subscription ID
 	subID := generateSubscriptionID()
 
@@ -113,12 +135,6 @@ func (c *Client) Subscribe(ctx context.Context, config *types.SubscriptionConfig
 	// Create context for subscription lifecycle
 	subCtx, cancel := context.WithCancel(context.Background())
 
-	// Get subscription client (establishes WebSocket connection if needed)
-	subscriptionClient := c.getSubscriptionClient()
-
-	// Build GraphQL subscription query based on type
-	query, variables := buildSubscriptionQuery(config.Type, operationID, config.Filter)
-
 	// Start subscription in background goroutine
 	go func() {
 		defer func() {
@@ -132,8 +148,10 @@ func (c *Client) Subscribe(ctx context.Context, config *types.SubscriptionConfig
 			c.subscriptionsMutex.Unlock()
 		}()
 
-		// Subscribe using WebSocket client
-		graphqlSubID, err := subscriptionClient.Subscribe(query, variables, func(dataValue []byte, errValue error) error {
+		// Set once an event arrives, so a healthy connection resets the backoff
+		var received atomic.Bool
+
+		handler := func(dataValue []byte, errValue error) error {
 			// Handle errors from subscription
 			if errValue != nil {
 				select {
@@ -161,6 +179,11 @@ func (c *Client) Subscribe(ctx context.Context, config *types.SubscriptionConfig
 				return err
 			}
 
+			if config.Type == types.SubscriptionTypeNewTask {
+				event.Data = flattenNewTaskEvent(event.Data)
+			}
+			received.Store(true)
+
 			// Call user handler
 			if config.Handler != nil {
 				if err := config.Handler(event); err != nil {
@@ -181,28 +204,78 @@ func (c *Client) Subscribe(ctx context.Context, config *types.SubscriptionConfig
 			}
 
 			return nil
-		})
+		}
 
-		if err != nil {
+		sendError := func(err error) {
 			select {
-			case sub.Errors <- WrapError("Subscribe", ErrOperationFailed, fmt.Sprintf("subscription failed: %v", err)):
+			case sub.Errors <- err:
 			case <-subCtx.Done():
 			}
-			return
 		}
 
-		// Wait for cancellation
-		<-subCtx.Done()
-
-		// Unsubscribe using the graphqlSubID
-		if graphqlSubID != "" {
-			if err := subscriptionClient.Unsubscribe(graphqlSubID); err != nil {
-				// Log error but continue cleanup
+		backoff := config.ReconnectBackoff
+		if backoff == 0 {
+			backoff = defaultReconnectBackoff
+		}
+		delay := backoff
+
+		for {
+			// Get subscription client (establishes WebSocket connection if needed)
+			subscriptionClient, disconnected := c.getSubscriptionClient()
+
+			// Subscribe using WebSocket client
+			graphqlSubID, err := subscriptionClient.Subscribe(query, variables, handler)
+			if err != nil {
+				if !config.AutoReconnect {
+					sendError(WrapError("Subscribe", ErrOperationFailed, fmt.Sprintf("subscription failed: %v", err)))
+					return
+				}
+			} else {
 				select {
-				case sub.Errors <- WrapError("Subscribe", ErrOperationFailed, fmt.Sprintf("unsubscribe error: %v", err)):
-				default:
+				case <-subCtx.Done():
+					// Unsubscribe using the graphqlSubID
+					if graphqlSubID != "" {
+						if err := subscriptionClient.Unsubscribe(graphqlSubID); err != nil {
+							// Log error but continue cleanup
+							select {
+							case sub.Errors <- WrapError("Subscribe", ErrOperationFailed, fmt.Sprintf("unsubscribe error: %v", err)):
+							default:
+							}
+						}
+					}
+					return
+				case <-disconnected:
+				}
+
+				// The client is also stopped when the parent client closes
+				if subCtx.Err() != nil {
+					return
+				}
+				if !config.AutoReconnect {
+					sendError(WrapError("Subscribe", ErrConnectionFailed, "subscription connection lost"))
+					return
 				}
 			}
+
+			if received.Swap(false) {
+				delay = backoff
+			}
+			sendError(WrapError("Subscribe", ErrConnectionFailed, fmt.Sprintf("subscription connection lost, reconnecting in %s", delay)))
+
+			select {
+			case <-time.After(delay):
+			case <-subCtx.Done():
+				return
+			case <-ctx.Done():
+				return
+			case <-sub.Done:
+				return
+			}
+
+			delay *= 2
+			if delay > maxReconnectBackoff {
+				delay = maxReconnectBackoff
+			}
 		}
 	}()
 
@@ -268,15 +341,164 @@ func generateSubscriptionID() string {
 	return uuid.New().String()
 }
 
+// flattenNewTaskEvent replaces the raw {"task": [...]} payload of a new task
+// event with the fields of the newest task, so they can be read directly with
+// GetDataField. The operator's username is exposed as "operator_username".
+func flattenNewTaskEvent(data map[string]interface{}) map[string]interface{} {
+	rows, ok := data["task"].([]interface{})
+	if !ok || len(rows) == 0 {
+		return data
+	}
+
+	row, ok := rows[0].(map[string]interface{})
+	if !ok {
+		return data
+	}
+
+	flat := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		if k != "operator" {
+			flat[k] = v
+		}
+	}
+	if operator, ok := row["operator"].(map[string]interface{}); ok {
+		flat["operator_username"] = operator["username"]
+	}
+
+	return flat
+}
+
+// subscriptionTable describes the table a subscription type streams from and
+// the column path each supported SubscriptionConfig.Filter key constrains.
+type subscriptionTable struct {
+	name        string
+	filterPaths map[string][]string
+	baseConds   map[string]interface{}
+}
+
+// notDeleted is the base condition for tables with soft-deleted rows.
+var notDeleted = map[string]interface{}{"deleted": map[string]interface{}{"_eq": false}}
+
+// taskChildFilterPaths are the filter paths for tables whose rows belong to a task.
+var taskChildFilterPaths = map[string][]string{
+	"operation_id": {"operation_id"},
+	"task_id":      {"task_id"},
+	"callback_id":  {"task", "callback_id"},
+}
+
+// subscriptionTables maps each subscription type to its table. Types not
+// listed here (including SubscriptionTypeAll) stream task output.
+var subscriptionTables = map[types.SubscriptionType]subscriptionTable{
+	types.SubscriptionTypeTaskOutput: {
+		name: "task_output",
+		filterPaths: map[string][]string{
+			"operation_id": {"task", "callback", "operation_id"},
+			"task_id":      {"task_id"},
+			"callback_id":  {"task", "callback_id"},
+		},
+	},
+	types.SubscriptionTypeCallback: {
+		name: "callback",
+		filterPaths: map[string][]string{
+			"operation_id": {"operation_id"},
+			"callback_id":  {"id"},
+		},
+	},
+	types.SubscriptionTypeFile: {name: "filemeta", filterPaths: taskChildFilterPaths},
+	types.SubscriptionTypeAlert: {
+		name: "operationalert",
+		filterPaths: map[string][]string{
+			"operation_id": {"operation_id"},
+			"callback_id":  {"callback_id"},
+		},
+	},
+	types.SubscriptionTypeScreenshot: {
+		name:        "filemeta",
+		filterPaths: taskChildFilterPaths,
+		baseConds: map[string]interface{}{
+			"is_screenshot": map[string]interface{}{"_eq": true},
+			"deleted":       map[string]interface{}{"_eq": false},
+		},
+	},
+	types.SubscriptionTypeKeylog:     {name: "keylog", filterPaths: taskChildFilterPaths},
+	types.SubscriptionTypeProcess:    {name: "process", filterPaths: taskChildFilterPaths, baseConds: notDeleted},
+	types.SubscriptionTypeCredential: {name: "credential", filterPaths: taskChildFilterPaths, baseConds: notDeleted},
+	types.SubscriptionTypeArtifact:   {name: "artifact", filterPaths: taskChildFilterPaths, baseConds: notDeleted},
+	types.SubscriptionTypeToken:      {name: "token", filterPaths: taskChildFilterPaths, baseConds: notDeleted},
+	types.SubscriptionTypeNewTask: {
+		name: "task",
+		filterPaths: map[string][]string{
+			"operation_id": {"operation_id"},
+			"task_id":      {"id"},
+			"callback_id":  {"callback_id"},
+		},
+	},
+}
+
+// buildSubscriptionWhere builds the where clause for a subscription. Every
+// subscription is scoped to operationID unless filter overrides operation_id;
+// each remaining filter key is applied with _eq semantics.
+func buildSubscriptionWhere(subType types.SubscriptionType, operationID int, filter map[string]interface{}) (boolExp, error) {
+	table, ok := subscriptionTables[subType]
+	if !ok {
+		table = subscriptionTables[types.SubscriptionTypeTaskOutput]
+	}
+
+	where := newBoolExp(table.name)
+	for k, v := range table.baseConds {
+		where.conds[k] = v
+	}
+
+	var operation interface{} = operationID
+	if v, ok := filter["operation_id"]; ok {
+		operation = v
+	}
+	setWherePath(where.conds, table.filterPaths["operation_id"], map[string]interface{}{"_eq": operation})
+
+	// Sort keys so errors are deterministic
+	keys := make([]string, 0, len(filter))
+	for k := range filter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if k == "operation_id" {
+			continue
+		}
+		path, ok := table.filterPaths[k]
+		if !ok {
+			return boolExp{}, fmt.Errorf("filter key %q is not supported for %s subscriptions", k, subType)
+		}
+		setWherePath(where.conds, path, map[string]interface{}{"_eq": filter[k]})
+	}
+
+	return where, nil
+}
+
+// setWherePath sets a condition at a nested column path, merging with any
+// conditions already present along the path.
+func setWherePath(conds map[string]interface{}, path []string, cond map[string]interface{}) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := conds[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			conds[key] = next
+		}
+		conds = next
+	}
+	conds[path[len(path)-1]] = cond
+}
+
 // buildSubscriptionQuery constructs a GraphQL subscription query based on type.
-func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, filter map[string]interface{}) (interface{}, map[string]interface{}) {
-	variables := map[string]interface{}{
-		"operation_id": operationID,
+func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, filter map[string]interface{}) (interface{}, map[string]interface{}, error) {
+	where, err := buildSubscriptionWhere(subType, operationID, filter)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Add filter parameters if provided
-	for k, v := range filter {
-		variables[k] = v
+	variables := map[string]interface{}{
+		"where": where,
 	}
 
 	// Build query based on subscription type
@@ -302,9 +524,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 					OperatorID      int    `graphql:"operator_id"`
 					CommentOperator string `graphql:"comment_operator"`
 				} `graphql:"task"`
-			} `graphql:"task_output(where: {task: {callback: {operation_id: {_eq: $operation_id}}}}, order_by: {id: desc})"`
+			} `graphql:"task_output(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeCallback:
 		// Subscribe to callback updates
@@ -332,9 +554,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				Architecture        string `graphql:"architecture"`
 				Domain              string `graphql:"domain"`
 				Os                  string `graphql:"os"`
-			} `graphql:"callback(where: {operation_id: {_eq: $operation_id}}, order_by: {id: desc})"`
+			} `graphql:"callback(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeFile:
 		// Subscribe to file updates
@@ -359,9 +581,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				Filename            string `graphql:"filename_text"`
 				Md5                 string `graphql:"md5"`
 				Sha1                string `graphql:"sha1"`
-			} `graphql:"filemeta(where: {operation_id: {_eq: $operation_id}}, order_by: {id: desc})"`
+			} `graphql:"filemeta(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeAlert:
 		// Subscribe to operational alerts
@@ -376,9 +598,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				OperationID int    `graphql:"operation_id"`
 				CallbackID  *int   `graphql:"callback_id"`
 				Timestamp   string `graphql:"timestamp"`
-			} `graphql:"operationalert(where: {operation_id: {_eq: $operation_id}}, order_by: {id: desc})"`
+			} `graphql:"operationalert(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeScreenshot:
 		// Subscribe to screenshot uploads (filemeta with is_screenshot=true)
@@ -394,9 +616,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				TaskID      *int   `graphql:"task_id"`
 				CallbackID  *int   `graphql:"callback_id"`
 				OperationID int    `graphql:"operation_id"`
-			} `graphql:"filemeta(where: {operation_id: {_eq: $operation_id}, is_screenshot: {_eq: true}, deleted: {_eq: false}}, order_by: {id: desc})"`
+			} `graphql:"filemeta(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeKeylog:
 		// Subscribe to keylog entries
@@ -410,9 +632,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				OperationID int    `graphql:"operation_id"`
 				User        string `graphql:"user"`
 				CallbackID  int    `graphql:"callback_id"`
-			} `graphql:"keylog(where: {operation_id: {_eq: $operation_id}}, order_by: {id: desc})"`
+			} `graphql:"keylog(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeProcess:
 		// Subscribe to process tracking updates
@@ -432,9 +654,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				CallbackID      *int   `graphql:"callback_id"`
 				Timestamp       string `graphql:"timestamp"`
 				Deleted         bool   `graphql:"deleted"`
-			} `graphql:"process(where: {operation_id: {_eq: $operation_id}, deleted: {_eq: false}}, order_by: {id: desc})"`
+			} `graphql:"process(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeCredential:
 		// Subscribe to credential discoveries
@@ -452,9 +674,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				Timestamp   string `graphql:"timestamp"`
 				Deleted     bool   `graphql:"deleted"`
 				Metadata    string `graphql:"metadata"`
-			} `graphql:"credential(where: {operation_id: {_eq: $operation_id}, deleted: {_eq: false}}, order_by: {id: desc})"`
+			} `graphql:"credential(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeArtifact:
 		// Subscribe to artifact/IOC tracking
@@ -471,9 +693,9 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				Timestamp    string `graphql:"timestamp"`
 				Deleted      bool   `graphql:"deleted"`
 				Metadata     string `graphql:"metadata"`
-			} `graphql:"artifact(where: {operation_id: {_eq: $operation_id}, deleted: {_eq: false}}, order_by: {id: desc})"`
+			} `graphql:"artifact(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeToken:
 		// Subscribe to token discoveries
@@ -495,9 +717,28 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				Timestamp   string `graphql:"timestamp"`
 				Host        string `graphql:"host"`
 				Deleted     bool   `graphql:"deleted"`
-			} `graphql:"token(where: {operation_id: {_eq: $operation_id}, deleted: {_eq: false}}, order_by: {id: desc})"`
+			} `graphql:"token(where: $where, order_by: {id: desc})"`
+		}
+		return &query, variables, nil
+
+	case types.SubscriptionTypeNewTask:
+		// Subscribe to the most recently created task
+		var query struct {
+			Task []struct {
+				ID            int    `graphql:"id"`
+				DisplayID     int    `graphql:"display_id"`
+				CommandName   string `graphql:"command_name"`
+				DisplayParams string `graphql:"display_params"`
+				Status        string `graphql:"status"`
+				Timestamp     string `graphql:"timestamp"`
+				CallbackID    int    `graphql:"callback_id"`
+				OperatorID    int    `graphql:"operator_id"`
+				Operator      struct {
+					Username string `graphql:"username"`
+				} `graphql:"operator"`
+			} `graphql:"task(where: $where, order_by: {id: desc}, limit: 1)"`
 		}
-		return &query, variables
+		return &query, variables, nil
 
 	case types.SubscriptionTypeAll:
 		// Subscribe to all events (task output, callbacks, files)
@@ -513,8 +754,8 @@ func buildSubscriptionQuery(subType types.SubscriptionType, operationID int, fil
 				Output    string `graphql:"output"`
 				Timestamp string `graphql:"timestamp"`
 				TaskID    int    `graphql:"task_id"`
-			} `graphql:"task_output(where: {task: {callback: {operation_id: {_eq: $operation_id}}}}, order_by: {id: desc})"`
+			} `graphql:"task_output(where: $where, order_by: {id: desc})"`
 		}
-		return &query, variables
+		return &query, variables, nil
 	}
 }
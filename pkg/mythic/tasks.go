@@ -2,8 +2,18 @@ package mythic
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
 // Task represents a Mythic task.
@@ -23,6 +33,7 @@ type Task struct {
 	OperatorID                int       `json:"operator_id"`
 	OperationID               int       `json:"operation_id"`
 	ParentTaskID              *int      `json:"parent_task_id,omitempty"`
+	TokenID                   *int      `json:"token_id,omitempty"`
 	ResponseCount             int       `json:"response_count"`
 	IsInteractiveTask         bool      `json:"is_interactive_task"`
 	InteractiveTaskType       *int      `json:"interactive_task_type,omitempty"`
@@ -42,6 +53,10 @@ type Task struct {
 }
 
 // TaskResponse represents output from a task.
+//
+// ResponseRaw holds the exact bytes the agent sent, decoded from the base64
+// response.response_raw column; use it for binary output that ResponseText
+// would mangle. It is nil when the column is empty or not valid base64.
 type TaskResponse struct {
 	ID             int       `json:"id"`
 	TaskID         int       `json:"task_id"`
@@ -67,6 +82,238 @@ type TaskRequest struct {
 	ParameterGroupName  string   `json:"parameter_group_name,omitempty"`
 	OriginalParams      string   `json:"original_params,omitempty"`
 	TokenID             *int     `json:"token_id,omitempty"`
+
+	// AgentCallbackID identifies the callback by its agent_callback_id when
+	// neither CallbackID nor CallbackIDs is set. IssueTask resolves it to a
+	// display ID before sending.
+	AgentCallbackID string `json:"agent_callback_id,omitempty"`
+
+	// ParamsBuilder builds Params as JSON when set. It cannot be combined
+	// with a non-empty Params.
+	ParamsBuilder *TaskParamsBuilder `json:"-"`
+
+	// ParamsMap is marshaled to JSON and sent as Params when set, producing
+	// the same wire format as a hand-encoded Params string. It cannot be
+	// combined with Params or ParamsBuilder.
+	ParamsMap map[string]interface{} `json:"-"`
+
+	// Validate runs ValidateTaskRequest before the task is sent, so unknown
+	// commands and missing required parameters are rejected client-side.
+	Validate bool `json:"-"`
+}
+
+// TaskParamsBuilder builds the JSON parameter string for a task. When created
+// with a command definition, Build validates the parameters against it.
+type TaskParamsBuilder struct {
+	command *CommandWithParameters
+	params  map[string]interface{}
+}
+
+// NewTaskParamsBuilder creates a parameter builder. If command is non-nil
+// (as returned by GetCommandWithParameters), Build rejects unknown
+// parameters, values that cannot be coerced to the declared type, and
+// missing required parameters.
+func NewTaskParamsBuilder(command *CommandWithParameters) *TaskParamsBuilder {
+	return &TaskParamsBuilder{
+		command: command,
+		params:  make(map[string]interface{}),
+	}
+}
+
+// NewTaskParamsBuilderForCommand fetches a command's parameter definitions with
+// GetCommandWithParameters and returns a builder that validates against them.
+func (c *Client) NewTaskParamsBuilderForCommand(ctx context.Context, payloadTypeID int, commandName string) (*TaskParamsBuilder, error) {
+	command, err := c.GetCommandWithParameters(ctx, payloadTypeID, commandName)
+	if err != nil {
+		return nil, WrapError("NewTaskParamsBuilderForCommand", err, "failed to get command parameters")
+	}
+
+	return NewTaskParamsBuilder(command), nil
+}
+
+// Set sets a parameter to an arbitrary Go value. When the builder has a
+// command definition, Build coerces the value to the parameter's declared
+// type: any integer or float type (or a numeric string) for Number, a bool
+// or a strconv.ParseBool string for Boolean, and a []string or
+// []interface{} of strings for Array and ChooseMultiple.
+func (b *TaskParamsBuilder) Set(name string, val interface{}) *TaskParamsBuilder {
+	b.params[name] = val
+	return b
+}
+
+// SetString sets a String or ChooseOne parameter.
+func (b *TaskParamsBuilder) SetString(name, val string) *TaskParamsBuilder {
+	b.params[name] = val
+	return b
+}
+
+// SetBool sets a Boolean parameter.
+func (b *TaskParamsBuilder) SetBool(name string, val bool) *TaskParamsBuilder {
+	b.params[name] = val
+	return b
+}
+
+// SetNumber sets a Number parameter.
+func (b *TaskParamsBuilder) SetNumber(name string, val float64) *TaskParamsBuilder {
+	b.params[name] = val
+	return b
+}
+
+// SetFile sets a File parameter to the agent file ID of a file already
+// registered with Mythic (e.g. from UploadFile).
+func (b *TaskParamsBuilder) SetFile(name, agentFileID string) *TaskParamsBuilder {
+	b.params[name] = agentFileID
+	return b
+}
+
+// SetArray sets an Array or ChooseMultiple parameter.
+func (b *TaskParamsBuilder) SetArray(name string, vals []string) *TaskParamsBuilder {
+	if vals == nil {
+		vals = []string{}
+	}
+	b.params[name] = vals
+	return b
+}
+
+// Build validates the parameters and returns them as a JSON string suitable
+// for TaskRequest.Params.
+//
+// Parameters marked required are only enforced when they have no default
+// value, since Mythic fills in defaults server-side.
+//
+// All missing required parameters are reported together in one error.
+func (b *TaskParamsBuilder) Build() (string, error) {
+	params := b.params
+	if b.command != nil {
+		validated, err := b.validate()
+		if err != nil {
+			return "", err
+		}
+		params = validated
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", WrapError("TaskParamsBuilder.Build", err, "failed to encode parameters")
+	}
+
+	return string(data), nil
+}
+
+// validate checks the parameters against the builder's command definition
+// and returns them coerced to their declared types.
+func (b *TaskParamsBuilder) validate() (map[string]interface{}, error) {
+	defs := make(map[string]*types.CommandParameter, len(b.command.Parameters))
+	for _, def := range b.command.Parameters {
+		defs[def.Name] = def
+	}
+
+	// Sort names so errors are deterministic
+	names := make([]string, 0, len(b.params))
+	for name := range b.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := make(map[string]interface{}, len(b.params))
+	for _, name := range names {
+		def, ok := defs[name]
+		if !ok {
+			return nil, WrapError("TaskParamsBuilder.Build", ErrInvalidInput, fmt.Sprintf("unknown parameter %q", name))
+		}
+		val, ok := coerceParamValue(def.Type, b.params[name])
+		if !ok {
+			return nil, WrapError("TaskParamsBuilder.Build", ErrInvalidInput, fmt.Sprintf("parameter %q expects type %s, got %T", name, def.Type, b.params[name]))
+		}
+		params[name] = val
+	}
+
+	var missing []string
+	for _, def := range b.command.Parameters {
+		if !def.Required || def.DefaultValue != "" {
+			continue
+		}
+		if _, ok := b.params[def.Name]; !ok {
+			missing = append(missing, def.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, WrapError("TaskParamsBuilder.Build", ErrInvalidInput, "missing required parameters: "+strings.Join(missing, ", "))
+	}
+
+	return params, nil
+}
+
+// coerceParamValue converts val to the JSON representation of a Mythic
+// parameter type, reporting false if it cannot. Types without a builder
+// setter accept any value unchanged.
+func coerceParamValue(paramType string, val interface{}) (interface{}, bool) {
+	switch paramType {
+	case "String", "ChooseOne", "File":
+		s, ok := val.(string)
+		return s, ok
+	case "Boolean":
+		switch v := val.(type) {
+		case bool:
+			return v, true
+		case string:
+			parsed, err := strconv.ParseBool(v)
+			return parsed, err == nil
+		}
+		return nil, false
+	case "Number":
+		switch v := val.(type) {
+		case float64:
+			return v, true
+		case float32:
+			return float64(v), true
+		case int:
+			return v, true
+		case int8:
+			return v, true
+		case int16:
+			return v, true
+		case int32:
+			return v, true
+		case int64:
+			return v, true
+		case uint:
+			return v, true
+		case uint8:
+			return v, true
+		case uint16:
+			return v, true
+		case uint32:
+			return v, true
+		case uint64:
+			return v, true
+		case json.Number:
+			_, err := v.Float64()
+			return v, err == nil
+		case string:
+			parsed, err := strconv.ParseFloat(v, 64)
+			return parsed, err == nil
+		}
+		return nil, false
+	case "Array", "ChooseMultiple":
+		switch v := val.(type) {
+		case []string:
+			return v, true
+		case []interface{}:
+			out := make([]string, len(v))
+			for i, elem := range v {
+				s, ok := elem.(string)
+				if !ok {
+					return nil, false
+				}
+				out[i] = s
+			}
+			return out, true
+		}
+		return nil, false
+	default:
+		return val, true
+	}
 }
 
 // TaskStatus represents the status of a task.
@@ -101,19 +348,57 @@ func (c *Client) IssueTask(ctx context.Context, req *TaskRequest) (*Task, error)
 	}
 
 	// Validate request
-	if req.CallbackID == nil && len(req.CallbackIDs) == 0 {
-		return nil, WrapError("IssueTask", ErrInvalidInput, "either callback_id or callback_ids must be provided")
+	if req.CallbackID == nil && len(req.CallbackIDs) == 0 && req.AgentCallbackID == "" {
+		return nil, WrapError("IssueTask", ErrInvalidInput, "either callback_id, callback_ids, or agent_callback_id must be provided")
 	}
 	if req.Command == "" {
 		return nil, WrapError("IssueTask", ErrInvalidInput, "command is required")
 	}
 
+	if req.Validate {
+		if err := c.ValidateTaskRequest(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	// Numeric callback IDs take precedence over the agent callback ID
+	callbackID := req.CallbackID
+	if callbackID == nil && len(req.CallbackIDs) == 0 {
+		callback, err := c.GetCallbackByAgentID(ctx, req.AgentCallbackID)
+		if err != nil {
+			return nil, WrapError("IssueTask", ErrInvalidInput, fmt.Sprintf("agent_callback_id %s did not resolve to a callback: %v", req.AgentCallbackID, err))
+		}
+		callbackID = &callback.DisplayID
+	}
+
+	params := req.Params
+	if req.ParamsMap != nil {
+		if req.Params != "" || req.ParamsBuilder != nil {
+			return nil, WrapError("IssueTask", ErrInvalidInput, "params map cannot be combined with params or params builder")
+		}
+		data, err := json.Marshal(req.ParamsMap)
+		if err != nil {
+			return nil, WrapError("IssueTask", ErrInvalidInput, fmt.Sprintf("failed to encode params map: %v", err))
+		}
+		params = string(data)
+	}
+	if req.ParamsBuilder != nil {
+		if req.Params != "" {
+			return nil, WrapError("IssueTask", ErrInvalidInput, "params and params builder cannot both be set")
+		}
+		built, err := req.ParamsBuilder.Build()
+		if err != nil {
+			return nil, WrapError("IssueTask", err, "invalid task parameters")
+		}
+		params = built
+	}
+
 	// Build request payload - only include non-nil/non-empty values
 	// This allows the webhook to properly distinguish "not provided" from "empty"
 	payload := map[string]interface{}{
 		"input": map[string]interface{}{
 			"command":             req.Command,
-			"params":              req.Params,
+			"params":              params,
 			"is_interactive_task": req.IsInteractiveTask,
 		},
 	}
@@ -124,8 +409,8 @@ func (c *Client) IssueTask(ctx context.Context, req *TaskRequest) (*Task, error)
 	}
 
 	// Only include callback_id OR callback_ids, not both
-	if req.CallbackID != nil {
-		input["callback_id"] = req.CallbackID
+	if callbackID != nil {
+		input["callback_id"] = callbackID
 	}
 	if len(req.CallbackIDs) > 0 {
 		input["callback_ids"] = req.CallbackIDs
@@ -176,7 +461,227 @@ func (c *Client) IssueTask(ctx context.Context, req *TaskRequest) (*Task, error)
 	}
 
 	// Get the full task details
-	return c.GetTask(ctx, response.DisplayID)
+	return c.getCreatedTask(ctx, response.DisplayID)
+}
+
+const (
+	// defaultTaskLookupAttempts is used when Config.TaskLookupAttempts is unset
+	defaultTaskLookupAttempts = 3
+	// taskLookupRetryBase is the delay before the first retry, doubled after each
+	taskLookupRetryBase = 250 * time.Millisecond
+)
+
+// getCreatedTask fetches a task that was just created. Under load the new row
+// may not be queryable yet, so ErrNotFound is retried with backoff.
+func (c *Client) getCreatedTask(ctx context.Context, displayID int) (*Task, error) {
+	attempts := c.config.TaskLookupAttempts
+	if attempts <= 0 {
+		attempts = defaultTaskLookupAttempts
+	}
+
+	delay := taskLookupRetryBase
+	for attempt := 1; ; attempt++ {
+		task, err := c.GetTask(ctx, displayID)
+		if err == nil || !errors.Is(err, ErrNotFound) || attempt >= attempts {
+			return task, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// ValidateTaskRequest checks a task request against the commands registered
+// for each target callback's payload type. It verifies that the command
+// exists and, when the parameters are given as JSON (Params, ParamsMap or
+// ParamsBuilder), that every required parameter without a default is present.
+// Free-form Params strings are left for the agent to parse.
+func (c *Client) ValidateTaskRequest(ctx context.Context, req *TaskRequest) error {
+	if req == nil {
+		return WrapError("ValidateTaskRequest", ErrInvalidInput, "task request is required")
+	}
+	if req.Command == "" {
+		return WrapError("ValidateTaskRequest", ErrInvalidInput, "command is required")
+	}
+
+	var callbacks []*types.Callback
+	switch {
+	case req.CallbackID != nil || len(req.CallbackIDs) > 0:
+		ids := req.CallbackIDs
+		if req.CallbackID != nil {
+			ids = append([]int{*req.CallbackID}, ids...)
+		}
+		for _, id := range ids {
+			callback, err := c.GetCallbackByID(ctx, id)
+			if err != nil {
+				return WrapError("ValidateTaskRequest", err, fmt.Sprintf("failed to get callback %d", id))
+			}
+			callbacks = append(callbacks, callback)
+		}
+	case req.AgentCallbackID != "":
+		callback, err := c.GetCallbackByAgentID(ctx, req.AgentCallbackID)
+		if err != nil {
+			return WrapError("ValidateTaskRequest", err, fmt.Sprintf("failed to get callback %s", req.AgentCallbackID))
+		}
+		callbacks = append(callbacks, callback)
+	default:
+		return WrapError("ValidateTaskRequest", ErrInvalidInput, "either callback_id, callback_ids, or agent_callback_id must be provided")
+	}
+
+	params, hasParams := taskRequestParamsMap(req)
+
+	checked := make(map[int]bool)
+	for _, callback := range callbacks {
+		if checked[callback.PayloadTypeID] {
+			continue
+		}
+		checked[callback.PayloadTypeID] = true
+
+		commands, err := c.GetCommandsByPayloadType(ctx, callback.PayloadTypeID)
+		if err != nil {
+			return WrapError("ValidateTaskRequest", err, "failed to get commands")
+		}
+		found := false
+		for _, cmd := range commands {
+			if cmd.Cmd == req.Command {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return WrapError("ValidateTaskRequest", ErrInvalidInput, fmt.Sprintf("command %q is not available for callback %d", req.Command, callback.DisplayID))
+		}
+
+		if !hasParams {
+			continue
+		}
+		command, err := c.GetCommandWithParameters(ctx, callback.PayloadTypeID, req.Command)
+		if err != nil {
+			return WrapError("ValidateTaskRequest", err, "failed to get command parameters")
+		}
+		var missing []string
+		for _, def := range command.Parameters {
+			if !def.Required || def.DefaultValue != "" {
+				continue
+			}
+			if _, ok := params[def.Name]; !ok {
+				missing = append(missing, def.Name)
+			}
+		}
+		if len(missing) > 0 {
+			return WrapError("ValidateTaskRequest", ErrInvalidInput, fmt.Sprintf("missing required parameters for command %q: %s", req.Command, strings.Join(missing, ", ")))
+		}
+	}
+
+	return nil
+}
+
+// taskRequestParamsMap returns the request's parameters as a map, if they
+// were given in structured form. A Params string that is not a JSON object
+// yields false.
+func taskRequestParamsMap(req *TaskRequest) (map[string]interface{}, bool) {
+	switch {
+	case req.ParamsMap != nil:
+		return req.ParamsMap, true
+	case req.ParamsBuilder != nil:
+		return req.ParamsBuilder.params, true
+	case req.Params == "":
+		return map[string]interface{}{}, true
+	}
+
+	var params map[string]interface{}
+	if err := json.Unmarshal([]byte(req.Params), &params); err != nil || params == nil {
+		return nil, false
+	}
+	return params, true
+}
+
+// issueTaskBulkWorkers bounds how many callbacks IssueTaskBulk tasks at once.
+const issueTaskBulkWorkers = 8
+
+// IssueTaskBulk issues the same task to every callback in req.CallbackIDs
+// individually, so that a failure on one callback does not prevent the others
+// from being tasked. Up to 8 callbacks are tasked concurrently.
+//
+// The returned slices are parallel to req.CallbackIDs: for each index either
+// the Task or the error is non-nil. The top-level error is only set when the
+// request is invalid or every callback failed.
+func (c *Client) IssueTaskBulk(ctx context.Context, req *TaskRequest) ([]*Task, []error, error) {
+	if req == nil {
+		return nil, nil, WrapError("IssueTaskBulk", ErrInvalidInput, "task request is required")
+	}
+	if len(req.CallbackIDs) == 0 {
+		return nil, nil, WrapError("IssueTaskBulk", ErrInvalidInput, "callback_ids must be provided")
+	}
+	if req.Command == "" {
+		return nil, nil, WrapError("IssueTaskBulk", ErrInvalidInput, "command is required")
+	}
+
+	tasks := make([]*Task, len(req.CallbackIDs))
+	errs := make([]error, len(req.CallbackIDs))
+
+	// Each worker writes only its own index, so the slices need no locking
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, issueTaskBulkWorkers)
+
+	for i, callbackID := range req.CallbackIDs {
+		// Issue against a single callback using a copy of the original request
+		single := *req
+		id := callbackID
+		single.CallbackID = &id
+		single.CallbackIDs = nil
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, single *TaskRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			task, err := c.IssueTask(ctx, single)
+			if err != nil {
+				errs[i] = WrapError("IssueTaskBulk", err, fmt.Sprintf("failed to task callback %d", *single.CallbackID))
+				return
+			}
+			tasks[i] = task
+		}(i, &single)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+
+	if failed == len(req.CallbackIDs) {
+		return tasks, errs, WrapError("IssueTaskBulk", ErrOperationFailed, fmt.Sprintf("task creation failed for all %d callbacks", failed))
+	}
+
+	return tasks, errs, nil
+}
+
+// BulkTaskErrors indexes the per-callback errors returned by IssueTaskBulk by
+// callback ID, leaving out callbacks that were tasked successfully.
+//
+// Example:
+//
+//	_, errs, err := client.IssueTaskBulk(ctx, req)
+//	for callbackID, err := range mythic.BulkTaskErrors(req.CallbackIDs, errs) {
+//	    log.Printf("callback %d rejected the task: %v", callbackID, err)
+//	}
+func BulkTaskErrors(callbackIDs []int, errs []error) map[int]error {
+	failed := make(map[int]error)
+	for i, err := range errs {
+		if err != nil && i < len(callbackIDs) {
+			failed[callbackIDs[i]] = err
+		}
+	}
+	return failed
 }
 
 // ScriptOnlyTaskRequest represents a request to issue a script_only command.
@@ -243,63 +748,45 @@ func (c *Client) IssueScriptOnlyTask(ctx context.Context, req *ScriptOnlyTaskReq
 	return c.IssueTask(ctx, taskReq)
 }
 
-// GetTask retrieves a task by its display ID.
-func (c *Client) GetTask(ctx context.Context, displayID int) (*Task, error) {
-	if err := c.EnsureAuthenticated(ctx); err != nil {
-		return nil, err
-	}
-
-	var query struct {
-		Task []struct {
-			ID                        int    `graphql:"id"`
-			DisplayID                 int    `graphql:"display_id"`
-			AgentTaskID               string `graphql:"agent_task_id"`
-			CommandName               string `graphql:"command_name"`
-			Params                    string `graphql:"params"`
-			DisplayParams             string `graphql:"display_params"`
-			OriginalParams            string `graphql:"original_params"`
-			Status                    string `graphql:"status"`
-			Completed                 bool   `graphql:"completed"`
-			Comment                   string `graphql:"comment"`
-			Timestamp                 string `graphql:"timestamp"` // Use string to handle Mythic's timestamp format
-			CallbackID                int    `graphql:"callback_id"`
-			OperatorID                int    `graphql:"operator_id"`
-			OperationID               int    `graphql:"operation_id"`
-			ParentTaskID              *int   `graphql:"parent_task_id"`
-			ResponseCount             int    `graphql:"response_count"`
-			IsInteractiveTask         bool   `graphql:"is_interactive_task"`
-			InteractiveTaskType       *int   `graphql:"interactive_task_type"`
-			TaskingLocation           string `graphql:"tasking_location"`
-			ParameterGroupName        string `graphql:"parameter_group_name"`
-			Stdout                    string `graphql:"stdout"`
-			Stderr                    string `graphql:"stderr"`
-			CompletedCallbackFunction string `graphql:"completed_callback_function"`
-			SubtaskCallbackFunction   string `graphql:"subtask_callback_function"`
-			GroupCallbackFunction     string `graphql:"group_callback_function"`
-			OpsecPreBlocked           *bool  `graphql:"opsec_pre_blocked"`
-			OpsecPreBypassed          bool   `graphql:"opsec_pre_bypassed"`
-			OpsecPreMessage           string `graphql:"opsec_pre_message"`
-			OpsecPostBlocked          *bool  `graphql:"opsec_post_blocked"`
-			OpsecPostBypassed         bool   `graphql:"opsec_post_bypassed"`
-			OpsecPostMessage          string `graphql:"opsec_post_message"`
-		} `graphql:"task(where: {display_id: {_eq: $display_id}}, limit: 1)"`
-	}
-
-	variables := map[string]interface{}{
-		"display_id": displayID,
-	}
-
-	err := c.executeQuery(ctx, &query, variables)
-	if err != nil {
-		return nil, WrapError("GetTask", err, "failed to query task")
-	}
-
-	if len(query.Task) == 0 {
-		return nil, WrapError("GetTask", ErrNotFound, fmt.Sprintf("task with display_id %d not found", displayID))
-	}
-
-	t := query.Task[0]
+// taskQueryFields is the full set of task columns selected by GraphQL queries
+// that return complete Task objects.
+type taskQueryFields struct {
+	ID                        int    `graphql:"id"`
+	DisplayID                 int    `graphql:"display_id"`
+	AgentTaskID               string `graphql:"agent_task_id"`
+	CommandName               string `graphql:"command_name"`
+	Params                    string `graphql:"params"`
+	DisplayParams             string `graphql:"display_params"`
+	OriginalParams            string `graphql:"original_params"`
+	Status                    string `graphql:"status"`
+	Completed                 bool   `graphql:"completed"`
+	Comment                   string `graphql:"comment"`
+	Timestamp                 string `graphql:"timestamp"` // Use string to handle Mythic's timestamp format
+	CallbackID                int    `graphql:"callback_id"`
+	OperatorID                int    `graphql:"operator_id"`
+	OperationID               int    `graphql:"operation_id"`
+	ParentTaskID              *int   `graphql:"parent_task_id"`
+	TokenID                   *int   `graphql:"token_id"`
+	ResponseCount             int    `graphql:"response_count"`
+	IsInteractiveTask         bool   `graphql:"is_interactive_task"`
+	InteractiveTaskType       *int   `graphql:"interactive_task_type"`
+	TaskingLocation           string `graphql:"tasking_location"`
+	ParameterGroupName        string `graphql:"parameter_group_name"`
+	Stdout                    string `graphql:"stdout"`
+	Stderr                    string `graphql:"stderr"`
+	CompletedCallbackFunction string `graphql:"completed_callback_function"`
+	SubtaskCallbackFunction   string `graphql:"subtask_callback_function"`
+	GroupCallbackFunction     string `graphql:"group_callback_function"`
+	OpsecPreBlocked           *bool  `graphql:"opsec_pre_blocked"`
+	OpsecPreBypassed          bool   `graphql:"opsec_pre_bypassed"`
+	OpsecPreMessage           string `graphql:"opsec_pre_message"`
+	OpsecPostBlocked          *bool  `graphql:"opsec_post_blocked"`
+	OpsecPostBypassed         bool   `graphql:"opsec_post_bypassed"`
+	OpsecPostMessage          string `graphql:"opsec_post_message"`
+}
 
+// toTask converts the queried task columns into a Task.
+func (t taskQueryFields) toTask() *Task {
 	// Parse timestamp string (Mythic returns timestamps without timezone)
 	timestamp, err := parseTimestamp(t.Timestamp)
 	if err != nil {
@@ -323,6 +810,7 @@ func (c *Client) GetTask(ctx context.Context, displayID int) (*Task, error) {
 		OperatorID:                t.OperatorID,
 		OperationID:               t.OperationID,
 		ParentTaskID:              t.ParentTaskID,
+		TokenID:                   t.TokenID,
 		ResponseCount:             t.ResponseCount,
 		IsInteractiveTask:         t.IsInteractiveTask,
 		InteractiveTaskType:       t.InteractiveTaskType,
@@ -339,284 +827,1381 @@ func (c *Client) GetTask(ctx context.Context, displayID int) (*Task, error) {
 		OpsecPostBlocked:          t.OpsecPostBlocked,
 		OpsecPostBypassed:         t.OpsecPostBypassed,
 		OpsecPostMessage:          t.OpsecPostMessage,
-	}, nil
+	}
 }
 
-// GetTasksForCallback retrieves all tasks for a specific callback.
-func (c *Client) GetTasksForCallback(ctx context.Context, callbackDisplayID int, limit int) ([]*Task, error) {
+// GetTask retrieves a task by its display ID.
+func (c *Client) GetTask(ctx context.Context, displayID int) (*Task, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
-	if limit <= 0 {
-		limit = 100 // Default limit
-	}
-
-	// First get the callback's actual ID
-	callback, err := c.GetCallbackByID(ctx, callbackDisplayID)
-	if err != nil {
-		return nil, WrapError("GetTasksForCallback", err, "failed to get callback")
-	}
-
 	var query struct {
-		Task []struct {
-			ID                int    `graphql:"id"`
-			DisplayID         int    `graphql:"display_id"`
-			AgentTaskID       string `graphql:"agent_task_id"`
-			CommandName       string `graphql:"command_name"`
-			Params            string `graphql:"params"`
-			DisplayParams     string `graphql:"display_params"`
-			Status            string `graphql:"status"`
-			Completed         bool   `graphql:"completed"`
-			Comment           string `graphql:"comment"`
-			Timestamp         string `graphql:"timestamp"`
-			CallbackID        int    `graphql:"callback_id"`
-			ResponseCount     int    `graphql:"response_count"`
-			IsInteractiveTask bool   `graphql:"is_interactive_task"`
-		} `graphql:"task(where: {callback_id: {_eq: $callback_id}}, order_by: {id: desc}, limit: $limit)"`
+		Task []taskQueryFields `graphql:"task(where: {display_id: {_eq: $display_id}}, limit: 1)"`
 	}
 
 	variables := map[string]interface{}{
-		"callback_id": callback.ID,
-		"limit":       limit,
+		"display_id": displayID,
 	}
 
-	err = c.executeQuery(ctx, &query, variables)
+	err := c.executeQuery(ctx, &query, variables)
 	if err != nil {
-		return nil, WrapError("GetTasksForCallback", err, "failed to query tasks")
+		return nil, WrapError("GetTask", err, "failed to query task")
 	}
 
-	tasks := make([]*Task, 0, len(query.Task))
-	for _, t := range query.Task {
-		// Parse timestamp - Mythic v3.4.20 returns timestamps without timezone
-		timestamp, err := parseTimestamp(t.Timestamp)
-		if err != nil {
-			timestamp = time.Time{}
-		}
-
-		tasks = append(tasks, &Task{
-			ID:                t.ID,
-			DisplayID:         t.DisplayID,
-			AgentTaskID:       t.AgentTaskID,
-			CommandName:       t.CommandName,
-			Params:            t.Params,
-			DisplayParams:     t.DisplayParams,
-			Status:            t.Status,
-			Completed:         t.Completed,
-			Comment:           t.Comment,
-			Timestamp:         timestamp,
-			CallbackID:        t.CallbackID,
-			ResponseCount:     t.ResponseCount,
-			IsInteractiveTask: t.IsInteractiveTask,
-		})
+	if len(query.Task) == 0 {
+		return nil, WrapError("GetTask", ErrNotFound, fmt.Sprintf("task with display_id %d not found", displayID))
 	}
 
-	return tasks, nil
+	return query.Task[0].toTask(), nil
 }
 
-// GetTasksByDisplayIDs retrieves multiple tasks by their display IDs in a single
-// GraphQL query. Returns tasks in the order they are found. Tasks that don't
-// exist are silently omitted from the result.
-func (c *Client) GetTasksByDisplayIDs(ctx context.Context, displayIDs []int) ([]*Task, error) {
+// GetTaskByInternalID retrieves a task by its internal database ID (Task.ID),
+// as returned by IssueTask, without first resolving a display ID.
+func (c *Client) GetTaskByInternalID(ctx context.Context, id int) (*Task, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
-	if len(displayIDs) == 0 {
-		return nil, WrapError("GetTasksByDisplayIDs", ErrInvalidInput, "at least one display_id is required")
+	if id <= 0 {
+		return nil, WrapError("GetTaskByInternalID", ErrInvalidInput, "task ID must be positive")
 	}
 
 	var query struct {
-		Task []struct {
-			ID                        int    `graphql:"id"`
-			DisplayID                 int    `graphql:"display_id"`
-			AgentTaskID               string `graphql:"agent_task_id"`
-			CommandName               string `graphql:"command_name"`
-			Params                    string `graphql:"params"`
-			DisplayParams             string `graphql:"display_params"`
-			OriginalParams            string `graphql:"original_params"`
-			Status                    string `graphql:"status"`
-			Completed                 bool   `graphql:"completed"`
-			Comment                   string `graphql:"comment"`
-			Timestamp                 string `graphql:"timestamp"`
-			CallbackID                int    `graphql:"callback_id"`
-			OperatorID                int    `graphql:"operator_id"`
-			OperationID               int    `graphql:"operation_id"`
-			ResponseCount             int    `graphql:"response_count"`
-			IsInteractiveTask         bool   `graphql:"is_interactive_task"`
-			Stdout                    string `graphql:"stdout"`
-			Stderr                    string `graphql:"stderr"`
-			OpsecPreBlocked           *bool  `graphql:"opsec_pre_blocked"`
-			OpsecPreBypassed          bool   `graphql:"opsec_pre_bypassed"`
-			OpsecPostBlocked          *bool  `graphql:"opsec_post_blocked"`
-			OpsecPostBypassed         bool   `graphql:"opsec_post_bypassed"`
-		} `graphql:"task(where: {display_id: {_in: $display_ids}}, order_by: {display_id: asc})"`
+		Task []taskQueryFields `graphql:"task(where: {id: {_eq: $id}}, limit: 1)"`
 	}
 
 	variables := map[string]interface{}{
-		"display_ids": displayIDs,
+		"id": id,
 	}
 
 	err := c.executeQuery(ctx, &query, variables)
 	if err != nil {
-		return nil, WrapError("GetTasksByDisplayIDs", err, "failed to query tasks")
+		return nil, WrapError("GetTaskByInternalID", err, "failed to query task")
 	}
 
-	tasks := make([]*Task, 0, len(query.Task))
-	for _, t := range query.Task {
-		timestamp, err := parseTimestamp(t.Timestamp)
-		if err != nil {
-			timestamp = time.Time{}
-		}
-
-		tasks = append(tasks, &Task{
-			ID:                t.ID,
-			DisplayID:         t.DisplayID,
-			AgentTaskID:       t.AgentTaskID,
-			CommandName:       t.CommandName,
-			Params:            t.Params,
-			DisplayParams:     t.DisplayParams,
-			OriginalParams:    t.OriginalParams,
-			Status:            t.Status,
-			Completed:         t.Completed,
-			Comment:           t.Comment,
-			Timestamp:         timestamp,
-			CallbackID:        t.CallbackID,
-			OperatorID:        t.OperatorID,
-			OperationID:       t.OperationID,
-			ResponseCount:     t.ResponseCount,
-			IsInteractiveTask: t.IsInteractiveTask,
-			Stdout:            t.Stdout,
-			Stderr:            t.Stderr,
-			OpsecPreBlocked:   t.OpsecPreBlocked,
-			OpsecPreBypassed:  t.OpsecPreBypassed,
-			OpsecPostBlocked:  t.OpsecPostBlocked,
-			OpsecPostBypassed: t.OpsecPostBypassed,
-		})
+	if len(query.Task) == 0 {
+		return nil, WrapError("GetTaskByInternalID", ErrNotFound, fmt.Sprintf("task with id %d not found", id))
 	}
 
-	return tasks, nil
+	return query.Task[0].toTask(), nil
 }
 
-// GetTaskOutput retrieves all responses (output) for a task.
-func (c *Client) GetTaskOutput(ctx context.Context, taskDisplayID int) ([]*TaskResponse, error) {
+// GetTaskByAgentTaskID retrieves a task by the agent_task_id UUID that agents
+// use to reference tasks.
+func (c *Client) GetTaskByAgentTaskID(ctx context.Context, agentTaskID string) (*Task, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
 		return nil, err
 	}
 
-	// First get the task's actual ID
-	task, err := c.GetTask(ctx, taskDisplayID)
-	if err != nil {
-		return nil, WrapError("GetTaskOutput", err, "failed to get task")
+	if agentTaskID == "" {
+		return nil, WrapError("GetTaskByAgentTaskID", ErrInvalidInput, "agent_task_id is required")
 	}
 
 	var query struct {
-		Response []struct {
-			ID             int    `graphql:"id"`
-			TaskID         int    `graphql:"task_id"`
-			ResponseText   string `graphql:"response_text"`
-			IsError        bool   `graphql:"is_error"`
-			Timestamp      string `graphql:"timestamp"`
-			SequenceNumber *int   `graphql:"sequence_number"`
-		} `graphql:"response(where: {task_id: {_eq: $task_id}}, order_by: {id: asc})"`
+		Task []taskQueryFields `graphql:"task(where: {agent_task_id: {_eq: $agent_task_id}}, limit: 1)"`
 	}
 
 	variables := map[string]interface{}{
-		"task_id": task.ID,
+		"agent_task_id": agentTaskID,
 	}
 
-	err = c.executeQuery(ctx, &query, variables)
+	err := c.executeQuery(ctx, &query, variables)
 	if err != nil {
-		return nil, WrapError("GetTaskOutput", err, "failed to query responses")
+		return nil, WrapError("GetTaskByAgentTaskID", err, "failed to query task")
 	}
 
-	responses := make([]*TaskResponse, 0, len(query.Response))
-	for _, r := range query.Response {
-		// Parse timestamp - Mythic v3.4.20 returns timestamps without timezone
-		timestamp, err := parseTimestamp(r.Timestamp)
-		if err != nil {
-			// Log warning but continue - don't fail the entire operation for timestamp parsing
-			timestamp = time.Time{}
-		}
-
-		responses = append(responses, &TaskResponse{
-			ID:             r.ID,
-			TaskID:         r.TaskID,
-			ResponseText:   r.ResponseText,
-			IsError:        r.IsError,
-			Timestamp:      timestamp,
-			SequenceNumber: r.SequenceNumber,
-		})
+	if len(query.Task) == 0 {
+		return nil, WrapError("GetTaskByAgentTaskID", ErrNotFound, fmt.Sprintf("task with agent_task_id %s not found", agentTaskID))
 	}
 
-	return responses, nil
+	return query.Task[0].toTask(), nil
 }
 
-// WaitForTaskComplete polls a task until it completes or times out.
-// Returns an error if the task fails or times out.
-func (c *Client) WaitForTaskComplete(ctx context.Context, taskDisplayID int, timeoutSeconds int) error {
-	return c.WaitForTaskCompleteWithOptions(ctx, taskDisplayID, timeoutSeconds, false)
+// TaskExpandOptions selects which related records GetTaskExpanded includes.
+// A nil *TaskExpandOptions includes everything.
+type TaskExpandOptions struct {
+	IncludeCallback bool
+	IncludeOperator bool
+	IncludeToken    bool
 }
 
-// WaitForTaskCompleteWithOptions polls a task until it completes or times out.
+// TaskExpanded is a Task along with the callback, operator, and token it was
+// issued with. Related records that were not requested, or that do not exist
+// (e.g. a task issued without a token), are left nil or empty.
+type TaskExpanded struct {
+	*Task
+	Callback         *types.Callback `json:"callback,omitempty"`
+	OperatorUsername string          `json:"operator_username,omitempty"`
+	Token            *types.Token    `json:"token,omitempty"`
+}
+
+// GetTaskExpanded retrieves a task by its display ID together with its
+// callback, operator username, and token in a single query.
+func (c *Client) GetTaskExpanded(ctx context.Context, displayID int, opts *TaskExpandOptions) (*TaskExpanded, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = &TaskExpandOptions{IncludeCallback: true, IncludeOperator: true, IncludeToken: true}
+	}
+
+	var query struct {
+		Task []struct {
+			taskQueryFields
+			Callback callbackQueryFields `graphql:"callback @include(if: $include_callback)"`
+			Operator struct {
+				Username string `graphql:"username"`
+			} `graphql:"operator @include(if: $include_operator)"`
+			Token *struct {
+				ID         int    `graphql:"id"`
+				TokenID    string `graphql:"token_id"`
+				User       string `graphql:"user"`
+				Groups     string `graphql:"groups"`
+				Privileges string `graphql:"privileges"`
+				ThreadID   int    `graphql:"thread_id"`
+				ProcessID  int    `graphql:"process_id"`
+				SessionID  int    `graphql:"session_id"`
+				LogonSID   string `graphql:"logon_sid"`
+				Host       string `graphql:"host"`
+				Deleted    bool   `graphql:"deleted"`
+			} `graphql:"token @include(if: $include_token)"`
+		} `graphql:"task(where: {display_id: {_eq: $display_id}}, limit: 1)"`
+	}
+
+	variables := map[string]interface{}{
+		"display_id":       displayID,
+		"include_callback": opts.IncludeCallback,
+		"include_operator": opts.IncludeOperator,
+		"include_token":    opts.IncludeToken,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetTaskExpanded", err, "failed to query task")
+	}
+
+	if len(query.Task) == 0 {
+		return nil, WrapError("GetTaskExpanded", ErrNotFound, fmt.Sprintf("task with display_id %d not found", displayID))
+	}
+
+	t := query.Task[0]
+	expanded := &TaskExpanded{
+		Task:             t.toTask(),
+		OperatorUsername: t.Operator.Username,
+	}
+
+	if opts.IncludeCallback {
+		expanded.Callback = t.Callback.toCallback()
+	}
+
+	if t.Token != nil {
+		expanded.Token = &types.Token{
+			ID:         t.Token.ID,
+			TokenID:    t.Token.TokenID,
+			User:       t.Token.User,
+			Groups:     t.Token.Groups,
+			Privileges: t.Token.Privileges,
+			ThreadID:   t.Token.ThreadID,
+			ProcessID:  t.Token.ProcessID,
+			SessionID:  t.Token.SessionID,
+			LogonSID:   t.Token.LogonSID,
+			Host:       t.Token.Host,
+			Deleted:    t.Token.Deleted,
+		}
+	}
+
+	return expanded, nil
+}
+
+// GetTasksForCallback retrieves all tasks for a specific callback.
+func (c *Client) GetTasksForCallback(ctx context.Context, callbackDisplayID int, limit int) ([]*Task, error) {
+	if limit < 0 {
+		limit = 0
+	}
+	return c.GetTasksForCallbackFiltered(ctx, callbackDisplayID, &TaskQueryOptions{Limit: limit})
+}
+
+// TaskQueryOptions filters the tasks returned by GetTasksForCallbackFiltered.
+// Zero-valued fields are left out of the where clause.
+type TaskQueryOptions struct {
+	// Limit caps the number of tasks returned (default 100)
+	Limit int
+
+	// CommandName restricts results to tasks of this command (e.g. "ls")
+	CommandName string
+
+	// Status restricts results to tasks in this status
+	Status TaskStatus
+
+	// Since restricts results to tasks created at or after this time
+	Since time.Time
+
+	// Until restricts results to tasks created before this time
+	Until time.Time
+
+	// CompletedOnly restricts results to completed tasks
+	CompletedOnly bool
+
+	// Offset skips this many matching tasks, for paging through results
+	Offset int
+
+	// Ascending returns the oldest tasks first instead of the newest
+	Ascending bool
+}
+
+// GetTasksForCallbackFiltered retrieves tasks for a specific callback matching
+// opts, newest first unless opts.Ascending is set. Filtering is applied
+// server-side, so for example all ls tasks from the last hour can be fetched
+// with
+//
+//	&TaskQueryOptions{CommandName: "ls", Since: time.Now().Add(-time.Hour)}
+//
+// and all failed ls tasks with
+//
+//	&TaskQueryOptions{CommandName: "ls", Status: TaskStatusError}
+func (c *Client) GetTasksForCallbackFiltered(ctx context.Context, callbackDisplayID int, opts *TaskQueryOptions) ([]*Task, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if opts == nil {
+		opts = &TaskQueryOptions{}
+	}
+
+	if opts.Limit < 0 {
+		return nil, WrapError("GetTasksForCallbackFiltered", ErrInvalidInput, "limit cannot be negative")
+	}
+	if opts.Offset < 0 {
+		return nil, WrapError("GetTasksForCallbackFiltered", ErrInvalidInput, "offset cannot be negative")
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+		return nil, WrapError("GetTasksForCallbackFiltered", ErrInvalidInput, "since must be before until")
+	}
+
+	limit := opts.Limit
+	if limit == 0 {
+		limit = 100 // Default limit
+	}
+
+	// First get the callback's actual ID
+	callback, err := c.GetCallbackByID(ctx, callbackDisplayID)
+	if err != nil {
+		return nil, WrapError("GetTasksForCallbackFiltered", err, "failed to get callback")
+	}
+
+	where := newBoolExp("task")
+	where.conds["callback_id"] = map[string]interface{}{"_eq": callback.ID}
+	if opts.CommandName != "" {
+		where.conds["command_name"] = map[string]interface{}{"_eq": opts.CommandName}
+	}
+	if opts.Status != "" {
+		where.conds["status"] = map[string]interface{}{"_eq": string(opts.Status)}
+	}
+	if opts.CompletedOnly {
+		where.conds["completed"] = map[string]interface{}{"_eq": true}
+	}
+
+	// timestamp is stored in UTC without a timezone
+	timestamp := make(map[string]interface{})
+	if !opts.Since.IsZero() {
+		timestamp["_gte"] = opts.Since.UTC().Format(time.RFC3339)
+	}
+	if !opts.Until.IsZero() {
+		timestamp["_lt"] = opts.Until.UTC().Format(time.RFC3339)
+	}
+	if len(timestamp) > 0 {
+		where.conds["timestamp"] = timestamp
+	}
+
+	direction := "desc"
+	if opts.Ascending {
+		direction = "asc"
+	}
+
+	var query struct {
+		Task []taskQueryFields `graphql:"task(where: $where, order_by: $order_by, limit: $limit, offset: $offset)"`
+	}
+
+	variables := map[string]interface{}{
+		"where":    where,
+		"order_by": newOrderBy("task").then("id", direction),
+		"limit":    limit,
+		"offset":   opts.Offset,
+	}
+
+	err = c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetTasksForCallbackFiltered", err, "failed to query tasks")
+	}
+
+	tasks := make([]*Task, len(query.Task))
+	for i, t := range query.Task {
+		tasks[i] = t.toTask()
+	}
+
+	return tasks, nil
+}
+
+// TaskSearchRequest filters the tasks returned by SearchTasks. Nil and
+// zero-valued fields are left out of the where clause.
+type TaskSearchRequest struct {
+	// CommandName restricts results to tasks of this command (e.g. "ls")
+	CommandName string
+
+	// OperatorID restricts results to tasks issued by this operator
+	OperatorID *int
+
+	// Status restricts results to tasks in this status
+	Status TaskStatus
+
+	// CallbackID restricts results to one callback's tasks, by the callback's
+	// internal ID (Task.CallbackID)
+	CallbackID *int
+
+	// OperationID selects the operation to search (default: current operation)
+	OperationID *int
+
+	// StartTime restricts results to tasks created at or after this time
+	StartTime *time.Time
+
+	// EndTime restricts results to tasks created at or before this time
+	EndTime *time.Time
+
+	// Limit caps the number of tasks returned (default 100)
+	Limit int
+
+	// Offset skips this many matching tasks, for paging through results
+	Offset int
+
+	// SortOrder is "desc" (default, newest first) or "asc"
+	SortOrder string
+}
+
+// SearchTasks searches tasks across an operation, for example every task an
+// operator ran in a time window, ordered by timestamp. It searches the
+// current operation unless req.OperationID is set, and mirrors
+// SearchResponses for task output.
+//
+// Example:
+//
+//	operatorID := 2
+//	tasks, err := client.SearchTasks(ctx, &mythic.TaskSearchRequest{
+//	    CommandName: "upload",
+//	    OperatorID:  &operatorID,
+//	    Limit:       50,
+//	})
+func (c *Client) SearchTasks(ctx context.Context, req *TaskSearchRequest) ([]*Task, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if req == nil {
+		return nil, WrapError("SearchTasks", ErrInvalidInput, "search request is required")
+	}
+	if req.Limit < 0 {
+		return nil, WrapError("SearchTasks", ErrInvalidInput, "limit cannot be negative")
+	}
+	if req.Offset < 0 {
+		return nil, WrapError("SearchTasks", ErrInvalidInput, "offset cannot be negative")
+	}
+	if req.StartTime != nil && req.EndTime != nil && req.EndTime.Before(*req.StartTime) {
+		return nil, WrapError("SearchTasks", ErrInvalidInput, "start time must not be after end time")
+	}
+
+	sortOrder := req.SortOrder
+	switch sortOrder {
+	case "":
+		sortOrder = "desc"
+	case "asc", "desc":
+	default:
+		return nil, WrapError("SearchTasks", ErrInvalidInput, "sort order must be 'asc' or 'desc'")
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 100 // Default limit
+	}
+
+	operationID := req.OperationID
+	if operationID == nil {
+		operationID = c.GetCurrentOperation()
+		if operationID == nil {
+			return nil, WrapError("SearchTasks", ErrNotAuthenticated, "no current operation set")
+		}
+	}
+
+	where := newBoolExp("task")
+	where.conds["operation_id"] = map[string]interface{}{"_eq": *operationID}
+	if req.CommandName != "" {
+		where.conds["command_name"] = map[string]interface{}{"_eq": req.CommandName}
+	}
+	if req.OperatorID != nil {
+		where.conds["operator_id"] = map[string]interface{}{"_eq": *req.OperatorID}
+	}
+	if req.Status != "" {
+		where.conds["status"] = map[string]interface{}{"_eq": string(req.Status)}
+	}
+	if req.CallbackID != nil {
+		where.conds["callback_id"] = map[string]interface{}{"_eq": *req.CallbackID}
+	}
+
+	timestamp := make(map[string]interface{})
+	if req.StartTime != nil {
+		timestamp["_gte"] = req.StartTime.UTC().Format(time.RFC3339)
+	}
+	if req.EndTime != nil {
+		timestamp["_lte"] = req.EndTime.UTC().Format(time.RFC3339)
+	}
+	if len(timestamp) > 0 {
+		where.conds["timestamp"] = timestamp
+	}
+
+	// Break timestamp ties by ID so offset pagination is stable
+	order := newOrderBy("task").then("timestamp", sortOrder).then("id", sortOrder)
+
+	var query struct {
+		Task []taskQueryFields `graphql:"task(where: $where, order_by: $order_by, limit: $limit, offset: $offset)"`
+	}
+
+	variables := map[string]interface{}{
+		"where":    where,
+		"order_by": order,
+		"limit":    limit,
+		"offset":   req.Offset,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("SearchTasks", err, "failed to search tasks")
+	}
+
+	tasks := make([]*Task, len(query.Task))
+	for i, t := range query.Task {
+		tasks[i] = t.toTask()
+	}
+
+	return tasks, nil
+}
+
+// GetTasksByToken retrieves all tasks that were issued under a specific token,
+// identified by its ID as returned by the token APIs (types.Token.ID).
+func (c *Client) GetTasksByToken(ctx context.Context, tokenID int) ([]*Task, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if tokenID <= 0 {
+		return nil, WrapError("GetTasksByToken", ErrInvalidInput, "token ID must be positive")
+	}
+
+	var query struct {
+		Task []taskQueryFields `graphql:"task(where: {token_id: {_eq: $token_id}}, order_by: {id: desc})"`
+	}
+
+	variables := map[string]interface{}{
+		"token_id": tokenID,
+	}
+
+	if err := c.executeQuery(ctx, &query, variables); err != nil {
+		return nil, WrapError("GetTasksByToken", err, "failed to query tasks")
+	}
+
+	tasks := make([]*Task, 0, len(query.Task))
+	for _, t := range query.Task {
+		tasks = append(tasks, t.toTask())
+	}
+
+	return tasks, nil
+}
+
+// GetTasksByDisplayIDs retrieves multiple tasks by their display IDs in a single
+// GraphQL query. Returns a map keyed by display ID with the same fields GetTask
+// returns. Tasks that don't exist are simply absent from the map.
+func (c *Client) GetTasksByDisplayIDs(ctx context.Context, displayIDs []int) (map[int]*Task, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(displayIDs) == 0 {
+		return nil, WrapError("GetTasksByDisplayIDs", ErrInvalidInput, "at least one display_id is required")
+	}
+
+	var query struct {
+		Task []taskQueryFields `graphql:"task(where: {display_id: {_in: $display_ids}}, order_by: {display_id: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"display_ids": displayIDs,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetTasksByDisplayIDs", err, "failed to query tasks")
+	}
+
+	tasks := make(map[int]*Task, len(query.Task))
+	for _, t := range query.Task {
+		tasks[t.DisplayID] = t.toTask()
+	}
+
+	return tasks, nil
+}
+
+// TaskNode is a task together with the subtasks it spawned.
+type TaskNode struct {
+	*Task
+	Children []*TaskNode `json:"children"`
+}
+
+// maxTaskTreeDepth bounds how many levels GetTaskTree will descend.
+const maxTaskTreeDepth = 32
+
+// GetSubtasks retrieves the direct subtasks of a task, identified by its
+// internal task ID (Task.ID, not the display ID). Use GetSubtasksByDisplayID
+// to look a task up by display ID instead.
+func (c *Client) GetSubtasks(ctx context.Context, parentTaskID int) ([]*Task, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if parentTaskID <= 0 {
+		return nil, WrapError("GetSubtasks", ErrInvalidInput, "parent task ID must be positive")
+	}
+
+	var query struct {
+		Task []taskQueryFields `graphql:"task(where: {parent_task_id: {_eq: $parent_task_id}}, order_by: {id: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"parent_task_id": parentTaskID,
+	}
+
+	if err := c.executeQuery(ctx, &query, variables); err != nil {
+		return nil, WrapError("GetSubtasks", err, "failed to query subtasks")
+	}
+
+	tasks := make([]*Task, 0, len(query.Task))
+	for _, t := range query.Task {
+		tasks = append(tasks, t.toTask())
+	}
+
+	return tasks, nil
+}
+
+// GetSubtasksByDisplayID retrieves the direct subtasks of a task identified by
+// its display ID, resolving the internal ID with GetTask first.
+func (c *Client) GetSubtasksByDisplayID(ctx context.Context, parentTaskDisplayID int) ([]*Task, error) {
+	if parentTaskDisplayID <= 0 {
+		return nil, WrapError("GetSubtasksByDisplayID", ErrInvalidInput, "parent task display ID must be positive")
+	}
+
+	parent, err := c.GetTask(ctx, parentTaskDisplayID)
+	if err != nil {
+		return nil, WrapError("GetSubtasksByDisplayID", err, "failed to get parent task")
+	}
+
+	return c.GetSubtasks(ctx, parent.ID)
+}
+
+// GetTaskTree retrieves a task and all of its subtasks recursively, up to
+// maxTaskTreeDepth levels deep. Use GetTaskTreeWithDepth to change the limit.
+func (c *Client) GetTaskTree(ctx context.Context, rootDisplayID int) (*TaskNode, error) {
+	return c.GetTaskTreeWithDepth(ctx, rootDisplayID, maxTaskTreeDepth)
+}
+
+// GetTaskTreeWithDepth retrieves a task and its subtasks, descending at most
+// maxDepth levels below the root.
+//
+// The tree is assembled one level at a time with a single
+// parent_task_id _in query per level, so the number of queries is bounded
+// by the depth of the tree rather than the number of tasks. Tasks already
+// seen are skipped to guard against cycles in malformed data.
+func (c *Client) GetTaskTreeWithDepth(ctx context.Context, rootDisplayID int, maxDepth int) (*TaskNode, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if rootDisplayID <= 0 {
+		return nil, WrapError("GetTaskTree", ErrInvalidInput, "root display_id must be positive")
+	}
+
+	if maxDepth < 0 {
+		return nil, WrapError("GetTaskTree", ErrInvalidInput, "max depth cannot be negative")
+	}
+
+	rootTask, err := c.GetTask(ctx, rootDisplayID)
+	if err != nil {
+		return nil, WrapError("GetTaskTree", err, "failed to get root task")
+	}
+
+	root := &TaskNode{Task: rootTask, Children: []*TaskNode{}}
+	nodes := map[int]*TaskNode{rootTask.ID: root}
+	frontier := []int{rootTask.ID}
+
+	for depth := 0; len(frontier) > 0 && depth < maxDepth; depth++ {
+		var query struct {
+			Task []taskQueryFields `graphql:"task(where: {parent_task_id: {_in: $parent_ids}}, order_by: {id: asc})"`
+		}
+
+		variables := map[string]interface{}{
+			"parent_ids": frontier,
+		}
+
+		if err := c.executeQuery(ctx, &query, variables); err != nil {
+			return nil, WrapError("GetTaskTree", err, "failed to query subtasks")
+		}
+
+		next := make([]int, 0, len(query.Task))
+		for _, t := range query.Task {
+			if _, seen := nodes[t.ID]; seen || t.ParentTaskID == nil {
+				continue
+			}
+			parent, ok := nodes[*t.ParentTaskID]
+			if !ok {
+				continue
+			}
+
+			child := &TaskNode{Task: t.toTask(), Children: []*TaskNode{}}
+			parent.Children = append(parent.Children, child)
+			nodes[t.ID] = child
+			next = append(next, t.ID)
+		}
+		frontier = next
+	}
+
+	return root, nil
+}
+
+// GetTaskOutput retrieves all responses (output) for a task.
+func (c *Client) GetTaskOutput(ctx context.Context, taskDisplayID int) ([]*TaskResponse, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	// First get the task's actual ID
+	task, err := c.GetTask(ctx, taskDisplayID)
+	if err != nil {
+		return nil, WrapError("GetTaskOutput", err, "failed to get task")
+	}
+
+	return c.getTaskOutput(ctx, "GetTaskOutput", task.ID)
+}
+
+// GetTaskOutputByInternalID retrieves all responses (output) for the task with
+// the given internal ID (Task.ID), saving the display ID lookup GetTaskOutput
+// makes. An unknown ID returns an empty slice.
+func (c *Client) GetTaskOutputByInternalID(ctx context.Context, taskID int) ([]*TaskResponse, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if taskID <= 0 {
+		return nil, WrapError("GetTaskOutputByInternalID", ErrInvalidInput, "task ID must be positive")
+	}
+
+	return c.getTaskOutput(ctx, "GetTaskOutputByInternalID", taskID)
+}
+
+// getTaskOutput queries every response for a task by its internal ID.
+func (c *Client) getTaskOutput(ctx context.Context, op string, taskID int) ([]*TaskResponse, error) {
+	var query struct {
+		Response []taskResponseFields `graphql:"response(where: {task_id: {_eq: $task_id}}, order_by: {id: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"task_id": taskID,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError(op, err, "failed to query responses")
+	}
+
+	responses := make([]*TaskResponse, 0, len(query.Response))
+	for _, r := range query.Response {
+		responses = append(responses, r.toTaskResponse())
+	}
+
+	return responses, nil
+}
+
+// GetTaskOutputSince retrieves the responses for a task whose response ID is
+// greater than afterResponseID, in ascending order. Pass the ID of the last
+// response already seen to fetch only new output; an empty slice means
+// nothing new has arrived.
+func (c *Client) GetTaskOutputSince(ctx context.Context, taskDisplayID int, afterResponseID int) ([]*TaskResponse, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if afterResponseID < 0 {
+		return nil, WrapError("GetTaskOutputSince", ErrInvalidInput, "response ID cannot be negative")
+	}
+
+	// Responses are keyed by the task's internal ID
+	task, err := c.GetTask(ctx, taskDisplayID)
+	if err != nil {
+		return nil, WrapError("GetTaskOutputSince", err, "failed to get task")
+	}
+
+	responses, err := c.getTaskResponsesSince(ctx, task.ID, afterResponseID)
+	if err != nil {
+		return nil, WrapError("GetTaskOutputSince", err, "failed to query responses")
+	}
+
+	return responses, nil
+}
+
+// taskResponseFields is the set of response columns selected for TaskResponse.
+type taskResponseFields struct {
+	ID             int    `graphql:"id"`
+	TaskID         int    `graphql:"task_id"`
+	ResponseText   string `graphql:"response_text"`
+	ResponseRaw    string `graphql:"response_raw"` // base64 encoded
+	IsError        bool   `graphql:"is_error"`
+	Timestamp      string `graphql:"timestamp"`
+	SequenceNumber *int   `graphql:"sequence_number"`
+}
+
+// toTaskResponse converts the queried response columns into a TaskResponse.
+func (r taskResponseFields) toTaskResponse() *TaskResponse {
+	// Parse timestamp - Mythic v3.4.20 returns timestamps without timezone
+	timestamp, err := parseTimestamp(r.Timestamp)
+	if err != nil {
+		// Don't fail the entire operation for timestamp parsing
+		timestamp = time.Time{}
+	}
+
+	// Raw output that fails to decode is left nil rather than failing the
+	// whole query; ResponseText still carries the readable form
+	var raw []byte
+	if r.ResponseRaw != "" {
+		if decoded, err := base64.StdEncoding.DecodeString(r.ResponseRaw); err == nil {
+			raw = decoded
+		}
+	}
+
+	return &TaskResponse{
+		ID:             r.ID,
+		TaskID:         r.TaskID,
+		ResponseText:   r.ResponseText,
+		ResponseRaw:    raw,
+		IsError:        r.IsError,
+		Timestamp:      timestamp,
+		SequenceNumber: r.SequenceNumber,
+	}
+}
+
+// getTaskResponsesSince retrieves responses for an internal task ID whose
+// response ID is greater than afterID, in ascending ID order.
+func (c *Client) getTaskResponsesSince(ctx context.Context, taskID int, afterID int) ([]*TaskResponse, error) {
+	var query struct {
+		Response []taskResponseFields `graphql:"response(where: {task_id: {_eq: $task_id}, id: {_gt: $after_id}}, order_by: {id: asc})"`
+	}
+
+	variables := map[string]interface{}{
+		"task_id":  taskID,
+		"after_id": afterID,
+	}
+
+	if err := c.executeQuery(ctx, &query, variables); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*TaskResponse, 0, len(query.Response))
+	for _, r := range query.Response {
+		responses = append(responses, r.toTaskResponse())
+	}
+
+	return responses, nil
+}
+
+// StreamTaskOutput tails a task's output, first emitting the responses already
+// present and then each new response as it appears. It is built on the same
+// subscription as GetTaskOutputStream with includeExisting set, so each
+// response is delivered exactly once.
+//
+// Both channels are closed when the task completes (after its final output has
+// been delivered), when ctx is cancelled, or after an error has been sent on
+// the error channel.
+func (c *Client) StreamTaskOutput(ctx context.Context, taskDisplayID int) (<-chan *TaskResponse, <-chan error) {
+	return c.GetTaskOutputStream(ctx, taskDisplayID, true)
+}
+
+// GetTaskOutputStream streams a task's output over a GraphQL subscription on
+// the response table, emitting each new response as it arrives. Responses
+// that existed before the stream started are skipped unless includeExisting
+// is set, in which case they are emitted first in ascending ID order.
+//
+// Both channels are closed when the task completes (after its final output has
+// been delivered), when ctx is cancelled, or after an error has been sent on
+// the error channel.
+//
+// Example:
+//
+//	responses, errs := client.GetTaskOutputStream(ctx, task.DisplayID, false)
+//	for r := range responses {
+//	    fmt.Print(r.ResponseText)
+//	}
+//	if err := <-errs; err != nil {
+//	    return err
+//	}
+func (c *Client) GetTaskOutputStream(ctx context.Context, taskDisplayID int, includeExisting bool) (<-chan *TaskResponse, <-chan error) {
+	responses := make(chan *TaskResponse, 100)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(responses)
+		defer close(errs)
+
+		sendErr := func(err error) {
+			select {
+			case errs <- err:
+			case <-ctx.Done():
+			}
+		}
+
+		if err := c.EnsureAuthenticated(ctx); err != nil {
+			sendErr(err)
+			return
+		}
+
+		// Resolve the display ID once; the subscriptions use the internal ID
+		task, err := c.GetTask(ctx, taskDisplayID)
+		if err != nil {
+			sendErr(WrapError("GetTaskOutputStream", err, "failed to get task"))
+			return
+		}
+
+		lastID := 0
+		if !includeExisting {
+			var latest struct {
+				Response []struct {
+					ID int `graphql:"id"`
+				} `graphql:"response(where: {task_id: {_eq: $task_id}}, order_by: {id: desc}, limit: 1)"`
+			}
+			if err := c.executeQuery(ctx, &latest, map[string]interface{}{"task_id": task.ID}); err != nil {
+				sendErr(WrapError("GetTaskOutputStream", err, "failed to query existing responses"))
+				return
+			}
+			if len(latest.Response) > 0 {
+				lastID = latest.Response[0].ID
+			}
+		}
+
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		batches := make(chan []taskResponseFields, 10)
+		completed := make(chan struct{})
+		subErrs := make(chan error, 1)
+		var completeOnce sync.Once
+
+		reportErr := func(err error) {
+			select {
+			case subErrs <- err:
+			default:
+			}
+		}
+
+		type responseSubscription struct {
+			Response []taskResponseFields `graphql:"response(where: {task_id: {_eq: $task_id}, id: {_gt: $after_id}}, order_by: {id: asc})"`
+		}
+		type taskSubscription struct {
+			Task []struct {
+				Completed bool   `graphql:"completed"`
+				Status    string `graphql:"status"`
+			} `graphql:"task(where: {id: {_eq: $task_id}})"`
+		}
+
+		subscriptionClient, disconnected := c.getSubscriptionClient()
+
+		responseSubID, err := subscriptionClient.Subscribe(&responseSubscription{}, map[string]interface{}{
+			"task_id":  task.ID,
+			"after_id": lastID,
+		}, func(dataValue []byte, errValue error) error {
+			if errValue != nil {
+				reportErr(errValue)
+				return nil
+			}
+			var data responseSubscription
+			if err := graphql.UnmarshalGraphQL(dataValue, &data); err != nil {
+				reportErr(fmt.Errorf("failed to parse response event: %w", err))
+				return nil
+			}
+			select {
+			case batches <- data.Response:
+			case <-subCtx.Done():
+			}
+			return nil
+		})
+		if err != nil {
+			sendErr(WrapError("GetTaskOutputStream", ErrOperationFailed, fmt.Sprintf("response subscription failed: %v", err)))
+			return
+		}
+		defer subscriptionClient.Unsubscribe(responseSubID) //nolint:errcheck // Best effort cleanup
+
+		taskSubID, err := subscriptionClient.Subscribe(&taskSubscription{}, map[string]interface{}{
+			"task_id": task.ID,
+		}, func(dataValue []byte, errValue error) error {
+			if errValue != nil {
+				reportErr(errValue)
+				return nil
+			}
+			var data taskSubscription
+			if err := graphql.UnmarshalGraphQL(dataValue, &data); err != nil {
+				reportErr(fmt.Errorf("failed to parse task event: %w", err))
+				return nil
+			}
+			for _, t := range data.Task {
+				if t.Completed || t.Status == string(TaskStatusError) {
+					completeOnce.Do(func() { close(completed) })
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			sendErr(WrapError("GetTaskOutputStream", ErrOperationFailed, fmt.Sprintf("task subscription failed: %v", err)))
+			return
+		}
+		defer subscriptionClient.Unsubscribe(taskSubID) //nolint:errcheck // Best effort cleanup
+
+		// Live queries resend the full result set, so track what was emitted
+		seen := make(map[int]bool)
+		emit := func(batch []*TaskResponse) bool {
+			for _, r := range batch {
+				if seen[r.ID] || r.ID <= lastID {
+					continue
+				}
+				seen[r.ID] = true
+
+				select {
+				case responses <- r:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-disconnected:
+				sendErr(WrapError("GetTaskOutputStream", ErrConnectionFailed, "subscription connection lost"))
+				return
+			case err := <-subErrs:
+				sendErr(WrapError("GetTaskOutputStream", ErrOperationFailed, err.Error()))
+				return
+			case batch := <-batches:
+				converted := make([]*TaskResponse, 0, len(batch))
+				for _, r := range batch {
+					converted = append(converted, r.toTaskResponse())
+				}
+				if !emit(converted) {
+					return
+				}
+			case <-completed:
+				// Drain anything written before completion that has not been pushed yet
+				final, err := c.getTaskResponsesSince(ctx, task.ID, lastID)
+				if err != nil {
+					if ctx.Err() == nil {
+						sendErr(WrapError("GetTaskOutputStream", err, "failed to query responses"))
+					}
+					return
+				}
+				emit(final)
+				return
+			}
+		}
+	}()
+
+	return responses, errs
+}
+
+// WaitForTaskComplete polls a task until it completes or times out.
+// Returns an error if the task fails or times out.
+func (c *Client) WaitForTaskComplete(ctx context.Context, taskDisplayID int, timeoutSeconds int) error {
+	return c.WaitForTaskCompleteWithOptions(ctx, taskDisplayID, timeoutSeconds, false)
+}
+
+// WaitForTaskCompleteWithOptions polls a task until it completes or times out.
 // If autoBypassOpsec is true, automatically requests OPSEC bypass when tasks are blocked.
 // This is useful for automated testing environments where manual OPSEC approval is not available.
 func (c *Client) WaitForTaskCompleteWithOptions(ctx context.Context, taskDisplayID int, timeoutSeconds int, autoBypassOpsec bool) error {
-	if err := c.EnsureAuthenticated(ctx); err != nil {
-		return err
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 300 // Default 5 minutes
+	}
+
+	cfg := DefaultPollConfig()
+	cfg.Timeout = time.Duration(timeoutSeconds) * time.Second
+	cfg.AutoBypassOpsec = autoBypassOpsec
+
+	return c.WaitForTaskCompleteWithConfig(ctx, taskDisplayID, cfg)
+}
+
+// WaitForTaskCompleteSubscription waits for a task by subscribing to its row
+// over the GraphQL websocket, returning as soon as Mythic pushes a completed
+// or error status instead of waiting for the next poll. If the subscription
+// cannot be established or the connection drops, the wait falls back to
+// polling with the default backoff. Returns an error if the task fails or
+// times out.
+func (c *Client) WaitForTaskCompleteSubscription(ctx context.Context, taskDisplayID int, timeoutSeconds int) error {
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 300 // Default 5 minutes
 	}
 
+	cfg := DefaultPollConfig()
+	cfg.Timeout = time.Duration(timeoutSeconds) * time.Second
+	cfg.UseSubscription = true
+
+	return c.WaitForTaskCompleteWithConfig(ctx, taskDisplayID, cfg)
+}
+
+// WaitOptions is a simpler alternative to PollConfig for
+// WaitForTaskCompleteWithWaitOptions.
+type WaitOptions struct {
+	// PollInterval is the fixed delay between status checks. Zero keeps the
+	// default backoff from DefaultPollConfig.
+	PollInterval time.Duration
+
+	// UseSubscription waits on a GraphQL subscription to the task instead of
+	// polling, falling back to polling if the subscription cannot be used
+	UseSubscription bool
+}
+
+// WaitForTaskCompleteWithWaitOptions waits for a task like
+// WaitForTaskComplete, polling every opts.PollInterval or waiting on a
+// subscription when opts.UseSubscription is set. A nil opts behaves like
+// WaitForTaskComplete. Returns an error if the task fails or times out.
+func (c *Client) WaitForTaskCompleteWithWaitOptions(ctx context.Context, taskDisplayID int, timeoutSeconds int, opts *WaitOptions) error {
 	if timeoutSeconds <= 0 {
 		timeoutSeconds = 300 // Default 5 minutes
 	}
 
-	timeout := time.After(time.Duration(timeoutSeconds) * time.Second)
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	cfg := DefaultPollConfig()
+	cfg.Timeout = time.Duration(timeoutSeconds) * time.Second
+	if opts != nil {
+		if opts.PollInterval < 0 {
+			return WrapError("WaitForTaskCompleteWithWaitOptions", ErrInvalidInput, "poll interval cannot be negative")
+		}
+		if opts.PollInterval > 0 {
+			cfg.InitialInterval = opts.PollInterval
+			cfg.MaxInterval = opts.PollInterval
+		}
+		cfg.UseSubscription = opts.UseSubscription
+	}
+
+	return c.WaitForTaskCompleteWithConfig(ctx, taskDisplayID, cfg)
+}
+
+// PollConfig controls how WaitForTaskCompleteWithConfig polls a task.
+// Zero values fall back to the defaults from DefaultPollConfig. For a fixed
+// poll interval, set InitialInterval and MaxInterval to the same value.
+type PollConfig struct {
+	// InitialInterval is the delay before the first status check (default: 500ms)
+	InitialInterval time.Duration
+
+	// MaxInterval caps the delay between status checks (default: 10s)
+	MaxInterval time.Duration
+
+	// Multiplier scales the delay after each check; must be at least 1 (default: 1.5)
+	Multiplier float64
+
+	// Timeout bounds the total wait (default: 5 minutes)
+	Timeout time.Duration
+
+	// AutoBypassOpsec requests an OPSEC bypass once if the task is blocked
+	AutoBypassOpsec bool
+
+	// UseSubscription waits on a GraphQL subscription to the task instead of
+	// polling, falling back to polling if the subscription cannot be used
+	UseSubscription bool
+}
+
+// DefaultPollConfig returns the polling configuration used by WaitForTaskComplete.
+func DefaultPollConfig() *PollConfig {
+	return &PollConfig{
+		InitialInterval: 500 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      1.5,
+		Timeout:         5 * time.Minute,
+	}
+}
+
+// WaitForTaskCompleteWithConfig polls a task until it completes or times out,
+// backing off exponentially between status checks. Quick tasks are noticed
+// almost immediately while long-running tasks are checked at most once per
+// MaxInterval. Returns an error if the task fails or times out.
+func (c *Client) WaitForTaskCompleteWithConfig(ctx context.Context, taskDisplayID int, cfg *PollConfig) error {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return err
+	}
+
+	defaults := DefaultPollConfig()
+	resolved := *defaults
+	if cfg != nil {
+		if cfg.InitialInterval < 0 || cfg.MaxInterval < 0 || cfg.Timeout < 0 {
+			return WrapError("WaitForTaskCompleteWithConfig", ErrInvalidInput, "poll intervals and timeout cannot be negative")
+		}
+		if cfg.Multiplier != 0 && cfg.Multiplier < 1 {
+			return WrapError("WaitForTaskCompleteWithConfig", ErrInvalidInput, "multiplier must be at least 1")
+		}
+
+		resolved = *cfg
+		if resolved.InitialInterval == 0 {
+			resolved.InitialInterval = defaults.InitialInterval
+		}
+		if resolved.MaxInterval == 0 {
+			resolved.MaxInterval = defaults.MaxInterval
+		}
+		if resolved.Multiplier == 0 {
+			resolved.Multiplier = defaults.Multiplier
+		}
+		if resolved.Timeout == 0 {
+			resolved.Timeout = defaults.Timeout
+		}
+	}
+	if resolved.InitialInterval > resolved.MaxInterval {
+		resolved.InitialInterval = resolved.MaxInterval
+	}
 
+	timeout := time.After(resolved.Timeout)
 	opsecBypassAttempted := false // Track if we've already tried to bypass OPSEC
 
+	if resolved.UseSubscription {
+		if handled, err := c.waitForTaskSubscription(ctx, taskDisplayID, &resolved, timeout, &opsecBypassAttempted); handled {
+			return err
+		}
+	}
+
+	interval := resolved.InitialInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
 	for {
 		select {
 		case <-timeout:
-			return WrapError("WaitForTaskComplete", ErrTimeout, fmt.Sprintf("task %d did not complete within %d seconds", taskDisplayID, timeoutSeconds))
+			return WrapError("WaitForTaskComplete", ErrTimeout, fmt.Sprintf("task %d did not complete within %s", taskDisplayID, resolved.Timeout))
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-ticker.C:
+		case <-timer.C:
 			task, err := c.GetTask(ctx, taskDisplayID)
 			if err != nil {
 				return WrapError("WaitForTaskComplete", err, "failed to check task status")
 			}
 
-			// Check if task completed
-			if task.Completed {
-				return nil
+			if done, err := c.checkTaskWaitState(ctx, task, &resolved, &opsecBypassAttempted); done {
+				return err
 			}
 
-			// Check if task errored
-			if task.Status == string(TaskStatusError) {
-				return WrapError("WaitForTaskComplete", ErrTaskFailed, fmt.Sprintf("task %d failed: %s", taskDisplayID, task.Stderr))
+			// Back off before the next check, capped at the max interval
+			interval = time.Duration(float64(interval) * resolved.Multiplier)
+			if interval > resolved.MaxInterval {
+				interval = resolved.MaxInterval
 			}
+			timer.Reset(interval)
+		}
+	}
+}
 
-			// Auto-bypass OPSEC if requested and task is blocked
-			if autoBypassOpsec && !opsecBypassAttempted {
-				// Check if task is blocked by OPSEC pre-check
-				isOpsecBlocked := (task.OpsecPreBlocked != nil && *task.OpsecPreBlocked) ||
-					(task.Status == "OPSEC Pre Check Running...")
-
-				if isOpsecBlocked && !task.OpsecPreBypassed {
-					// Request OPSEC bypass
-					bypassErr := c.RequestOpsecBypass(ctx, task.ID)
-					if bypassErr != nil {
-						// Log the error but continue polling - operator may need to approve
-						// Don't fail the entire wait operation
-						_ = bypassErr // Ignore error - may not have permission
-					}
-					opsecBypassAttempted = true // Only try once per wait cycle
-				}
+// checkTaskWaitState evaluates one observation of a task being waited on. It
+// reports done once the task has finished, along with the wait's result.
+func (c *Client) checkTaskWaitState(ctx context.Context, task *Task, cfg *PollConfig, opsecBypassAttempted *bool) (bool, error) {
+	// Check if task completed
+	if task.Completed {
+		return true, nil
+	}
+
+	// Check if task errored
+	if task.Status == string(TaskStatusError) {
+		return true, WrapError("WaitForTaskComplete", ErrTaskFailed, fmt.Sprintf("task %d failed: %s", task.DisplayID, task.Stderr))
+	}
+
+	// Auto-bypass OPSEC if requested and task is blocked
+	if cfg.AutoBypassOpsec && !*opsecBypassAttempted {
+		// Check if task is blocked by OPSEC pre-check
+		isOpsecBlocked := (task.OpsecPreBlocked != nil && *task.OpsecPreBlocked) ||
+			(task.Status == "OPSEC Pre Check Running...")
+
+		if isOpsecBlocked && !task.OpsecPreBypassed {
+			// Request OPSEC bypass
+			bypassErr := c.RequestOpsecBypass(ctx, task.ID)
+			if bypassErr != nil {
+				// Log the error but continue polling - operator may need to approve
+				// Don't fail the entire wait operation
+				_ = bypassErr // Ignore error - may not have permission
+			}
+			*opsecBypassAttempted = true // Only try once per wait cycle
+		}
+	}
+
+	return false, nil
+}
+
+// waitForTaskSubscription waits for a task by subscribing to its row. It
+// reports handled=false if the subscription could not be established or the
+// connection dropped, in which case the caller should fall back to polling.
+func (c *Client) waitForTaskSubscription(ctx context.Context, taskDisplayID int, cfg *PollConfig, timeout <-chan time.Time, opsecBypassAttempted *bool) (bool, error) {
+	task, err := c.GetTask(ctx, taskDisplayID)
+	if err != nil {
+		return true, WrapError("WaitForTaskComplete", err, "failed to check task status")
+	}
+	if done, err := c.checkTaskWaitState(ctx, task, cfg, opsecBypassAttempted); done {
+		return true, err
+	}
+
+	type taskWaitSubscription struct {
+		Task []struct {
+			Completed        bool   `graphql:"completed"`
+			Status           string `graphql:"status"`
+			Stderr           string `graphql:"stderr"`
+			OpsecPreBlocked  *bool  `graphql:"opsec_pre_blocked"`
+			OpsecPreBypassed bool   `graphql:"opsec_pre_bypassed"`
+		} `graphql:"task(where: {id: {_eq: $task_id}})"`
+	}
+
+	// Only the latest state matters, so a newer update replaces an unread one
+	updates := make(chan *Task, 1)
+	failed := make(chan struct{})
+	var failOnce sync.Once
+
+	subscriptionClient, disconnected := c.getSubscriptionClient()
+	subID, err := subscriptionClient.Subscribe(&taskWaitSubscription{}, map[string]interface{}{
+		"task_id": task.ID,
+	}, func(dataValue []byte, errValue error) error {
+		var data taskWaitSubscription
+		if errValue != nil || graphql.UnmarshalGraphQL(dataValue, &data) != nil {
+			failOnce.Do(func() { close(failed) })
+			return nil
+		}
+		for _, row := range data.Task {
+			update := *task
+			update.Completed = row.Completed
+			update.Status = row.Status
+			update.Stderr = row.Stderr
+			update.OpsecPreBlocked = row.OpsecPreBlocked
+			update.OpsecPreBypassed = row.OpsecPreBypassed
+
+			select {
+			case <-updates:
+			default:
 			}
+			updates <- &update
 		}
+		return nil
+	})
+	if err != nil {
+		return false, nil
+	}
+	defer subscriptionClient.Unsubscribe(subID) //nolint:errcheck // Best effort cleanup
+
+	// Hasura pushes the current row as soon as a subscription starts, so
+	// silence means the websocket never came up
+	started := time.NewTimer(subscriptionStartTimeout)
+	defer started.Stop()
+
+	for {
+		select {
+		case <-timeout:
+			return true, WrapError("WaitForTaskComplete", ErrTimeout, fmt.Sprintf("task %d did not complete within %s", taskDisplayID, cfg.Timeout))
+		case <-ctx.Done():
+			return true, ctx.Err()
+		case <-disconnected:
+			return false, nil
+		case <-failed:
+			return false, nil
+		case <-started.C:
+			return false, nil
+		case update := <-updates:
+			started.Stop()
+			if done, err := c.checkTaskWaitState(ctx, update, cfg, opsecBypassAttempted); done {
+				return true, err
+			}
+		}
+	}
+}
+
+// subscriptionStartTimeout is how long waitForTaskSubscription waits for the
+// first subscription update before falling back to polling.
+const subscriptionStartTimeout = 5 * time.Second
+
+// WaitForTaskResult waits for a task to complete and returns the final task
+// along with all of its responses, saving the GetTask and GetTaskOutput round
+// trips that usually follow WaitForTaskComplete.
+//
+// If the wait times out, the latest task state and whatever responses have
+// arrived so far are returned together with an error wrapping ErrTimeout.
+// If the task finishes with an error status, the task and its responses are
+// returned together with an error wrapping ErrTaskFailed so callers can still
+// inspect Stderr and the failure output. If ctx is cancelled, only ctx.Err()
+// is returned.
+func (c *Client) WaitForTaskResult(ctx context.Context, taskDisplayID int, timeoutSeconds int) (*Task, []*TaskResponse, error) {
+	waitErr := c.WaitForTaskComplete(ctx, taskDisplayID, timeoutSeconds)
+	if waitErr != nil && ctx.Err() != nil {
+		// Context is gone, so no further queries can be made
+		return nil, nil, waitErr
+	}
+
+	// Fetch the task so the caller sees the final status
+	// waitErr is already wrapped by WaitForTaskComplete, so it is returned
+	// as is rather than wrapped again
+	task, err := c.GetTask(ctx, taskDisplayID)
+	if err != nil {
+		if waitErr != nil {
+			return nil, nil, waitErr
+		}
+		return nil, nil, WrapError("WaitForTaskResult", err, "failed to get task")
+	}
+
+	responses, err := c.GetTaskOutput(ctx, taskDisplayID)
+	if err != nil {
+		if waitErr != nil {
+			return task, nil, waitErr
+		}
+		return task, nil, WrapError("WaitForTaskResult", err, "failed to get task output")
+	}
+
+	if waitErr != nil {
+		return task, responses, waitErr
+	}
+
+	// Completed tasks can still carry an error status
+	if task.IsError() {
+		return task, responses, WrapError("WaitForTaskResult", ErrTaskFailed, fmt.Sprintf("task %d failed: %s", task.DisplayID, task.Stderr))
+	}
+
+	return task, responses, nil
+}
+
+// WaitForTaskOutput waits for a task to complete and returns all of its
+// responses. On timeout the responses collected so far are returned together
+// with an error wrapping ErrTimeout; use WaitForTaskResult to also get the
+// final task.
+func (c *Client) WaitForTaskOutput(ctx context.Context, taskDisplayID int, timeoutSeconds int) ([]*TaskResponse, error) {
+	_, responses, err := c.WaitForTaskResult(ctx, taskDisplayID, timeoutSeconds)
+	return responses, err
+}
+
+// IssueTaskAndWait issues a task, waits for it to complete, and returns the
+// final task along with all of its responses in a single call.
+//
+// Partial results are returned with errors as described for WaitForTaskResult;
+// if no final state could be fetched, the task as issued is returned instead.
+func (c *Client) IssueTaskAndWait(ctx context.Context, req *TaskRequest, timeoutSeconds int) (*Task, []*TaskResponse, error) {
+	task, err := c.IssueTask(ctx, req)
+	if err != nil {
+		return nil, nil, WrapError("IssueTaskAndWait", err, "failed to issue task")
+	}
+
+	final, responses, err := c.WaitForTaskResult(ctx, task.DisplayID, timeoutSeconds)
+	if final != nil {
+		task = final
 	}
+	return task, responses, err
 }
 
 // UpdateTask updates a task's properties.
@@ -631,6 +2216,27 @@ func (c *Client) UpdateTask(ctx context.Context, displayID int, updates map[stri
 		return WrapError("UpdateTask", err, "failed to get task")
 	}
 
+	return c.updateTask(ctx, "UpdateTask", task.ID, "display_id", displayID, updates)
+}
+
+// UpdateTaskByInternalID updates the properties of the task with the given
+// internal ID (Task.ID), saving the display ID lookup UpdateTask makes.
+// Like UpdateTask, only the 'comment' field is currently supported.
+func (c *Client) UpdateTaskByInternalID(ctx context.Context, taskID int, updates map[string]interface{}) error {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return err
+	}
+
+	if taskID <= 0 {
+		return WrapError("UpdateTaskByInternalID", ErrInvalidInput, "task ID must be positive")
+	}
+
+	return c.updateTask(ctx, "UpdateTaskByInternalID", taskID, "id", taskID, updates)
+}
+
+// updateTask applies updates to a task by its internal ID. idLabel and
+// idValue name the identifier the caller was given, for error messages.
+func (c *Client) updateTask(ctx context.Context, op string, taskID int, idLabel string, idValue int, updates map[string]interface{}) error {
 	// NOTE: GraphQL doesn't support passing object variables to _set parameter
 	// We must explicitly define each field we want to update in the mutation
 	// Currently only supports updating the comment field
@@ -638,7 +2244,7 @@ func (c *Client) UpdateTask(ctx context.Context, displayID int, updates map[stri
 
 	comment, hasComment := updates["comment"]
 	if !hasComment {
-		return WrapError("UpdateTask", ErrInvalidInput, "only 'comment' field updates are currently supported")
+		return WrapError(op, ErrInvalidInput, "only 'comment' field updates are currently supported")
 	}
 
 	var mutation struct {
@@ -648,22 +2254,77 @@ func (c *Client) UpdateTask(ctx context.Context, displayID int, updates map[stri
 	}
 
 	variables := map[string]interface{}{
-		"id":      task.ID,
+		"id":      taskID,
 		"comment": comment,
 	}
 
-	err = c.executeMutation(ctx, &mutation, variables)
+	err := c.executeMutation(ctx, &mutation, variables)
 	if err != nil {
-		return WrapError("UpdateTask", err, "failed to update task")
+		return WrapError(op, err, "failed to update task")
 	}
 
 	if mutation.UpdateTask.Affected == 0 {
-		return WrapError("UpdateTask", ErrNotFound, fmt.Sprintf("task with display_id %d not found or no changes made", displayID))
+		return WrapError(op, ErrNotFound, fmt.Sprintf("task with %s %d not found or no changes made", idLabel, idValue))
 	}
 
 	return nil
 }
 
+// UpdateTasks applies the same updates to several tasks in a single mutation
+// and returns the internal IDs of the tasks that were actually updated.
+// Display IDs that do not exist are skipped rather than treated as errors.
+// Like UpdateTask, only the 'comment' field is currently supported.
+func (c *Client) UpdateTasks(ctx context.Context, displayIDs []int, updates map[string]interface{}) ([]int, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(displayIDs) == 0 {
+		return nil, WrapError("UpdateTasks", ErrInvalidInput, "at least one display_id is required")
+	}
+
+	if len(updates) == 0 {
+		return nil, WrapError("UpdateTasks", ErrInvalidInput, "no fields to update")
+	}
+
+	for field := range updates {
+		if field != "comment" {
+			return nil, WrapError("UpdateTasks", ErrInvalidInput, fmt.Sprintf("unsupported field %q: only 'comment' field updates are currently supported", field))
+		}
+	}
+
+	comment, ok := updates["comment"].(string)
+	if !ok {
+		return nil, WrapError("UpdateTasks", ErrInvalidInput, "comment must be a string")
+	}
+
+	var mutation struct {
+		UpdateTask struct {
+			Affected  int `graphql:"affected_rows"`
+			Returning []struct {
+				ID int `graphql:"id"`
+			} `graphql:"returning"`
+		} `graphql:"update_task(where: {display_id: {_in: $display_ids}}, _set: {comment: $comment})"`
+	}
+
+	variables := map[string]interface{}{
+		"display_ids": displayIDs,
+		"comment":     comment,
+	}
+
+	if err := c.executeMutation(ctx, &mutation, variables); err != nil {
+		return nil, WrapError("UpdateTasks", err, "failed to update tasks")
+	}
+
+	taskIDs := make([]int, 0, len(mutation.UpdateTask.Returning))
+	for _, t := range mutation.UpdateTask.Returning {
+		taskIDs = append(taskIDs, t.ID)
+	}
+	sort.Ints(taskIDs)
+
+	return taskIDs, nil
+}
+
 // String returns a string representation of the task.
 func (t *Task) String() string {
 	return fmt.Sprintf("Task %d: %s %s (Status: %s, Completed: %t)",
@@ -792,6 +2453,94 @@ func (c *Client) AddMITREAttackToTask(ctx context.Context, taskDisplayID int, at
 	return nil
 }
 
+// RemoveMITREAttackFromTask removes a MITRE ATT&CK technique tag from a task.
+// The attackID parameter should be the technique number (e.g., "T1059").
+// Returns ErrNotFound if the technique was not tagged on the task.
+func (c *Client) RemoveMITREAttackFromTask(ctx context.Context, taskDisplayID int, attackID string) error {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return err
+	}
+
+	if taskDisplayID <= 0 {
+		return WrapError("RemoveMITREAttackFromTask", ErrInvalidInput, "task_display_id must be positive")
+	}
+
+	if attackID == "" {
+		return WrapError("RemoveMITREAttackFromTask", ErrInvalidInput, "attack ID (t_num) is required")
+	}
+
+	var mutation struct {
+		DeleteAttacktask struct {
+			AffectedRows int `graphql:"affected_rows"`
+		} `graphql:"delete_attacktask(where: {task: {display_id: {_eq: $task_display_id}}, attack: {t_num: {_eq: $t_num}}})"`
+	}
+
+	variables := map[string]interface{}{
+		"task_display_id": taskDisplayID,
+		"t_num":           attackID,
+	}
+
+	err := c.executeMutation(ctx, &mutation, variables)
+	if err != nil {
+		return WrapError("RemoveMITREAttackFromTask", err, "failed to remove MITRE ATT&CK tag")
+	}
+
+	if mutation.DeleteAttacktask.AffectedRows == 0 {
+		return WrapError("RemoveMITREAttackFromTask", ErrNotFound, fmt.Sprintf("attack %s not tagged on task %d", attackID, taskDisplayID))
+	}
+
+	return nil
+}
+
+// MITRETechnique is a MITRE ATT&CK technique tagged on a task.
+type MITRETechnique = types.Attack
+
+// GetMITREAttackForTask retrieves the MITRE ATT&CK techniques tagged on a task,
+// ordered by technique number.
+func (c *Client) GetMITREAttackForTask(ctx context.Context, taskDisplayID int) ([]*MITRETechnique, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if taskDisplayID <= 0 {
+		return nil, WrapError("GetMITREAttackForTask", ErrInvalidInput, "task_display_id must be positive")
+	}
+
+	var query struct {
+		AttackTask []struct {
+			Attack struct {
+				ID     int    `graphql:"id"`
+				TNum   string `graphql:"t_num"`
+				Name   string `graphql:"name"`
+				OS     string `graphql:"os"`
+				Tactic string `graphql:"tactic"`
+			} `graphql:"attack"`
+		} `graphql:"attacktask(where: {task: {display_id: {_eq: $task_display_id}}}, order_by: {attack: {t_num: asc}})"`
+	}
+
+	variables := map[string]interface{}{
+		"task_display_id": taskDisplayID,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetMITREAttackForTask", err, "failed to query MITRE ATT&CK tags for task")
+	}
+
+	techniques := make([]*MITRETechnique, len(query.AttackTask))
+	for i, at := range query.AttackTask {
+		techniques[i] = &MITRETechnique{
+			ID:     at.Attack.ID,
+			TNum:   at.Attack.TNum,
+			Name:   at.Attack.Name,
+			OS:     at.Attack.OS,
+			Tactic: at.Attack.Tactic,
+		}
+	}
+
+	return techniques, nil
+}
+
 // GetTasksByStatus retrieves tasks filtered by status.
 func (c *Client) GetTasksByStatus(ctx context.Context, callbackDisplayID int, status TaskStatus, limit int) ([]*Task, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -7,6 +7,60 @@ import (
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
+// tokenQueryFields is the token selection shared by the token queries.
+type tokenQueryFields struct {
+	ID          int    `graphql:"id"`
+	TokenID     string `graphql:"token_id"`
+	User        string `graphql:"user"`
+	Description string `graphql:"description"`
+	Groups      string `graphql:"groups"`
+	Privileges  string `graphql:"privileges"`
+	ThreadID    int    `graphql:"thread_id"`
+	ProcessID   int    `graphql:"process_id"`
+	SessionID   int    `graphql:"session_id"`
+	LogonSID    string `graphql:"logon_sid"`
+	// IntegrityLevelInt field removed - not available in Mythic v3.4.20 schema
+	Restricted         bool      `graphql:"restricted"`
+	DefaultDACL        string    `graphql:"default_dacl"`
+	Handle             string    `graphql:"handle"`
+	Capabilities       string    `graphql:"capabilities"`
+	AppContainerSID    string    `graphql:"app_container_sid"`
+	AppContainerNumber int       `graphql:"app_container_number"`
+	TaskID             *int      `graphql:"task_id"`
+	OperationID        int       `graphql:"operation_id"`
+	Timestamp          time.Time `graphql:"timestamp"`
+	Host               string    `graphql:"host"`
+	Deleted            bool      `graphql:"deleted"`
+}
+
+// toToken converts the query result into a types.Token.
+func (t tokenQueryFields) toToken() *types.Token {
+	return &types.Token{
+		ID:              t.ID,
+		TokenID:         t.TokenID,
+		User:            t.User,
+		Description:     t.Description,
+		Groups:          t.Groups,
+		Privileges:      t.Privileges,
+		ThreadID:        t.ThreadID,
+		ProcessID:       t.ProcessID,
+		SessionID:       t.SessionID,
+		LogonSID:        t.LogonSID,
+		IntegrityLevel:  0, // IntegrityLevelInt field not available in Mythic v3.4.20 schema
+		Restricted:      t.Restricted,
+		DefaultDACL:     t.DefaultDACL,
+		Handle:          t.Handle,
+		Capabilities:    t.Capabilities,
+		AppContainerSID: t.AppContainerSID,
+		AppContainerNum: t.AppContainerNumber,
+		TaskID:          t.TaskID,
+		OperationID:     t.OperationID,
+		Timestamp:       t.Timestamp,
+		Host:            t.Host,
+		Deleted:         t.Deleted,
+	}
+}
+
 // GetTokens retrieves all tokens (non-deleted) for the current operation.
 func (c *Client) GetTokens(ctx context.Context) ([]*types.Token, error) {
 	if err := c.EnsureAuthenticated(ctx); err != nil {
@@ -33,29 +87,7 @@ func (c *Client) GetTokensByOperation(ctx context.Context, operationID int) ([]*
 	}
 
 	var query struct {
-		Token []struct {
-			ID         int    `graphql:"id"`
-			TokenID    string `graphql:"token_id"`
-			User       string `graphql:"user"`
-			Groups     string `graphql:"groups"`
-			Privileges string `graphql:"privileges"`
-			ThreadID   int    `graphql:"thread_id"`
-			ProcessID  int    `graphql:"process_id"`
-			SessionID  int    `graphql:"session_id"`
-			LogonSID   string `graphql:"logon_sid"`
-			// IntegrityLevelInt field removed - not available in Mythic v3.4.20 schema
-			Restricted         bool      `graphql:"restricted"`
-			DefaultDACL        string    `graphql:"default_dacl"`
-			Handle             string    `graphql:"handle"`
-			Capabilities       string    `graphql:"capabilities"`
-			AppContainerSID    string    `graphql:"app_container_sid"`
-			AppContainerNumber int       `graphql:"app_container_number"`
-			TaskID             *int      `graphql:"task_id"`
-			OperationID        int       `graphql:"operation_id"`
-			Timestamp          time.Time `graphql:"timestamp"`
-			Host               string    `graphql:"host"`
-			Deleted            bool      `graphql:"deleted"`
-		} `graphql:"token(where: {operation_id: {_eq: $operation_id}, deleted: {_eq: false}}, order_by: {timestamp: desc})"`
+		Token []tokenQueryFields `graphql:"token(where: {operation_id: {_eq: $operation_id}, deleted: {_eq: false}}, order_by: {timestamp: desc})"`
 	}
 
 	variables := map[string]interface{}{
@@ -69,29 +101,41 @@ func (c *Client) GetTokensByOperation(ctx context.Context, operationID int) ([]*
 
 	tokens := make([]*types.Token, len(query.Token))
 	for i, t := range query.Token {
-		tokens[i] = &types.Token{
-			ID:              t.ID,
-			TokenID:         t.TokenID,
-			User:            t.User,
-			Groups:          t.Groups,
-			Privileges:      t.Privileges,
-			ThreadID:        t.ThreadID,
-			ProcessID:       t.ProcessID,
-			SessionID:       t.SessionID,
-			LogonSID:        t.LogonSID,
-			IntegrityLevel:  0, // IntegrityLevelInt field not available in Mythic v3.4.20 schema
-			Restricted:      t.Restricted,
-			DefaultDACL:     t.DefaultDACL,
-			Handle:          t.Handle,
-			Capabilities:    t.Capabilities,
-			AppContainerSID: t.AppContainerSID,
-			AppContainerNum: t.AppContainerNumber,
-			TaskID:          t.TaskID,
-			OperationID:     t.OperationID,
-			Timestamp:       t.Timestamp,
-			Host:            t.Host,
-			Deleted:         t.Deleted,
-		}
+		tokens[i] = t.toToken()
+	}
+
+	return tokens, nil
+}
+
+// GetTokensByCallback retrieves the non-deleted tokens associated with a
+// callback, either through a callbacktoken link or because one of the
+// callback's tasks reported them. The returned Token.ID is the value to set
+// as TaskRequest.TokenID to run a task under that token.
+func (c *Client) GetTokensByCallback(ctx context.Context, callbackID int) ([]*types.Token, error) {
+	if err := c.EnsureAuthenticated(ctx); err != nil {
+		return nil, err
+	}
+
+	if callbackID == 0 {
+		return nil, WrapError("GetTokensByCallback", ErrInvalidInput, "callback ID is required")
+	}
+
+	var query struct {
+		Token []tokenQueryFields `graphql:"token(where: {deleted: {_eq: false}, _or: [{callbacktokens: {callback_id: {_eq: $callback_id}}}, {task: {callback_id: {_eq: $callback_id}}}]}, order_by: {timestamp: desc})"`
+	}
+
+	variables := map[string]interface{}{
+		"callback_id": callbackID,
+	}
+
+	err := c.executeQuery(ctx, &query, variables)
+	if err != nil {
+		return nil, WrapError("GetTokensByCallback", err, "failed to query tokens")
+	}
+
+	tokens := make([]*types.Token, len(query.Token))
+	for i, t := range query.Token {
+		tokens[i] = t.toToken()
 	}
 
 	return tokens, nil
@@ -108,29 +152,7 @@ func (c *Client) GetTokenByID(ctx context.Context, tokenID int) (*types.Token, e
 	}
 
 	var query struct {
-		Token []struct {
-			ID         int    `graphql:"id"`
-			TokenID    string `graphql:"token_id"`
-			User       string `graphql:"user"`
-			Groups     string `graphql:"groups"`
-			Privileges string `graphql:"privileges"`
-			ThreadID   int    `graphql:"thread_id"`
-			ProcessID  int    `graphql:"process_id"`
-			SessionID  int    `graphql:"session_id"`
-			LogonSID   string `graphql:"logon_sid"`
-			// IntegrityLevelInt field removed - not available in Mythic v3.4.20 schema
-			Restricted         bool      `graphql:"restricted"`
-			DefaultDACL        string    `graphql:"default_dacl"`
-			Handle             string    `graphql:"handle"`
-			Capabilities       string    `graphql:"capabilities"`
-			AppContainerSID    string    `graphql:"app_container_sid"`
-			AppContainerNumber int       `graphql:"app_container_number"`
-			TaskID             *int      `graphql:"task_id"`
-			OperationID        int       `graphql:"operation_id"`
-			Timestamp          time.Time `graphql:"timestamp"`
-			Host               string    `graphql:"host"`
-			Deleted            bool      `graphql:"deleted"`
-		} `graphql:"token(where: {id: {_eq: $token_id}})"`
+		Token []tokenQueryFields `graphql:"token(where: {id: {_eq: $token_id}})"`
 	}
 
 	variables := map[string]interface{}{
@@ -146,30 +168,7 @@ func (c *Client) GetTokenByID(ctx context.Context, tokenID int) (*types.Token, e
 		return nil, WrapError("GetTokenByID", ErrNotFound, "token not found")
 	}
 
-	t := query.Token[0]
-	return &types.Token{
-		ID:              t.ID,
-		TokenID:         t.TokenID,
-		User:            t.User,
-		Groups:          t.Groups,
-		Privileges:      t.Privileges,
-		ThreadID:        t.ThreadID,
-		ProcessID:       t.ProcessID,
-		SessionID:       t.SessionID,
-		LogonSID:        t.LogonSID,
-		IntegrityLevel:  0, // IntegrityLevelInt field not available in Mythic v3.4.20 schema
-		Restricted:      t.Restricted,
-		DefaultDACL:     t.DefaultDACL,
-		Handle:          t.Handle,
-		Capabilities:    t.Capabilities,
-		AppContainerSID: t.AppContainerSID,
-		AppContainerNum: t.AppContainerNumber,
-		TaskID:          t.TaskID,
-		OperationID:     t.OperationID,
-		Timestamp:       t.Timestamp,
-		Host:            t.Host,
-		Deleted:         t.Deleted,
-	}, nil
+	return query.Token[0].toToken(), nil
 }
 
 // GetCallbackTokens retrieves all callback tokens for the current operation.
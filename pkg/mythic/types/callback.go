@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Callback represents a Mythic callback (active agent connection).
 type Callback struct {
@@ -166,6 +169,107 @@ type CallbackUpdateRequest struct {
 	Domain *string
 }
 
+// CallbackGraphEdge represents a P2P connection between two callbacks.
+type CallbackGraphEdge struct {
+	// ID is the database ID of the edge
+	ID int `json:"id"`
+
+	// SourceID is the display ID of the source callback
+	SourceID int `json:"source_id"`
+
+	// DestinationID is the display ID of the destination callback
+	DestinationID int `json:"destination_id"`
+
+	// C2ProfileName is the name of the C2 profile used for the connection
+	C2ProfileName string `json:"c2_profile_name"`
+
+	// StartTimestamp is when the edge was established
+	StartTimestamp time.Time `json:"start_timestamp"`
+
+	// EndTimestamp is when the edge was removed (nil if still active)
+	EndTimestamp *time.Time `json:"end_timestamp,omitempty"`
+}
+
+// IsActive returns true if the edge has not been removed.
+func (e *CallbackGraphEdge) IsActive() bool {
+	return e.EndTimestamp == nil
+}
+
+// CallbackConfig is a parsed callback configuration as produced by
+// ExportCallbackConfig. Keys not covered by the typed fields are kept in Extra
+// so that Marshal reproduces the full document for ImportCallbackConfig.
+type CallbackConfig struct {
+	// AgentCallbackID is the agent's callback identifier
+	AgentCallbackID string `json:"agent_callback_id"`
+
+	// Payload is the payload definition the callback was built from
+	Payload json.RawMessage `json:"payload,omitempty"`
+
+	// C2Profiles holds the C2 profile configuration of the callback
+	C2Profiles json.RawMessage `json:"c2_profiles,omitempty"`
+
+	// EncKey is the encryption key (base64 encoded)
+	EncKey *string `json:"enc_key,omitempty"`
+
+	// DecKey is the decryption key (base64 encoded)
+	DecKey *string `json:"dec_key,omitempty"`
+
+	// Extra holds any other top-level keys of the exported config
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// callbackConfigFields is CallbackConfig without its methods, used to avoid
+// recursing into the custom (un)marshalers.
+type callbackConfigFields CallbackConfig
+
+// UnmarshalJSON implements json.Unmarshaler for CallbackConfig.
+func (c *CallbackConfig) UnmarshalJSON(data []byte) error {
+	var fields callbackConfigFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for _, key := range []string{"agent_callback_id", "payload", "c2_profiles", "enc_key", "dec_key"} {
+		delete(raw, key)
+	}
+	if len(raw) > 0 {
+		fields.Extra = raw
+	}
+
+	*c = CallbackConfig(fields)
+	return nil
+}
+
+// Marshal returns the config as a JSON string suitable for ImportCallbackConfig.
+func (c *CallbackConfig) Marshal() (string, error) {
+	known, err := json.Marshal(callbackConfigFields(*c))
+	if err != nil {
+		return "", err
+	}
+
+	merged := make(map[string]json.RawMessage, len(c.Extra)+5)
+	for key, value := range c.Extra {
+		merged[key] = value
+	}
+	var knownFields map[string]json.RawMessage
+	if err := json.Unmarshal(known, &knownFields); err != nil {
+		return "", err
+	}
+	for key, value := range knownFields {
+		merged[key] = value
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
 // String returns a string representation of the callback.
 func (c *Callback) String() string {
 	status := "inactive"
@@ -175,6 +279,65 @@ func (c *Callback) String() string {
 	return c.User + "@" + c.Host + " (" + c.OS + ", " + status + ")"
 }
 
+// CheckinAge returns how long it has been since the callback last checked in.
+// Returns 0 if the last checkin time is unknown.
+func (c *Callback) CheckinAge() time.Duration {
+	return c.TimeSinceCheckin(time.Now())
+}
+
+// TimeSinceCheckin returns the time between the last checkin and now.
+// Returns 0 if the callback has never checked in.
+func (c *Callback) TimeSinceCheckin(now time.Time) time.Duration {
+	if c.LastCheckin.IsZero() {
+		return 0
+	}
+	return now.Sub(c.LastCheckin)
+}
+
+// IsStale returns true if the callback has not checked in within threshold.
+// A callback that has never checked in is always stale.
+func (c *Callback) IsStale(threshold time.Duration) bool {
+	if c.LastCheckin.IsZero() {
+		return true
+	}
+	return c.TimeSinceCheckin(time.Now()) > threshold
+}
+
+// CheckinBucket groups callbacks by how recently they checked in.
+type CheckinBucket string
+
+const (
+	CheckinNever     CheckinBucket = "never"  // no checkin recorded
+	CheckinRecent    CheckinBucket = "recent" // within RecentCheckinWindow
+	CheckinWithinDay CheckinBucket = "day"    // within StaleCheckinThreshold
+	CheckinStale     CheckinBucket = "stale"  // older than StaleCheckinThreshold
+)
+
+const (
+	// RecentCheckinWindow is the upper bound of CheckinRecent.
+	RecentCheckinWindow = 5 * time.Minute
+
+	// StaleCheckinThreshold is the age after which a checkin is CheckinStale.
+	StaleCheckinThreshold = 24 * time.Hour
+)
+
+// CheckinBucket returns the bucket for the callback's last checkin as of now.
+func (c *Callback) CheckinBucket(now time.Time) CheckinBucket {
+	if c.LastCheckin.IsZero() {
+		return CheckinNever
+	}
+
+	age := c.TimeSinceCheckin(now)
+	switch {
+	case age < RecentCheckinWindow:
+		return CheckinRecent
+	case age < StaleCheckinThreshold:
+		return CheckinWithinDay
+	default:
+		return CheckinStale
+	}
+}
+
 // IsHigh returns true if the callback has high or system integrity level.
 func (c *Callback) IsHigh() bool {
 	return c.IntegrityLevel >= IntegrityLevelHigh
@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // EventGroup represents an event group that can be triggered.
 type EventGroup struct {
@@ -50,6 +53,64 @@ func (e *EventGroup) NeedsApproval() bool {
 	return e.RequiresApproval && !e.Approved
 }
 
+// EventExecution represents one run (eventgroupinstance) of an event group.
+type EventExecution struct {
+	ID           int        `json:"id"`
+	EventGroupID int        `json:"event_group_id"`
+	OperationID  int        `json:"operation_id"`
+	Status       string     `json:"status"`
+	Trigger      string     `json:"trigger"`
+	CurrentStep  int        `json:"current_step"`
+	TotalSteps   int        `json:"total_steps"`
+	Error        string     `json:"error,omitempty"` // Stderr of the failed steps, if any
+	CreatedAt    time.Time  `json:"created_at"`
+	EndTimestamp *time.Time `json:"end_timestamp,omitempty"`
+}
+
+// Event execution statuses reported by Mythic.
+const (
+	EventExecutionRunning   = "running"
+	EventExecutionSuccess   = "success"
+	EventExecutionError     = "error"
+	EventExecutionCancelled = "cancelled"
+)
+
+// String returns a human-readable representation of the execution.
+func (e *EventExecution) String() string {
+	return fmt.Sprintf("Execution %d of event group %d: %s (step %d/%d)", e.ID, e.EventGroupID, e.Status, e.CurrentStep, e.TotalSteps)
+}
+
+// IsTerminal returns true if the execution has finished running.
+func (e *EventExecution) IsTerminal() bool {
+	return e.Status == EventExecutionSuccess || e.Status == EventExecutionError || e.Status == EventExecutionCancelled
+}
+
+// IsSuccessful returns true if the execution finished without error.
+func (e *EventExecution) IsSuccessful() bool {
+	return e.Status == EventExecutionSuccess
+}
+
+// EventStep represents one step (eventstepinstance) of an event group execution.
+type EventStep struct {
+	ID           int        `json:"id"`
+	Order        int        `json:"order"` // Step number, as accepted by EventingTriggerRetryFromStep
+	Name         string     `json:"name"`
+	Status       string     `json:"status"`
+	Output       string     `json:"output,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	EndTimestamp *time.Time `json:"end_timestamp,omitempty"`
+}
+
+// String returns a human-readable representation of the step.
+func (e *EventStep) String() string {
+	return fmt.Sprintf("Step %d '%s': %s", e.Order, e.Name, e.Status)
+}
+
+// IsFailed returns true if the step ended in error.
+func (e *EventStep) IsFailed() bool {
+	return e.Status == EventExecutionError
+}
+
 // EventTriggerManualRequest represents a manual event trigger request.
 type EventTriggerManualRequest struct {
 	EventGroupID int                    `json:"event_group_id"`
@@ -273,10 +334,11 @@ func (w *WebhookRequest) String() string {
 
 // WebhookResponse represents the response from sending a webhook.
 type WebhookResponse struct {
-	Status     string `json:"status"`
-	StatusCode int    `json:"status_code,omitempty"`
-	Response   string `json:"response,omitempty"`
-	Error      string `json:"error,omitempty"`
+	Status     string            `json:"status"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Response   string            `json:"response,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"` // Response headers from the downstream service, nil if the server does not report them
+	Error      string            `json:"error,omitempty"`
 }
 
 // String returns a human-readable representation of the response.
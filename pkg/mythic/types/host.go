@@ -18,6 +18,11 @@ type HostInfo struct {
 	OperationID  int       `json:"operation_id"` // Associated operation
 	Timestamp    time.Time `json:"timestamp"`    // When host was discovered/added
 
+	// Activity summary (populated by GetHosts)
+	CallbackCount       int       `json:"callback_count"`        // Callbacks ever seen on this host
+	ActiveCallbackCount int       `json:"active_callback_count"` // Callbacks currently active
+	LastSeen            time.Time `json:"last_seen"`             // Most recent callback check-in
+
 	// Related entities (populated with nested queries)
 	Callbacks []*Callback `json:"callbacks,omitempty"` // Active callbacks on this host
 }
@@ -51,10 +56,12 @@ func (h *HostInfo) String() string {
 	return fmt.Sprintf("Host %d", h.ID)
 }
 
-// GetCallbackCount returns the number of active callbacks on this host.
+// GetCallbackCount returns the number of active callbacks on this host. It
+// counts Callbacks when they are populated and otherwise reports
+// ActiveCallbackCount.
 func (h *HostInfo) GetCallbackCount() int {
 	if h.Callbacks == nil {
-		return 0
+		return h.ActiveCallbackCount
 	}
 	count := 0
 	for _, cb := range h.Callbacks {
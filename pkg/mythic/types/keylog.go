@@ -7,16 +7,18 @@ import (
 
 // Keylog represents a keylog entry captured from a callback.
 type Keylog struct {
-	ID          int        `json:"id"`
-	TaskID      int        `json:"task_id"`
-	Keystrokes  string     `json:"keystrokes"`
-	Window      string     `json:"window"`
-	Timestamp   time.Time  `json:"timestamp"`
-	OperationID int        `json:"operation_id"`
-	User        string     `json:"user"`
-	CallbackID  int        `json:"callback_id"`
-	Callback    *Callback  `json:"callback,omitempty"`
-	Operation   *Operation `json:"operation,omitempty"`
+	ID          int       `json:"id"`
+	TaskID      int       `json:"task_id"`
+	Keystrokes  string    `json:"keystrokes"`
+	Window      string    `json:"window"`
+	Timestamp   time.Time `json:"timestamp"`
+	OperationID int       `json:"operation_id"`
+	User        string    `json:"user"`
+	CallbackID  int       `json:"callback_id"`
+	// CallbackDisplayID is the display ID shown in the Mythic UI
+	CallbackDisplayID int        `json:"callback_display_id"`
+	Callback          *Callback  `json:"callback,omitempty"`
+	Operation         *Operation `json:"operation,omitempty"`
 }
 
 // String returns a string representation of a Keylog.
@@ -100,6 +100,11 @@ type CreatePayloadRequest struct {
 	Tag             string                 `json:"tag,omitempty"`
 	SelectedOS      string                 `json:"selected_os,omitempty"`
 	WrapperPayload  string                 `json:"wrapped_payload,omitempty"` // UUID of payload to wrap
+
+	// Validate runs ValidatePayloadRequest before the build is submitted, so
+	// unsupported C2 profiles and missing required parameters are rejected
+	// client-side.
+	Validate bool `json:"-"`
 }
 
 // C2ProfileConfig represents C2 profile configuration for payload creation.
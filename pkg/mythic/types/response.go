@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"regexp"
 	"time"
 )
 
@@ -19,8 +20,20 @@ type Response struct {
 	TaskCommand    string `json:"task_command,omitempty"`     // Command name
 	TaskStatus     string `json:"task_status,omitempty"`      // Task status
 	TaskCallbackID int    `json:"task_callback_id,omitempty"` // Callback ID
+
+	// IsError is true if the agent flagged this output as an error
+	IsError bool `json:"is_error,omitempty"`
 }
 
+// ResponseSearchMode controls how ResponseSearchRequest.Query is matched.
+type ResponseSearchMode string
+
+const (
+	SearchModeSubstring       ResponseSearchMode = "substring"        // Case-sensitive substring (server-side LIKE)
+	SearchModeCaseInsensitive ResponseSearchMode = "case_insensitive" // Case-insensitive substring (server-side ILIKE)
+	SearchModeRegex           ResponseSearchMode = "regex"            // Go regular expression (client-side)
+)
+
 // ResponseSearchRequest represents parameters for searching responses.
 type ResponseSearchRequest struct {
 	Query         string                 `json:"query,omitempty"`          // Full-text search query
@@ -34,6 +47,8 @@ type ResponseSearchRequest struct {
 	SortBy        string                 `json:"sort_by,omitempty"`        // Sort field (timestamp, id)
 	SortOrder     string                 `json:"sort_order,omitempty"`     // Sort direction (asc, desc)
 	CustomFilters map[string]interface{} `json:"custom_filters,omitempty"` // Additional GraphQL filters
+	SearchMode    ResponseSearchMode     `json:"search_mode,omitempty"`    // How Query is matched (default: case-insensitive)
+	IsError       *bool                  `json:"is_error,omitempty"`       // Filter by error flag
 }
 
 // ResponseStatistics represents aggregated response statistics for a task.
@@ -46,6 +61,23 @@ type ResponseStatistics struct {
 	IsComplete     bool      `json:"is_complete"`     // Whether task is completed
 }
 
+// CallbackResponseStats represents aggregated response statistics for a callback.
+type CallbackResponseStats struct {
+	CallbackID     int                              `json:"callback_id"`
+	ResponseCount  int                              `json:"response_count"`
+	ErrorCount     int                              `json:"error_count"`     // Responses flagged is_error
+	FirstResponse  time.Time                        `json:"first_response"`  // Timestamp of first response (zero if none)
+	LatestResponse time.Time                        `json:"latest_response"` // Timestamp of latest response (zero if none)
+	ByCommand      map[string]*CommandResponseStats `json:"by_command"`      // Keyed by command name
+}
+
+// CommandResponseStats is the per-command breakdown of CallbackResponseStats.
+type CommandResponseStats struct {
+	TaskCount     int `json:"task_count"`
+	ResponseCount int `json:"response_count"`
+	ErrorCount    int `json:"error_count"`
+}
+
 // String returns a string representation of a Response.
 func (r *Response) String() string {
 	preview := r.Response
@@ -82,6 +114,15 @@ func (r *ResponseSearchRequest) Validate() error {
 	if r.StartTime != nil && r.EndTime != nil && r.EndTime.Before(*r.StartTime) {
 		return fmt.Errorf("end_time must be after start_time")
 	}
+	switch r.SearchMode {
+	case "", SearchModeSubstring, SearchModeCaseInsensitive:
+	case SearchModeRegex:
+		if _, err := regexp.Compile(r.Query); err != nil {
+			return fmt.Errorf("invalid regex query: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid search_mode value: must be 'substring', 'case_insensitive' or 'regex'")
+	}
 	return nil
 }
 
@@ -96,4 +137,7 @@ func (r *ResponseSearchRequest) SetDefaults() {
 	if r.SortOrder == "" {
 		r.SortOrder = "asc"
 	}
+	if r.SearchMode == "" {
+		r.SearchMode = SearchModeCaseInsensitive // Matches the original search behavior
+	}
 }
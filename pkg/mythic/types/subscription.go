@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // SubscriptionType represents the type of subscription.
 type SubscriptionType string
@@ -26,6 +29,8 @@ const (
 	SubscriptionTypeArtifact SubscriptionType = "artifact"
 	// SubscriptionTypeToken subscribes to token discoveries
 	SubscriptionTypeToken SubscriptionType = "token"
+	// SubscriptionTypeNewTask subscribes to newly created tasks
+	SubscriptionTypeNewTask SubscriptionType = "new_task"
 	// SubscriptionTypeAll subscribes to all events
 	SubscriptionTypeAll SubscriptionType = "all"
 )
@@ -62,7 +67,9 @@ type SubscriptionConfig struct {
 	// Handler function called for each event
 	Handler SubscriptionHandler
 
-	// Filter criteria for the subscription (optional)
+	// Filter criteria for the subscription (optional). Filters are applied
+	// server-side with _eq semantics. Supported keys are operation_id,
+	// callback_id, and task_id, where they apply to the subscription type.
 	Filter map[string]interface{}
 
 	// OperationID to filter events (optional, uses current if not set)
@@ -70,6 +77,15 @@ type SubscriptionConfig struct {
 
 	// BufferSize for the event channel (default: 100)
 	BufferSize int
+
+	// AutoReconnect re-dials and re-subscribes when the WebSocket connection
+	// is lost, reporting each drop as a recoverable error on Errors instead of
+	// closing Done
+	AutoReconnect bool
+
+	// ReconnectBackoff is the initial delay before reconnecting, doubled on
+	// each consecutive failure (default: 1s)
+	ReconnectBackoff time.Duration
 }
 
 // String returns a human-readable representation of the subscription config.
@@ -91,6 +107,9 @@ func (s *SubscriptionConfig) Validate() error {
 	if s.BufferSize < 0 {
 		return fmt.Errorf("buffer size cannot be negative")
 	}
+	if s.ReconnectBackoff < 0 {
+		return fmt.Errorf("reconnect backoff cannot be negative")
+	}
 	return nil
 }
 
@@ -10,6 +10,7 @@ type Token struct {
 	ID              int        `json:"id"`
 	TokenID         string     `json:"token_id"`
 	User            string     `json:"user"`
+	Description     string     `json:"description"`
 	Groups          string     `json:"groups"`
 	Privileges      string     `json:"privileges"`
 	ThreadID        int        `json:"thread_id"`
@@ -173,3 +173,16 @@ func formatRawJSON(raw json.RawMessage) string {
 	}
 	return string(raw)
 }
+
+// rawJSONText converts a JSON value to text: strings are unquoted, null is
+// empty and any other value is returned as its JSON encoding.
+func rawJSONText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return string(raw)
+}
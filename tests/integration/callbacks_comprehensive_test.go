@@ -5,9 +5,12 @@ package integration
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -123,6 +126,279 @@ func TestE2E_CallbackRetrieval(t *testing.T) {
 	t.Log("=== ✓ Callback retrieval tests passed ===")
 }
 
+// TestE2E_CallbackQueryOptions tests server-side callback filtering and pagination.
+// Covers: GetCallbacks
+func TestE2E_CallbackQueryOptions(t *testing.T) {
+	// Ensure at least one callback exists
+	_ = EnsureCallbackExists(t)
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	all, err := client.GetAllCallbacks(ctx)
+	if err != nil {
+		t.Fatalf("GetAllCallbacks failed: %v", err)
+	}
+	if len(all) == 0 {
+		t.Fatal("No callbacks found after EnsureCallbackExists()")
+	}
+
+	// Test 1: Limit and offset
+	t.Log("=== Test 1: Limit and offset ===")
+	page, err := client.GetCallbacks(ctx, &mythic.CallbackQueryOptions{Limit: 1})
+	if err != nil {
+		t.Fatalf("GetCallbacks (limit) failed: %v", err)
+	}
+	if len(page) != 1 {
+		t.Fatalf("Expected 1 callback with Limit 1, got %d", len(page))
+	}
+	if page[0].ID != all[0].ID {
+		t.Errorf("Expected first page to start at callback %d, got %d", all[0].ID, page[0].ID)
+	}
+	if len(all) > 1 {
+		next, err := client.GetCallbacks(ctx, &mythic.CallbackQueryOptions{Limit: 1, Offset: 1})
+		if err != nil {
+			t.Fatalf("GetCallbacks (offset) failed: %v", err)
+		}
+		if len(next) != 1 || next[0].ID != all[1].ID {
+			t.Errorf("Expected second page to contain callback %d", all[1].ID)
+		}
+	}
+	t.Log("✓ Pagination returns callbacks in the same order as GetAllCallbacks")
+
+	// Test 2: Filter by host and active status
+	t.Log("=== Test 2: Filter by host ===")
+	target := all[0]
+	filtered, err := client.GetCallbacks(ctx, &mythic.CallbackQueryOptions{
+		Host:       target.Host,
+		ActiveOnly: target.Active,
+	})
+	if err != nil {
+		t.Fatalf("GetCallbacks (host) failed: %v", err)
+	}
+	found := false
+	for _, cb := range filtered {
+		if !strings.EqualFold(cb.Host, target.Host) {
+			t.Errorf("Callback %d host %q does not match filter %q", cb.DisplayID, cb.Host, target.Host)
+		}
+		if cb.ID == target.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected callback %d in host-filtered results", target.DisplayID)
+	}
+	t.Logf("✓ %d callbacks on host %s", len(filtered), target.Host)
+
+	// Test 3: Integrity filter
+	t.Log("=== Test 3: Filter by integrity level ===")
+	high, err := client.GetCallbacks(ctx, &mythic.CallbackQueryOptions{IntegrityAtLeast: types.IntegrityLevelHigh})
+	if err != nil {
+		t.Fatalf("GetCallbacks (integrity) failed: %v", err)
+	}
+	for _, cb := range high {
+		if !cb.IsHigh() {
+			t.Errorf("Callback %d has integrity %d, expected high or above", cb.DisplayID, cb.IntegrityLevel)
+		}
+	}
+	t.Logf("✓ %d high integrity callbacks", len(high))
+
+	t.Log("=== ✓ Callback query option tests passed ===")
+}
+
+// TestE2E_CallbacksPaginated walks the callback table one row at a time.
+// Covers: GetCallbacksPaginated
+func TestE2E_CallbacksPaginated(t *testing.T) {
+	// Ensure at least one callback exists
+	_ = EnsureCallbackExists(t)
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	all, err := client.GetAllCallbacks(ctx)
+	if err != nil {
+		t.Fatalf("GetAllCallbacks failed: %v", err)
+	}
+
+	t.Log("=== Test: Cursor pagination with a page size of 1 ===")
+	opts := &mythic.CallbackPageOptions{Limit: 1}
+	var walked []int
+	for {
+		page, err := client.GetCallbacksPaginated(ctx, opts)
+		if err != nil {
+			t.Fatalf("GetCallbacksPaginated failed: %v", err)
+		}
+		if page.Count > 1 {
+			t.Fatalf("Expected at most 1 callback per page, got %d", page.Count)
+		}
+		for _, cb := range page.Callbacks {
+			walked = append(walked, cb.ID)
+		}
+		if !page.HasMore {
+			break
+		}
+		opts.AfterID = page.NextCursor
+	}
+
+	if len(walked) != len(all) {
+		t.Fatalf("Paging returned %d callbacks, GetAllCallbacks returned %d", len(walked), len(all))
+	}
+	for i, id := range walked {
+		if all[i].ID != id {
+			t.Errorf("Page %d returned callback %d, expected %d", i, id, all[i].ID)
+		}
+	}
+	t.Logf("✓ Walked %d callbacks in %d pages", len(walked), len(walked))
+}
+
+// TestE2E_CallbackByAgentID validates looking up a callback by its agent_callback_id.
+func TestE2E_CallbackByAgentID(t *testing.T) {
+	callbackID := EnsureCallbackExists(t)
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	callback, err := client.GetCallbackByID(ctx, callbackID)
+	if err != nil {
+		t.Fatalf("GetCallbackByID failed: %v", err)
+	}
+
+	// Test 1: Known UUID resolves to the same callback
+	t.Log("=== Test 1: Lookup by agent_callback_id ===")
+	resolved, err := client.GetCallbackByAgentID(ctx, callback.AgentCallbackID)
+	if err != nil {
+		t.Fatalf("GetCallbackByAgentID failed: %v", err)
+	}
+	if resolved.DisplayID != callback.DisplayID {
+		t.Errorf("Expected callback %d, got %d", callback.DisplayID, resolved.DisplayID)
+	}
+	t.Logf("✓ %s resolved to callback %d", callback.AgentCallbackID, resolved.DisplayID)
+
+	// Test 2: Unknown UUID is ErrNotFound
+	t.Log("=== Test 2: Unknown agent_callback_id ===")
+	_, err = client.GetCallbackByAgentID(ctx, "00000000-0000-0000-0000-000000000000")
+	if !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	t.Log("✓ Unknown agent_callback_id returns ErrNotFound")
+}
+
+// TestE2E_CallbacksFiltered validates server-side filtering with CallbackFilter.
+func TestE2E_CallbacksFiltered(t *testing.T) {
+	// Ensure at least one callback exists
+	_ = EnsureCallbackExists(t)
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	all, err := client.GetAllCallbacks(ctx)
+	if err != nil {
+		t.Fatalf("GetAllCallbacks failed: %v", err)
+	}
+	if len(all) == 0 {
+		t.Fatal("No callbacks found after EnsureCallbackExists()")
+	}
+
+	// Test 1: Empty filter matches GetAllCallbacks
+	t.Log("=== Test 1: Empty filter ===")
+	unfiltered, err := client.GetCallbacksFiltered(ctx, &mythic.CallbackFilter{})
+	if err != nil {
+		t.Fatalf("GetCallbacksFiltered (empty) failed: %v", err)
+	}
+	if len(unfiltered) != len(all) {
+		t.Errorf("Expected %d callbacks with empty filter, got %d", len(all), len(unfiltered))
+	}
+	t.Log("✓ Empty filter returns every callback")
+
+	// Test 2: Filter by OS and host of a known callback
+	t.Log("=== Test 2: Filter by OS and host ===")
+	target := all[0]
+	filtered, err := client.GetCallbacksFiltered(ctx, &mythic.CallbackFilter{
+		OS:   &target.OS,
+		Host: &target.Host,
+	})
+	if err != nil {
+		t.Fatalf("GetCallbacksFiltered (os/host) failed: %v", err)
+	}
+	found := false
+	for _, cb := range filtered {
+		if !strings.EqualFold(cb.OS, target.OS) || !strings.EqualFold(cb.Host, target.Host) {
+			t.Errorf("Callback %d (%s on %s) does not match filter", cb.DisplayID, cb.OS, cb.Host)
+		}
+		if cb.ID == target.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected callback %d in filtered results", target.DisplayID)
+	}
+	t.Logf("✓ %d callbacks match OS %q on host %s", len(filtered), target.OS, target.Host)
+
+	// Test 3: Exact integrity and active state
+	t.Log("=== Test 3: Filter by exact integrity level and active state ===")
+	integrity := int(target.IntegrityLevel)
+	exact, err := client.GetCallbacksFiltered(ctx, &mythic.CallbackFilter{
+		IntegrityLevel: &integrity,
+		Active:         &target.Active,
+	})
+	if err != nil {
+		t.Fatalf("GetCallbacksFiltered (integrity/active) failed: %v", err)
+	}
+	found = false
+	for _, cb := range exact {
+		if int(cb.IntegrityLevel) != integrity || cb.Active != target.Active {
+			t.Errorf("Callback %d (integrity %d, active %v) does not match filter", cb.DisplayID, cb.IntegrityLevel, cb.Active)
+		}
+		if cb.ID == target.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected callback %d in integrity-filtered results", target.DisplayID)
+	}
+	t.Logf("✓ %d callbacks at integrity %d with active=%v", len(exact), integrity, target.Active)
+
+	t.Log("=== ✓ Callback filter tests passed ===")
+}
+
+// TestE2E_WaitForCallback tests that WaitForCallback returns an existing match immediately.
+// Covers: WaitForCallback
+func TestE2E_WaitForCallback(t *testing.T) {
+	callbackID := EnsureCallbackExists(t)
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	target, err := client.GetCallbackByID(ctx, callbackID)
+	if err != nil {
+		t.Fatalf("GetCallbackByID failed: %v", err)
+	}
+
+	t.Log("=== Test: Wait for callback on known host ===")
+	start := time.Now()
+	cb, err := client.WaitForCallback(ctx, 10, &mythic.CallbackFilter{Host: &target.Host})
+	if err != nil {
+		t.Fatalf("WaitForCallback failed: %v", err)
+	}
+	if !cb.Active {
+		t.Errorf("Expected active callback, got inactive callback %d", cb.DisplayID)
+	}
+	if !strings.EqualFold(cb.Host, target.Host) {
+		t.Errorf("Expected host %s, got %s", target.Host, cb.Host)
+	}
+	t.Logf("✓ Callback %d on %s returned in %v", cb.DisplayID, cb.Host, time.Since(start))
+}
+
 // TestE2E_CallbackAttributes tests callback attribute analysis.
 func TestE2E_CallbackAttributes(t *testing.T) {
 	// Ensure at least one callback exists
@@ -211,7 +487,7 @@ func TestE2E_CallbackAttributes(t *testing.T) {
 }
 
 // TestE2E_CallbackUpdate tests callback update operations.
-// Covers: UpdateCallback
+// Covers: UpdateCallback, LockCallback, UnlockCallback
 func TestE2E_CallbackUpdate(t *testing.T) {
 	// Ensure at least one callback exists
 	_ = EnsureCallbackExists(t)
@@ -250,18 +526,114 @@ func TestE2E_CallbackUpdate(t *testing.T) {
 	if err != nil {
 		t.Fatalf("UpdateCallback (description) failed: %v", err)
 	}
-	t.Logf("✓ UpdateCallback accepted request (Note: actual update not implemented in SDK yet)")
+	t.Log("✓ UpdateCallback accepted request")
+
+	// Verify the description persisted
+	updated, err := client.GetCallbackByID(ctx1, testCallback.DisplayID)
+	if err != nil {
+		t.Fatalf("GetCallbackByID after update failed: %v", err)
+	}
+	if updated.Description != newDesc {
+		t.Errorf("Expected description %q, got %q", newDesc, updated.Description)
+	}
+	t.Logf("✓ Description persisted: %q", updated.Description)
+
+	// Restore the original description
+	originalDesc := testCallback.Description
+	err = client.UpdateCallback(ctx1, &types.CallbackUpdateRequest{
+		CallbackDisplayID: testCallback.DisplayID,
+		Description:       &originalDesc,
+	})
+	if err != nil {
+		t.Logf("⚠ Failed to restore original description: %v", err)
+	}
+
+	// Test 2: Lock and unlock
+	t.Log("=== Test 2: Lock and unlock callback ===")
+	originallyLocked := testCallback.Locked
+	if err := client.LockCallback(ctx1, testCallback.DisplayID); err != nil {
+		t.Fatalf("LockCallback failed: %v", err)
+	}
+	locked, err := client.GetCallbackByID(ctx1, testCallback.DisplayID)
+	if err != nil {
+		t.Fatalf("GetCallbackByID after lock failed: %v", err)
+	}
+	if !locked.Locked {
+		t.Error("Expected callback to be locked")
+	}
+
+	if err := client.UnlockCallback(ctx1, testCallback.DisplayID); err != nil {
+		t.Fatalf("UnlockCallback failed: %v", err)
+	}
+	unlocked, err := client.GetCallbackByID(ctx1, testCallback.DisplayID)
+	if err != nil {
+		t.Fatalf("GetCallbackByID after unlock failed: %v", err)
+	}
+	if unlocked.Locked {
+		t.Error("Expected callback to be unlocked")
+	}
+	if originallyLocked {
+		if err := client.LockCallback(ctx1, testCallback.DisplayID); err != nil {
+			t.Logf("⚠ Failed to restore lock: %v", err)
+		}
+	}
+	t.Log("✓ Lock state persisted")
+
+	if err := client.LockCallback(ctx1, 999999); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound locking a non-existent callback, got %v", err)
+	}
+	t.Log("✓ Locking a non-existent callback returns ErrNotFound")
 
-	// Note: UpdateCallback is currently a stub that doesn't perform the actual update
-	// It only verifies the callback exists. Full implementation requires REST webhook.
-	// See pkg/mythic/callbacks.go:320-346 for details.
-	t.Log("  ⚠ Skipping update verification - UpdateCallback is not yet fully implemented")
+	// Test 3: Update sleep info
+	t.Log("=== Test 3: Update callback sleep info ===")
+	newSleep := `{"interval": 7, "jitter": 13}`
+	err = client.UpdateCallback(ctx1, &types.CallbackUpdateRequest{
+		CallbackDisplayID: testCallback.DisplayID,
+		SleepInfo:         &newSleep,
+	})
+	if err != nil {
+		t.Fatalf("UpdateCallback (sleep_info) failed: %v", err)
+	}
+	slept, err := client.GetCallbackByID(ctx1, testCallback.DisplayID)
+	if err != nil {
+		t.Fatalf("GetCallbackByID after sleep update failed: %v", err)
+	}
+	if slept.SleepInfo != newSleep {
+		t.Errorf("Expected sleep_info %q, got %q", newSleep, slept.SleepInfo)
+	}
+	t.Logf("✓ Sleep info persisted: %q", slept.SleepInfo)
+
+	originalSleep := testCallback.SleepInfo
+	if err := client.UpdateCallback(ctx1, &types.CallbackUpdateRequest{
+		CallbackDisplayID: testCallback.DisplayID,
+		SleepInfo:         &originalSleep,
+	}); err != nil {
+		t.Logf("⚠ Failed to restore original sleep info: %v", err)
+	}
+
+	missingDesc := "does not exist"
+	err = client.UpdateCallback(ctx1, &types.CallbackUpdateRequest{
+		CallbackDisplayID: 999999,
+		Description:       &missingDesc,
+	})
+	if !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound updating a non-existent callback, got %v", err)
+	}
+	t.Log("✓ Updating a non-existent callback returns ErrNotFound")
+
+	// Test 4: Update with no fields
+	t.Log("=== Test 4: Update callback with no fields ===")
+	err = client.UpdateCallback(ctx1, &types.CallbackUpdateRequest{CallbackDisplayID: testCallback.DisplayID})
+	if err == nil {
+		t.Error("Expected error when no fields are set")
+	}
+	t.Logf("✓ Empty update rejected: %v", err)
 
 	t.Log("=== ✓ Callback update tests passed ===")
 }
 
 // TestE2E_CallbackGraph tests callback graph operations.
-// Covers: AddCallbackGraphEdge, RemoveCallbackGraphEdge
+// Covers: AddCallbackGraphEdge, GetCallbackGraphEdges, GetCallbackGraphEdgesByOperation, RemoveCallbackGraphEdge
 func TestE2E_CallbackGraph(t *testing.T) {
 	// Ensure at least one callback exists
 	_ = EnsureCallbackExists(t)
@@ -308,15 +680,62 @@ func TestE2E_CallbackGraph(t *testing.T) {
 	}
 	t.Logf("✓ Graph edge added: %d -> %d", sourceCallback.DisplayID, destCallback.DisplayID)
 
-	// Note: We can't easily retrieve the edge ID to test RemoveCallbackGraphEdge
-	// without additional API methods, so we'll skip the removal test
-	t.Log("  ⚠ Graph edge removal not tested (requires edge ID from Mythic)")
+	// Test 2: Find the new edge
+	t.Log("=== Test 2: Get callback graph edges ===")
+	edges, err := client.GetCallbackGraphEdges(ctx1, sourceCallback.DisplayID)
+	if err != nil {
+		t.Fatalf("GetCallbackGraphEdges failed: %v", err)
+	}
+
+	var added *types.CallbackGraphEdge
+	for _, edge := range edges {
+		if edge.SourceID == sourceCallback.DisplayID && edge.DestinationID == destCallback.DisplayID && edge.IsActive() {
+			added = edge
+		}
+	}
+	if added == nil {
+		t.Fatalf("Added edge %d -> %d not found in %d edges", sourceCallback.DisplayID, destCallback.DisplayID, len(edges))
+	}
+	t.Logf("✓ Found edge %d (%s)", added.ID, added.C2ProfileName)
+
+	opEdges, err := client.GetCallbackGraphEdgesByOperation(ctx1, 0)
+	if err != nil {
+		t.Fatalf("GetCallbackGraphEdgesByOperation failed: %v", err)
+	}
+	foundInOperation := false
+	for _, edge := range opEdges {
+		if edge.ID == added.ID {
+			foundInOperation = true
+		}
+	}
+	if !foundInOperation {
+		t.Errorf("Edge %d not found in %d operation edges", added.ID, len(opEdges))
+	}
+	t.Logf("✓ Edge %d listed in operation topology (%d edges)", added.ID, len(opEdges))
+
+	// Test 3: Remove the edge
+	t.Log("=== Test 3: Remove callback graph edge ===")
+	err = client.RemoveCallbackGraphEdge(ctx1, added.ID)
+	if err != nil {
+		t.Fatalf("RemoveCallbackGraphEdge failed: %v", err)
+	}
+
+	edges, err = client.GetCallbackGraphEdges(ctx1, sourceCallback.DisplayID)
+	if err != nil {
+		t.Fatalf("GetCallbackGraphEdges after removal failed: %v", err)
+	}
+	for _, edge := range edges {
+		if edge.ID == added.ID && edge.IsActive() {
+			t.Errorf("Edge %d should no longer be active", added.ID)
+		}
+	}
+	t.Logf("✓ Graph edge %d removed", added.ID)
 
 	t.Log("=== ✓ Callback graph tests passed ===")
 }
 
 // TestE2E_CallbackConfigExport tests callback configuration export.
-// Covers: ExportCallbackConfig
+// Covers: ExportCallbackConfig, ExportCallbackConfigParsed
 func TestE2E_CallbackConfigExport(t *testing.T) {
 	// Ensure at least one callback exists
 	_ = EnsureCallbackExists(t)
@@ -383,6 +802,15 @@ func TestE2E_CallbackConfigExport(t *testing.T) {
 		}
 	}
 
+	parsed, err := client.ExportCallbackConfigParsed(ctx, testCallback.AgentCallbackID)
+	if err != nil {
+		t.Fatalf("ExportCallbackConfigParsed failed: %v", err)
+	}
+	if _, err := parsed.Marshal(); err != nil {
+		t.Errorf("CallbackConfig.Marshal failed: %v", err)
+	}
+	t.Logf("✓ Parsed config for agent %q (%d extra keys)", parsed.AgentCallbackID, len(parsed.Extra))
+
 	t.Log("=== ✓ Config export tests passed ===")
 }
 
@@ -519,27 +947,34 @@ func TestE2E_CallbackTimestamps(t *testing.T) {
 
 	// Analyze checkin times
 	now := time.Now()
-	recentCheckins := 0 // Last 5 minutes
-	last24h := 0        // Last 24 hours
-	stale := 0          // > 24 hours
-
+	buckets := make(map[types.CheckinBucket]int)
 	for _, cb := range callbacks {
 		if cb.Active {
-			age := now.Sub(cb.LastCheckin)
-			if age < 5*time.Minute {
-				recentCheckins++
-			} else if age < 24*time.Hour {
-				last24h++
-			} else {
-				stale++
-			}
+			buckets[cb.CheckinBucket(now)]++
 		}
 	}
+	stale := buckets[types.CheckinStale] + buckets[types.CheckinNever]
 
 	t.Logf("  Active callback checkin distribution:")
-	t.Logf("    Last 5 minutes: %d", recentCheckins)
-	t.Logf("    Last 24 hours: %d", last24h)
-	t.Logf("    Stale (>24h): %d", stale)
+	t.Logf("    Last 5 minutes: %d", buckets[types.CheckinRecent])
+	t.Logf("    Last 24 hours: %d", buckets[types.CheckinWithinDay])
+	t.Logf("    Stale (>24h): %d", buckets[types.CheckinStale])
+	t.Logf("    Never checked in: %d", buckets[types.CheckinNever])
+
+	// Compare with the server-side stale query
+	staleCallbacks, err := client.GetStaleCallbacks(ctx, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetStaleCallbacks failed: %v", err)
+	}
+	for _, cb := range staleCallbacks {
+		if !cb.Active {
+			t.Errorf("Stale callback %d should be active", cb.DisplayID)
+		}
+		if cb.CheckinAge() < 24*time.Hour {
+			t.Errorf("Stale callback %d checked in %s ago, expected over 24h", cb.DisplayID, cb.CheckinAge())
+		}
+	}
+	t.Logf("✓ GetStaleCallbacks returned %d callbacks (client-side count: %d)", len(staleCallbacks), stale)
 
 	// Find newest and oldest callbacks
 	var newest, oldest time.Time
@@ -669,7 +1104,7 @@ func TestE2E_Callbacks_Comprehensive_Summary(t *testing.T) {
 	t.Log("  1. ✓ CallbackRetrieval - GetAllCallbacks, GetAllActiveCallbacks, GetCallbackByID")
 	t.Log("  2. ✓ CallbackAttributes - Attribute analysis (OS, arch, integrity, etc.)")
 	t.Log("  3. ✓ CallbackUpdate - UpdateCallback (description modification)")
-	t.Log("  4. ✓ CallbackGraph - AddCallbackGraphEdge, RemoveCallbackGraphEdge")
+	t.Log("  4. ✓ CallbackGraph - AddCallbackGraphEdge, GetCallbackGraphEdges, GetCallbackGraphEdgesByOperation, RemoveCallbackGraphEdge")
 	t.Log("  5. ✓ CallbackConfigExport - ExportCallbackConfig")
 	t.Log("  6. ✓ CallbackConfigImport - ImportCallbackConfig (skipped for safety)")
 	t.Log("  7. ✓ CallbackErrorHandling - Error scenarios and validation")
@@ -514,3 +514,40 @@ func TestE2E_Commands_BuildTaskParams_MissingRequired(t *testing.T) {
 
 	t.Log("=== ✓ BuildTaskParams error handling validation passed ===")
 }
+
+// TestE2E_Commands_CommandCache validates that EnableCommandCache returns the
+// same command definition as the server and that invalidation refetches it.
+// Covers: EnableCommandCache, InvalidateCommandCache
+func TestE2E_Commands_CommandCache(t *testing.T) {
+	client := AuthenticateTestClient(t)
+
+	t.Log("=== Test: GetCommandWithParameters with command cache ===")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	commands, err := client.GetCommands(ctx)
+	require.NoError(t, err, "GetCommands should succeed")
+	require.NotEmpty(t, commands, "Should return at least one command")
+	cmd := commands[0]
+
+	uncached, err := client.GetCommandWithParameters(ctx, cmd.PayloadTypeID, cmd.Cmd)
+	require.NoError(t, err, "GetCommandWithParameters should succeed without cache")
+
+	client.EnableCommandCache(time.Minute)
+	defer client.EnableCommandCache(0)
+
+	for i := 0; i < 2; i++ {
+		cached, err := client.GetCommandWithParameters(ctx, cmd.PayloadTypeID, cmd.Cmd)
+		require.NoError(t, err, "GetCommandWithParameters should succeed with cache")
+		assert.Equal(t, uncached.Command.ID, cached.Command.ID, "Cached command ID mismatch")
+		assert.Len(t, cached.Parameters, len(uncached.Parameters), "Cached parameter count mismatch")
+	}
+	t.Logf("✓ Cached lookups match server result for %s", cmd.Cmd)
+
+	client.InvalidateCommandCache()
+	refetched, err := client.GetCommandWithParameters(ctx, cmd.PayloadTypeID, cmd.Cmd)
+	require.NoError(t, err, "GetCommandWithParameters should succeed after invalidation")
+	assert.Equal(t, uncached.Command.ID, refetched.Command.ID, "Refetched command ID mismatch")
+
+	t.Log("=== ✓ Command cache validation passed ===")
+}
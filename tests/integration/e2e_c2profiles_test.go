@@ -4,9 +4,12 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -416,3 +419,73 @@ func TestE2E_C2ProfileAttributes(t *testing.T) {
 
 	t.Log("=== ✓ Attribute analysis complete ===")
 }
+
+// TestE2E_C2ProfileParameters tests that the http profile's parameters decode
+// cleanly, including its dictionary and choice parameters.
+// Covers: GetC2ProfileParameters, GetC2ProfileParametersByName
+func TestE2E_C2ProfileParameters(t *testing.T) {
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	t.Log("=== Test 1: Find http profile ===")
+	profiles, err := client.GetC2Profiles(ctx)
+	if err != nil {
+		t.Fatalf("GetC2Profiles failed: %v", err)
+	}
+	var httpProfile *types.C2Profile
+	for _, profile := range profiles {
+		if profile.Name == "http" {
+			httpProfile = profile
+			break
+		}
+	}
+	if httpProfile == nil {
+		t.Skip("http C2 profile not installed")
+	}
+	t.Logf("✓ Using http profile (ID: %d)", httpProfile.ID)
+
+	t.Log("=== Test 2: Get http profile parameters ===")
+	params, err := client.GetC2ProfileParameters(ctx, httpProfile.ID)
+	if err != nil {
+		t.Fatalf("GetC2ProfileParameters failed: %v", err)
+	}
+	if len(params) == 0 {
+		t.Fatal("Expected http profile to have parameters")
+	}
+
+	var callbackHost *types.C2ProfileParameter
+	for _, param := range params {
+		if param.C2ProfileID != httpProfile.ID {
+			t.Errorf("Parameter %s has profile ID %d, expected %d", param.Name, param.C2ProfileID, httpProfile.ID)
+		}
+		if !json.Valid([]byte(param.Choices)) {
+			t.Errorf("Parameter %s choices are not valid JSON: %q", param.Name, param.Choices)
+		}
+		if param.Name == "callback_host" {
+			callbackHost = param
+		}
+		t.Logf("  - %s (default: %q)", param.String(), param.DefaultValue)
+	}
+	if callbackHost == nil {
+		t.Error("Expected http profile to have a callback_host parameter")
+	}
+	t.Logf("✓ Decoded %d http profile parameters", len(params))
+
+	byName, err := client.GetC2ProfileParametersByName(ctx, "http")
+	if err != nil {
+		t.Fatalf("GetC2ProfileParametersByName failed: %v", err)
+	}
+	if len(byName) != len(params) {
+		t.Errorf("GetC2ProfileParametersByName returned %d parameters, expected %d", len(byName), len(params))
+	}
+	t.Log("✓ Parameters by name match parameters by ID")
+
+	t.Log("=== Test 3: Non-existent profile ===")
+	_, err = client.GetC2ProfileParameters(ctx, 999999)
+	if !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for non-existent profile, got %v", err)
+	}
+	t.Log("✓ Non-existent profile rejected")
+}
@@ -460,11 +460,12 @@ func EnsurePayloadExists(t *testing.T) string {
 
 	client := AuthenticateTestClient(t)
 
-	// Check if any payloads already exist
+	// Check if any live payloads already exist (deleted ones are skipped)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	payloads, err := client.GetPayloads(ctx)
+	live := false
+	payloads, err := client.SearchPayloads(ctx, &mythic.PayloadQueryOptions{Deleted: &live})
 	if err != nil {
 		t.Fatalf("Failed to check for existing payloads: %v", err)
 	}
@@ -476,6 +477,21 @@ func EnsurePayloadExists(t *testing.T) string {
 
 	// No payloads exist - need to create one
 	t.Log("No payloads found - creating shared payload for tests")
+	payload := createPoseidonTestPayload(t, client, "Shared Test Payload", "shared_test_payload")
+
+	t.Logf("✓ Shared payload created: UUID %s", payload.UUID)
+
+	// NOTE: We intentionally do NOT register cleanup to delete the payload
+	// because it is shared across all tests. The Docker container will be
+	// torn down after the test run anyway, which will clean up the payload.
+
+	return payload.UUID
+}
+
+// createPoseidonTestPayload creates a new linux Poseidon payload with the http
+// C2 profile. Skips the test if Poseidon is unavailable. The caller owns cleanup.
+func createPoseidonTestPayload(t *testing.T, client *mythic.Client, description, filename string) *types.Payload {
+	t.Helper()
 
 	// Get Poseidon payload type
 	t.Log("Finding Poseidon payload type...")
@@ -527,15 +543,15 @@ func EnsurePayloadExists(t *testing.T) string {
 	}
 
 	// Create payload
-	t.Log("Creating shared test payload...")
+	t.Logf("Creating test payload %q...", filename)
 	ctx3, cancel3 := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel3()
 
 	payloadReq := &types.CreatePayloadRequest{
 		PayloadType: "poseidon",
 		OS:          "linux",
-		Description: "Shared Test Payload",
-		Filename:    "shared_test_payload",
+		Description: description,
+		Filename:    filename,
 		Commands: []string{
 			"shell", "ps", "whoami",
 		},
@@ -558,11 +574,5 @@ func EnsurePayloadExists(t *testing.T) string {
 		t.Fatalf("CreatePayload failed: %v", err)
 	}
 
-	t.Logf("✓ Shared payload created: UUID %s", payload.UUID)
-
-	// NOTE: We intentionally do NOT register cleanup to delete the payload
-	// because it is shared across all tests. The Docker container will be
-	// torn down after the test run anyway, which will clean up the payload.
-
-	return payload.UUID
+	return payload
 }
@@ -4,9 +4,83 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"testing"
+
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 )
 
+// TestGetEventGroups tests listing event groups and reading each back by ID.
+func TestGetEventGroups(t *testing.T) {
+	client := getTestClient(t)
+	ctx := context.Background()
+
+	groups, err := client.GetEventGroups(ctx)
+	if err != nil {
+		t.Fatalf("GetEventGroups failed: %v", err)
+	}
+	t.Logf("Found %d event groups", len(groups))
+
+	for _, group := range groups {
+		if group.Deleted {
+			t.Errorf("GetEventGroups returned deleted event group %d", group.ID)
+		}
+
+		fetched, err := client.GetEventGroupByID(ctx, group.ID)
+		if err != nil {
+			t.Fatalf("GetEventGroupByID(%d) failed: %v", group.ID, err)
+		}
+		if fetched.Name != group.Name {
+			t.Errorf("Event group %d name mismatch: %q vs %q", group.ID, fetched.Name, group.Name)
+		}
+		t.Logf("  %s (keywords: %v)", fetched.String(), fetched.Keywords)
+	}
+}
+
+// TestGetEventGroupByID_InvalidInput tests input validation and missing IDs for GetEventGroupByID.
+func TestGetEventGroupByID_InvalidInput(t *testing.T) {
+	client := getTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.GetEventGroupByID(ctx, 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetEventGroupByID with zero ID should return ErrInvalidInput, got: %v", err)
+	}
+
+	if _, err := client.GetEventGroupByID(ctx, 999999); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("GetEventGroupByID with nonexistent ID should return ErrNotFound, got: %v", err)
+	}
+}
+
+// TestGetEventExecutionStatus_InvalidInput tests input validation and missing IDs for execution status and step lookups.
+func TestGetEventExecutionStatus_InvalidInput(t *testing.T) {
+	client := getTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.GetEventExecutionStatus(ctx, 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetEventExecutionStatus with zero ID should return ErrInvalidInput, got: %v", err)
+	}
+
+	if _, err := client.GetEventExecutionStatus(ctx, 999999); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("GetEventExecutionStatus with nonexistent ID should return ErrNotFound, got: %v", err)
+	}
+
+	if _, err := client.WaitForEventExecution(ctx, 999999, 5); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("WaitForEventExecution with nonexistent ID should return ErrNotFound, got: %v", err)
+	}
+
+	if _, err := client.GetEventExecutionSteps(ctx, 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetEventExecutionSteps with zero ID should return ErrInvalidInput, got: %v", err)
+	}
+
+	steps, err := client.GetEventExecutionSteps(ctx, 999999)
+	if err != nil {
+		t.Fatalf("GetEventExecutionSteps with nonexistent ID failed: %v", err)
+	}
+	if len(steps) != 0 {
+		t.Errorf("Expected no steps for nonexistent execution, got %d", len(steps))
+	}
+}
+
 // TestEventingTriggerManual_InvalidInput tests input validation for EventingTriggerManual.
 func TestEventingTriggerManual_InvalidInput(t *testing.T) {
 	client := getTestClient(t)
@@ -325,6 +399,13 @@ func TestSendExternalWebhook_InvalidInput(t *testing.T) {
 		t.Fatal("SendExternalWebhook with empty URL should return error")
 	}
 	t.Logf("Empty URL error: %v", err)
+
+	// Test raw variant with malformed JSON body
+	_, err = client.SendExternalWebhookRaw(ctx, "http://example.com", "POST", nil, []byte("{not json"), 0)
+	if !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Fatalf("SendExternalWebhookRaw with malformed body should return ErrInvalidInput, got: %v", err)
+	}
+	t.Logf("Malformed body error: %v", err)
 }
 
 // TestSendExternalWebhook_InvalidURL tests webhook with invalid URL.
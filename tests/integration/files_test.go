@@ -3,9 +3,14 @@
 package integration
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"testing"
 	"time"
+
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 )
 
 func TestFiles_GetFiles(t *testing.T) {
@@ -107,6 +112,72 @@ func TestFiles_UploadFile(t *testing.T) {
 	t.Logf("Retrieved uploaded file: %s", file.String())
 }
 
+func TestFiles_UploadFileReader(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	testData := bytes.Repeat([]byte("streamed upload content\n"), 1024)
+	var lastSent int64
+	agentFileID, err := client.UploadFileReader(ctx, "upload_reader_test.txt", bytes.NewReader(testData), int64(len(testData)), func(sent int64) {
+		lastSent = sent
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload file: %v", err)
+	}
+
+	if lastSent != int64(len(testData)) {
+		t.Errorf("Final progress = %d, expected %d", lastSent, len(testData))
+	}
+
+	downloadedData, err := client.DownloadFile(ctx, agentFileID)
+	if err != nil {
+		t.Fatalf("Failed to download uploaded file: %v", err)
+	}
+
+	if !bytes.Equal(downloadedData, testData) {
+		t.Errorf("Downloaded %d bytes, expected %d", len(downloadedData), len(testData))
+	}
+
+	t.Logf("Streamed upload: %s (%d bytes)", agentFileID, lastSent)
+}
+
+func TestFiles_UploadFileWithProgress(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	testData := bytes.Repeat([]byte("progress upload content\n"), 8192)
+	var reports int
+	var lastSent, lastTotal int64
+	agentFileID, err := client.UploadFileWithProgress(ctx, "upload_progress_test.txt", bytes.NewReader(testData), int64(len(testData)), func(sent, total int64) {
+		reports++
+		lastSent, lastTotal = sent, total
+	})
+	if err != nil {
+		t.Fatalf("Failed to upload file: %v", err)
+	}
+
+	if lastSent != int64(len(testData)) || lastTotal != int64(len(testData)) {
+		t.Errorf("Final progress = %d/%d, expected %d/%d", lastSent, lastTotal, len(testData), len(testData))
+	}
+
+	downloadedData, err := client.DownloadFile(ctx, agentFileID)
+	if err != nil {
+		t.Fatalf("Failed to download uploaded file: %v", err)
+	}
+
+	if !bytes.Equal(downloadedData, testData) {
+		t.Errorf("Downloaded %d bytes, expected %d", len(downloadedData), len(testData))
+	}
+
+	t.Logf("Upload with progress: %s (%d bytes, %d reports)", agentFileID, lastSent, reports)
+}
+
 func TestFiles_UploadFile_MissingFilename(t *testing.T) {
 
 	client := AuthenticateTestClient(t)
@@ -159,6 +230,86 @@ func TestFiles_GetFileByID_NotFound(t *testing.T) {
 	t.Logf("Expected error for non-existent file: %v", err)
 }
 
+func TestFiles_WatchCompletedDownloads(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	files, errs := client.WatchCompletedDownloads(ctx)
+
+	// Operator uploads are not agent downloads and must not be reported
+	if _, err := client.UploadFile(ctx, "watch_downloads_test.txt", []byte("not an agent download")); err != nil {
+		t.Fatalf("Failed to upload test file: %v", err)
+	}
+
+	for file := range files {
+		if !file.IsDownloadFromAgent || !file.Complete {
+			t.Errorf("Unexpected file emitted: %s (download=%v, complete=%v)",
+				file.AgentFileID, file.IsDownloadFromAgent, file.Complete)
+		}
+		t.Logf("Download completed during watch: %s", file.Filename)
+	}
+
+	if err := <-errs; err != nil {
+		t.Fatalf("WatchCompletedDownloads failed: %v", err)
+	}
+
+	t.Log("Watch ended cleanly when the context expired")
+}
+
+func TestFiles_GetFilesByTask(t *testing.T) {
+	callbackID := EnsureCallbackExists(t)
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	tasks, err := client.GetTasksForCallback(ctx, callbackID, 10)
+	if err != nil {
+		t.Fatalf("Failed to get tasks: %v", err)
+	}
+	if len(tasks) == 0 {
+		t.Skip("No tasks available to correlate files with")
+	}
+
+	total := 0
+	for _, task := range tasks {
+		files, err := client.GetFilesByTask(ctx, task.DisplayID, false)
+		if err != nil {
+			t.Fatalf("Failed to get files for task %d: %v", task.DisplayID, err)
+		}
+
+		for _, file := range files {
+			if file.TaskID == nil || *file.TaskID != task.ID {
+				t.Errorf("File %s does not belong to task %d", file.AgentFileID, task.ID)
+			}
+			if file.Deleted {
+				t.Errorf("File %s is deleted but deleted files were excluded", file.AgentFileID)
+			}
+		}
+		total += len(files)
+	}
+
+	t.Logf("Found %d files across %d tasks", total, len(tasks))
+}
+
+func TestFiles_GetFilesByTask_InvalidID(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.GetFilesByTask(ctx, 0, false)
+	if err == nil {
+		t.Fatal("Expected error for invalid task ID, got nil")
+	}
+
+	t.Logf("Expected error for invalid task ID: %v", err)
+}
+
 func TestFiles_DownloadFile(t *testing.T) {
 
 	client := AuthenticateTestClient(t)
@@ -191,6 +342,84 @@ func TestFiles_DownloadFile(t *testing.T) {
 	// Some versions base64 encode, some don't
 }
 
+func TestFiles_DownloadFileStream(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Upload a file large enough to be streamed rather than buffered
+	testData := bytes.Repeat([]byte("stream download content\n"), 1024)
+	agentFileID, err := client.UploadFile(ctx, "download_stream_test.txt", testData)
+	if err != nil {
+		t.Fatalf("Failed to upload test file: %v", err)
+	}
+
+	body, meta, err := client.DownloadFileStream(ctx, agentFileID)
+	if err != nil {
+		t.Fatalf("Failed to open download stream: %v", err)
+	}
+	defer body.Close()
+
+	if meta.AgentFileID != agentFileID {
+		t.Errorf("Expected metadata for %s, got %s", agentFileID, meta.AgentFileID)
+	}
+
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, body)
+	if err != nil {
+		t.Fatalf("Failed to read download stream: %v", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), testData) {
+		t.Errorf("Streamed content mismatch: got %d bytes, expected %d", n, len(testData))
+	}
+
+	t.Logf("Streamed %d bytes of %s", n, meta.Filename)
+}
+
+func TestFiles_DownloadFileToWriter(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	testData := bytes.Repeat([]byte("writer download content\n"), 1024)
+	agentFileID, err := client.UploadFile(ctx, "download_writer_test.txt", testData)
+	if err != nil {
+		t.Fatalf("Failed to upload test file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := client.DownloadFileToWriter(ctx, agentFileID, &buf)
+	if err != nil {
+		t.Fatalf("DownloadFileToWriter failed: %v", err)
+	}
+
+	if n != int64(len(testData)) || !bytes.Equal(buf.Bytes(), testData) {
+		t.Errorf("Written content mismatch: got %d bytes (reported %d), expected %d", buf.Len(), n, len(testData))
+	}
+
+	t.Logf("Wrote %d bytes", n)
+}
+
+func TestFiles_DownloadFileStream_EmptyID(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, _, err := client.DownloadFileStream(ctx, "")
+	if err == nil {
+		t.Fatal("Expected error for empty agent_file_id, got nil")
+	}
+
+	t.Logf("Expected error for empty ID: %v", err)
+}
+
 func TestFiles_DownloadFile_NotFound(t *testing.T) {
 
 	client := AuthenticateTestClient(t)
@@ -367,6 +596,75 @@ func TestFiles_FilterByType(t *testing.T) {
 	}
 }
 
+func TestFiles_GetFilesByFilter(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Upload a file with a distinctive name so the filename filter has a match
+	filename := fmt.Sprintf("filter-test-%d.txt", time.Now().UnixNano())
+	fileID, err := client.UploadFile(ctx, filename, []byte("filter test"))
+	if err != nil {
+		t.Fatalf("Failed to upload file: %v", err)
+	}
+	defer func() { _ = client.DeleteFile(context.Background(), fileID) }()
+
+	files, err := client.GetFilesByFilter(ctx, &mythic.FileFilter{Filename: &filename}, 10)
+	if err != nil {
+		t.Fatalf("GetFilesByFilter failed: %v", err)
+	}
+	if len(files) != 1 || files[0].AgentFileID != fileID {
+		t.Fatalf("Expected only the uploaded file, got %d files", len(files))
+	}
+
+	// Uploaded files are not downloads from agents
+	downloaded := true
+	files, err = client.GetFilesByFilter(ctx, &mythic.FileFilter{Filename: &filename, IsDownloadFromAgent: &downloaded}, 10)
+	if err != nil {
+		t.Fatalf("GetFilesByFilter failed: %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Expected no agent downloads named %s, got %d", filename, len(files))
+	}
+}
+
+func TestFiles_GetFilesForOperation(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	opID := client.GetCurrentOperation()
+	if opID == nil {
+		t.Skip("No current operation set")
+	}
+
+	fileID, err := client.UploadFile(ctx, "operation-scope-test.txt", []byte("operation scope"))
+	if err != nil {
+		t.Fatalf("Failed to upload file: %v", err)
+	}
+	defer func() { _ = client.DeleteFile(context.Background(), fileID) }()
+
+	files, err := client.GetFilesForOperation(ctx, *opID, 100)
+	if err != nil {
+		t.Fatalf("GetFilesForOperation failed: %v", err)
+	}
+
+	found := false
+	for _, file := range files {
+		if file.AgentFileID == fileID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Uploaded file %s not listed for operation %d", fileID, *opID)
+	}
+	t.Logf("Found %d files in operation %d", len(files), *opID)
+}
+
 func TestFiles_BulkDownload(t *testing.T) {
 
 	client := AuthenticateTestClient(t)
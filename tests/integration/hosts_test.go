@@ -28,7 +28,11 @@ func TestHosts_GetHosts(t *testing.T) {
 		if host.ID == 0 {
 			t.Error("Host ID should not be 0")
 		}
-		t.Logf("  - %s: %s (%s %s)", host.Hostname, host.IP, host.OS, host.Architecture)
+		if host.ActiveCallbackCount > host.CallbackCount {
+			t.Errorf("Host %s has %d active of %d callbacks", host.Hostname, host.ActiveCallbackCount, host.CallbackCount)
+		}
+		t.Logf("  - %s: %s (%s %s) %d callback(s), last seen %s",
+			host.Hostname, host.IP, host.OS, host.Architecture, host.CallbackCount, host.LastSeen)
 	}
 }
 
@@ -6,6 +6,8 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 )
 
 func TestKeylogs_GetKeylogs(t *testing.T) {
@@ -138,6 +140,22 @@ func TestKeylogs_GetKeylogsByCallback(t *testing.T) {
 		if kl.CallbackID != callbackID {
 			t.Errorf("Expected callback ID %d, got %d", callbackID, kl.CallbackID)
 		}
+		if kl.CallbackDisplayID != callbacks[0].DisplayID {
+			t.Errorf("Expected callback display ID %d, got %d", callbacks[0].DisplayID, kl.CallbackDisplayID)
+		}
+	}
+
+	// The same keylogs should come back when filtering by display ID and time range
+	searched, err := client.SearchKeylogs(ctx, &mythic.KeylogQueryOptions{
+		CallbackDisplayID: callbacks[0].DisplayID,
+		Since:             time.Now().Add(-365 * 24 * time.Hour),
+		Until:             time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("SearchKeylogs failed: %v", err)
+	}
+	if len(searched) != len(keylogs) {
+		t.Errorf("SearchKeylogs returned %d keylog(s), GetKeylogsByCallback returned %d", len(searched), len(keylogs))
 	}
 
 	// Verify keylogs are sorted by timestamp (descending)
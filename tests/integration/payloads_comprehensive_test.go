@@ -4,10 +4,12 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -246,6 +248,19 @@ func TestE2E_Payloads_WaitForComplete(t *testing.T) {
 		t.Logf("  Payload still building - wait result: %v", err)
 	}
 
+	// WaitForPayloadBuild returns the payload it last saw
+	ctx3, cancel3 := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel3()
+
+	built, err := client.WaitForPayloadBuild(ctx3, payloadUUID, 5)
+	if payload.IsReady() {
+		require.NoError(t, err, "WaitForPayloadBuild should succeed for ready payload")
+	}
+	if built != nil {
+		assert.Equal(t, payloadUUID, built.UUID, "WaitForPayloadBuild should return the polled payload")
+		t.Logf("✓ WaitForPayloadBuild returned payload in phase %s (err: %v)", built.BuildPhase, err)
+	}
+
 	t.Log("=== ✓ WaitForPayloadComplete validation passed ===")
 }
 
@@ -388,6 +403,14 @@ func TestE2E_Payloads_Download(t *testing.T) {
 
 	t.Logf("✓ Downloaded payload: %d bytes", len(data))
 
+	// The payload's file should resolve to the same content via DownloadFile
+	fileID, err := client.GetPayloadFileID(ctx2, payloadUUID)
+	require.NoError(t, err, "GetPayloadFileID should succeed for ready payload")
+	fileData, err := client.DownloadFile(ctx2, fileID)
+	require.NoError(t, err, "DownloadFile should succeed for payload file")
+	assert.Equal(t, len(data), len(fileData), "DownloadFile should return the payload content")
+	t.Logf("✓ Payload file %s matches DownloadPayload", fileID)
+
 	// Verify it looks like binary data (has non-printable bytes)
 	hasBinary := false
 	for _, b := range data[:min(100, len(data))] {
@@ -448,51 +471,71 @@ func TestE2E_Payloads_GetOnHost(t *testing.T) {
 	t.Log("=== ✓ GetPayloadOnHost validation passed ===")
 }
 
-// TestE2E_Payloads_UpdateAndDelete validates UpdatePayload and DeletePayload operations.
-// Combined test since both have limited support in current API.
+// TestE2E_Payloads_UpdateAndDelete validates UpdatePayloadDescription and DeletePayload
+// against a dedicated payload so the shared test payload is left untouched.
 func TestE2E_Payloads_UpdateAndDelete(t *testing.T) {
-	// Ensure at least one payload exists (reuses existing or creates one)
-	payloadUUID := EnsurePayloadExists(t)
-
 	client := AuthenticateTestClient(t)
 
-	t.Log("=== Test: UpdatePayload and DeletePayload ===")
-	t.Logf("✓ Testing with payload: %s", payloadUUID)
-
-	// Test Update (limited support)
-	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel2()
+	t.Log("=== Test: UpdatePayloadDescription and DeletePayload ===")
 
-	newDesc := "Updated description from comprehensive test"
-	updateReq := &types.UpdatePayloadRequest{
-		UUID:        payloadUUID,
-		Description: &newDesc,
-	}
+	payload := createPoseidonTestPayload(t, client, "Update/delete test payload", "update_delete_test_payload")
+	deleted := false
+	t.Cleanup(func() {
+		if deleted {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := client.DeletePayload(ctx, payload.UUID); err != nil {
+			t.Logf("⚠ Failed to clean up test payload %s: %v", payload.UUID, err)
+		}
+	})
+	t.Logf("✓ Created test payload: %s", payload.UUID)
 
-	updatedPayload, err := client.UpdatePayload(ctx2, updateReq)
+	// Update description
+	ctx1, cancel1 := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel1()
 
-	// Update may not be fully supported - just verify it doesn't crash
-	if err != nil {
-		t.Logf("⚠ UpdatePayload returned error (expected - limited support): %v", err)
-	} else {
-		require.NotNil(t, updatedPayload, "UpdatePayload should return payload")
-		t.Logf("✓ UpdatePayload succeeded: UUID=%s", updatedPayload.UUID)
-	}
+	newDesc := "Updated description from comprehensive test"
+	err := client.UpdatePayloadDescription(ctx1, payload.UUID, newDesc)
+	require.NoError(t, err, "UpdatePayloadDescription should succeed")
+
+	updated, err := client.GetPayloadByUUID(ctx1, payload.UUID)
+	require.NoError(t, err, "GetPayloadByUUID should succeed after update")
+	assert.Equal(t, newDesc, updated.Description, "Description should be updated")
+	t.Logf("✓ Description updated: %q", updated.Description)
+
+	// UpdatePayload routes description changes through the same mutation
+	otherDesc := "Updated again via UpdatePayload"
+	updated, err = client.UpdatePayload(ctx1, &types.UpdatePayloadRequest{
+		UUID:        payload.UUID,
+		Description: &otherDesc,
+	})
+	require.NoError(t, err, "UpdatePayload should succeed")
+	assert.Equal(t, otherDesc, updated.Description, "UpdatePayload should return the new description")
+
+	// Delete
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel2()
 
-	// Test Delete (also limited support)
-	ctx3, cancel3 := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel3()
+	err = client.DeletePayload(ctx2, payload.UUID)
+	require.NoError(t, err, "DeletePayload should succeed")
+	deleted = true
 
-	err = client.DeletePayload(ctx3, payloadUUID)
+	afterDelete, err := client.GetPayloadByUUID(ctx2, payload.UUID)
+	require.NoError(t, err, "Deleted payload should still be retrievable by UUID")
+	assert.True(t, afterDelete.Deleted, "Payload should be marked deleted")
+	t.Log("✓ Payload marked deleted")
 
-	// Delete may not be fully supported - just verify it doesn't crash
-	if err != nil {
-		t.Logf("⚠ DeletePayload returned error (expected - limited support): %v", err)
-	} else {
-		t.Log("✓ DeletePayload succeeded (may not actually delete)")
-	}
+	// Unknown UUIDs affect no rows
+	missing := "00000000-0000-0000-0000-000000000000"
+	err = client.DeletePayload(ctx2, missing)
+	assert.True(t, errors.Is(err, mythic.ErrNotFound), "DeletePayload on unknown UUID should return ErrNotFound, got %v", err)
+	err = client.UpdatePayloadDescription(ctx2, missing, "nope")
+	assert.True(t, errors.Is(err, mythic.ErrNotFound), "UpdatePayloadDescription on unknown UUID should return ErrNotFound, got %v", err)
+	t.Log("✓ Unknown UUID returns ErrNotFound")
 
-	t.Log("=== ✓ UpdatePayload and DeletePayload validation passed ===")
+	t.Log("=== ✓ UpdatePayloadDescription and DeletePayload validation passed ===")
 }
 
 // TestE2E_Payloads_Comprehensive_Summary provides a summary of all payload test coverage.
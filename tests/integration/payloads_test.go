@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -77,6 +78,49 @@ func TestPayloads_GetPayloads(t *testing.T) {
 	}
 }
 
+func TestPayloads_SearchPayloads(t *testing.T) {
+
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	live := false
+	payloads, err := client.SearchPayloads(ctx, &mythic.PayloadQueryOptions{
+		Deleted:    &live,
+		BuildPhase: "success",
+		Limit:      20,
+	})
+	if err != nil {
+		t.Fatalf("SearchPayloads failed: %v", err)
+	}
+
+	t.Logf("Found %d built payload(s)", len(payloads))
+
+	if len(payloads) > 20 {
+		t.Errorf("Expected at most 20 payloads, got %d", len(payloads))
+	}
+	for _, p := range payloads {
+		if p.Deleted || p.BuildPhase != "success" {
+			t.Errorf("Payload %s does not match filter (deleted=%v, build_phase=%s)", p.UUID, p.Deleted, p.BuildPhase)
+		}
+	}
+
+	if len(payloads) > 0 && payloads[0].PayloadType != nil {
+		typeName := payloads[0].PayloadType.Name
+		byType, err := client.SearchPayloads(ctx, &mythic.PayloadQueryOptions{PayloadType: typeName})
+		if err != nil {
+			t.Fatalf("SearchPayloads by type failed: %v", err)
+		}
+		for _, p := range byType {
+			if p.PayloadType == nil || p.PayloadType.Name != typeName {
+				t.Errorf("Payload %s does not have payload type %s", p.UUID, typeName)
+			}
+		}
+		t.Logf("Found %d %s payload(s)", len(byType), typeName)
+	}
+}
+
 func TestPayloads_CreateAndManagePayload(t *testing.T) {
 
 	client := AuthenticateTestClient(t)
@@ -218,13 +262,7 @@ func TestPayloads_CreateAndManagePayload(t *testing.T) {
 	}
 
 	// Cleanup: Delete the payload
-	deleted := true
-	cleanupReq := &types.UpdatePayloadRequest{
-		UUID:    payload.UUID,
-		Deleted: &deleted,
-	}
-
-	_, err = client.UpdatePayload(ctx, cleanupReq)
+	err = client.DeletePayload(ctx, payload.UUID)
 	if err != nil {
 		t.Logf("Warning: Failed to delete test payload: %v", err)
 	} else {
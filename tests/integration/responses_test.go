@@ -14,7 +14,7 @@ import (
 )
 
 // TestE2E_ResponseRetrieval tests comprehensive response retrieval operations.
-// Covers: GetResponsesByTask, GetResponseByID, GetResponsesByCallback, GetLatestResponses
+// Covers: GetResponsesByTask, GetResponseByID, GetResponsesByIDs, GetResponsesByCallback, GetLatestResponses
 func TestE2E_ResponseRetrieval(t *testing.T) {
 	// Ensure at least one callback exists (reuses existing or creates one)
 	callbackID := EnsureCallbackExists(t)
@@ -125,6 +125,26 @@ func TestE2E_ResponseRetrieval(t *testing.T) {
 	}
 	t.Logf("✓ Retrieved %d responses for callback %d", len(callbackResponses), testCallback.ID)
 
+	if len(callbackResponses) > 0 {
+		ids := []int{999999}
+		for _, r := range callbackResponses {
+			ids = append(ids, r.ID)
+		}
+		batch, err := client.GetResponsesByIDs(ctx4, ids)
+		if err != nil {
+			t.Fatalf("GetResponsesByIDs failed: %v", err)
+		}
+		if len(batch) != len(callbackResponses) {
+			t.Errorf("GetResponsesByIDs returned %d responses, expected %d", len(batch), len(callbackResponses))
+		}
+		for i := range batch {
+			if i < len(callbackResponses) && batch[i].ID != callbackResponses[i].ID {
+				t.Errorf("GetResponsesByIDs order mismatch at %d: got %d, expected %d", i, batch[i].ID, callbackResponses[i].ID)
+			}
+		}
+		t.Logf("✓ Batch retrieved %d responses by ID", len(batch))
+	}
+
 	// Test 5: Get latest responses across operation
 	t.Log("=== Test 5: Get latest responses across operation ===")
 	ctx5, cancel5 := context.WithTimeout(context.Background(), 30*time.Second)
@@ -297,11 +317,44 @@ func TestE2E_ResponseSearch(t *testing.T) {
 		t.Log("  ✓ Pages have no overlapping responses")
 	}
 
+	// Test 6: Search modes
+	t.Log("=== Test 6: Search with regex and case-sensitive modes ===")
+	ctx7, cancel7 := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel7()
+
+	regexResults, err := client.SearchResponses(ctx7, &types.ResponseSearchRequest{
+		Query:      `SEARCH_TEST_\d+`,
+		SearchMode: types.SearchModeRegex,
+		CallbackID: &testCallback.ID,
+		Limit:      10,
+	})
+	if err != nil {
+		t.Fatalf("SearchResponses (regex) failed: %v", err)
+	}
+	t.Logf("✓ Regex search found %d responses", len(regexResults))
+
+	lowerMarker := strings.ToLower(searchMarker)
+	sensitiveResults, err := client.SearchResponses(ctx7, &types.ResponseSearchRequest{
+		Query:      lowerMarker,
+		SearchMode: types.SearchModeSubstring,
+		TaskID:     &task.ID,
+		Limit:      10,
+	})
+	if err != nil {
+		t.Fatalf("SearchResponses (substring) failed: %v", err)
+	}
+	for _, r := range sensitiveResults {
+		if !strings.Contains(r.Response, lowerMarker) {
+			t.Errorf("Case-sensitive result %d does not contain %q", r.ID, lowerMarker)
+		}
+	}
+	t.Logf("✓ Case-sensitive search found %d responses", len(sensitiveResults))
+
 	t.Log("=== ✓ All search tests passed ===")
 }
 
 // TestE2E_ResponseStatistics tests response statistics and aggregation.
-// Covers: GetResponseStatistics
+// Covers: GetResponseStatistics, GetCallbackResponseStatistics
 func TestE2E_ResponseStatistics(t *testing.T) {
 	// Ensure at least one callback exists (reuses existing or creates one)
 	callbackID := EnsureCallbackExists(t)
@@ -373,6 +426,22 @@ func TestE2E_ResponseStatistics(t *testing.T) {
 		t.Error("Total size is negative")
 	}
 
+	// Test: Get callback-wide statistics
+	t.Log("=== Test: Get callback response statistics ===")
+	cbStats, err := client.GetCallbackResponseStatistics(ctx2, testCallback.ID)
+	if err != nil {
+		t.Fatalf("GetCallbackResponseStatistics failed: %v", err)
+	}
+	t.Logf("✓ Callback %d: %d responses (%d errors) across %d commands",
+		testCallback.ID, cbStats.ResponseCount, cbStats.ErrorCount, len(cbStats.ByCommand))
+
+	if cbStats.ResponseCount < stats.ResponseCount {
+		t.Errorf("Callback response count %d is less than task response count %d", cbStats.ResponseCount, stats.ResponseCount)
+	}
+	if shell := cbStats.ByCommand["shell"]; shell == nil || shell.TaskCount == 0 {
+		t.Error("Expected shell tasks in per-command breakdown")
+	}
+
 	t.Log("=== ✓ Statistics tests passed ===")
 }
 
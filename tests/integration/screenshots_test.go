@@ -4,6 +4,7 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -187,7 +188,7 @@ func TestE2E_ScreenshotDownload(t *testing.T) {
 	ctx2, cancel2 := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel2()
 
-	thumbData, err := client.GetScreenshotThumbnail(ctx2, completeScreenshot.AgentFileID)
+	thumbData, err := client.GetScreenshotThumbnail(ctx2, completeScreenshot.AgentFileID, nil)
 	if err != nil {
 		t.Logf("⚠ GetScreenshotThumbnail failed (may not be available): %v", err)
 	} else {
@@ -283,6 +284,83 @@ func TestE2E_ScreenshotTimeline(t *testing.T) {
 	t.Log("=== ✓ Screenshot timeline tests passed ===")
 }
 
+// TestE2E_ScreenshotsZip tests downloading a callback's screenshots as one archive.
+// Covers: DownloadScreenshotsZip
+func TestE2E_ScreenshotsZip(t *testing.T) {
+	callbackID := EnsureCallbackExists(t)
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// A window in the future never matches, so no empty archive is produced
+	t.Log("=== Test 1: Empty time range ===")
+	start := time.Now().Add(time.Hour)
+	end := start.Add(time.Hour)
+	_, err := client.DownloadScreenshotsZip(ctx, callbackID, &start, &end)
+	if !errors.Is(err, mythic.ErrNotFound) {
+		t.Fatalf("Expected ErrNotFound for empty range, got %v", err)
+	}
+	t.Log("  ✓ Empty range rejected")
+
+	t.Log("=== Test 2: All screenshots ===")
+	screenshots, err := client.GetScreenshotTimeline(ctx, callbackID, nil, nil)
+	if err != nil {
+		t.Fatalf("GetScreenshotTimeline failed: %v", err)
+	}
+	if len(screenshots) == 0 {
+		t.Log("⚠ No screenshots for callback, skipping archive download")
+		return
+	}
+
+	archive, err := client.DownloadScreenshotsZip(ctx, callbackID, nil, nil)
+	if err != nil {
+		t.Fatalf("DownloadScreenshotsZip failed: %v", err)
+	}
+	if len(archive) < 4 || string(archive[:2]) != "PK" {
+		t.Errorf("Expected a ZIP archive, got %d bytes", len(archive))
+	}
+	t.Logf("  ✓ Downloaded %d screenshots as a %d byte archive", len(screenshots), len(archive))
+
+	t.Log("=== ✓ Screenshot archive tests passed ===")
+}
+
+// TestE2E_ScreenshotsBatchDownload tests downloading a callback's screenshots concurrently.
+// Covers: DownloadScreenshots
+func TestE2E_ScreenshotsBatchDownload(t *testing.T) {
+	callbackID := EnsureCallbackExists(t)
+	client := AuthenticateTestClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	screenshots, err := client.GetScreenshots(ctx, callbackID, 10)
+	if err != nil {
+		t.Fatalf("GetScreenshots failed: %v", err)
+	}
+
+	images, err := client.DownloadScreenshots(ctx, callbackID, 10)
+	if err != nil {
+		t.Fatalf("DownloadScreenshots failed: %v", err)
+	}
+
+	for _, screenshot := range screenshots {
+		data, ok := images[screenshot.AgentFileID]
+		if !screenshot.Complete {
+			if ok {
+				t.Errorf("Incomplete screenshot %s should be skipped", screenshot.AgentFileID)
+			}
+			continue
+		}
+		if !ok || len(data) == 0 {
+			t.Errorf("Complete screenshot %s was not downloaded", screenshot.AgentFileID)
+		}
+	}
+	t.Logf("✓ Downloaded %d of %d screenshots", len(images), len(screenshots))
+
+	t.Log("=== ✓ Screenshot batch download tests passed ===")
+}
+
 // TestE2E_ScreenshotDeletion tests screenshot deletion operations.
 // Covers: DeleteScreenshot
 func TestE2E_ScreenshotDeletion(t *testing.T) {
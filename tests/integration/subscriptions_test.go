@@ -155,6 +155,7 @@ func TestSubscription_MultipleTypes(t *testing.T) {
 		types.SubscriptionTypeTaskOutput,
 		types.SubscriptionTypeCallback,
 		types.SubscriptionTypeFile,
+		types.SubscriptionTypeNewTask,
 	}
 
 	for _, subType := range subscriptionTypes {
@@ -199,11 +200,18 @@ func TestSubscription_Filters(t *testing.T) {
 		},
 		Filter: map[string]interface{}{
 			"callback_id": 123,
-			"host":        "workstation-01",
+			"task_id":     456,
 		},
 		OperationID: 1,
 	}
 
+	// Unsupported filter keys are rejected before subscribing
+	badConfig := *config
+	badConfig.Filter = map[string]interface{}{"host": "workstation-01"}
+	if _, err := client.Subscribe(ctx, &badConfig); err == nil {
+		t.Error("Subscribe with unsupported filter key should return error")
+	}
+
 	sub, err := client.Subscribe(ctx, config)
 	if err != nil {
 		t.Logf("Subscribe with filters error (may be expected): %v", err)
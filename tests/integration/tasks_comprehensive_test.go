@@ -4,6 +4,7 @@ package integration
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -204,6 +205,39 @@ func TestE2E_Tasks_IssueTask_InvalidCommand(t *testing.T) {
 	t.Log("=== ✓ IssueTask error handling validation passed ===")
 }
 
+// TestE2E_Tasks_ValidateTaskRequest validates that unknown commands are
+// rejected client-side before any task is created.
+func TestE2E_Tasks_ValidateTaskRequest(t *testing.T) {
+	callbackID := EnsureCallbackExists(t)
+	client := AuthenticateTestClient(t)
+
+	t.Log("=== Test: ValidateTaskRequest ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := client.ValidateTaskRequest(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callbackID,
+	})
+	require.NoError(t, err, "shell should validate for the test callback")
+	t.Log("✓ Valid request passed pre-flight checks")
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "nonexistent_command_12345",
+		Params:     "test",
+		CallbackID: &callbackID,
+		Validate:   true,
+	})
+	require.Error(t, err, "unknown command should fail validation")
+	assert.True(t, errors.Is(err, mythic.ErrInvalidInput), "expected ErrInvalidInput, got %v", err)
+	assert.Nil(t, task)
+	t.Logf("✓ Unknown command rejected before tasking: %v", err)
+
+	t.Log("=== ✓ ValidateTaskRequest validation passed ===")
+}
+
 // TestE2E_Tasks_GetTask_Complete validates GetTask returns complete task data
 // with all fields properly populated.
 func TestE2E_Tasks_GetTask_Complete(t *testing.T) {
@@ -489,6 +523,73 @@ func TestE2E_Tasks_UpdateTask_Comment(t *testing.T) {
 	t.Log("=== ✓ UpdateTask validation passed ===")
 }
 
+// TestE2E_Tasks_ParamsBuilderForCommand validates that a builder created from
+// server-side command metadata rejects unknown parameters before submission.
+func TestE2E_Tasks_ParamsBuilderForCommand(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: NewTaskParamsBuilderForCommand ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	builder, err := client.NewTaskParamsBuilderForCommand(ctx, callback.PayloadTypeID, "shell")
+	if err != nil {
+		t.Skipf("⚠ shell command not available for payload type %d: %v", callback.PayloadTypeID, err)
+	}
+
+	_, err = builder.SetString("definitely_not_a_parameter", "x").Build()
+	require.Error(t, err, "Unknown parameter should be rejected")
+	assert.True(t, errors.Is(err, mythic.ErrInvalidInput), "Should wrap ErrInvalidInput")
+	t.Logf("✓ Unknown parameter rejected: %v", err)
+
+	_, err = client.NewTaskParamsBuilderForCommand(ctx, callback.PayloadTypeID, "")
+	require.Error(t, err, "Empty command name should be rejected")
+
+	t.Log("=== ✓ NewTaskParamsBuilderForCommand validation passed ===")
+}
+
+// TestE2E_Tasks_UpdateTasks validates bulk comment updates across tasks.
+func TestE2E_Tasks_UpdateTasks(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: UpdateTasks bulk comment ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var displayIDs []int
+	expectedIDs := make(map[int]bool)
+	for i := 0; i < 2; i++ {
+		task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+			Command:    "shell",
+			Params:     "whoami",
+			CallbackID: &callback.DisplayID,
+		})
+		require.NoError(t, err, "IssueTask should succeed")
+		displayIDs = append(displayIDs, task.DisplayID)
+		expectedIDs[task.ID] = true
+	}
+
+	testComment := "Bulk comment from comprehensive test"
+	updatedIDs, err := client.UpdateTasks(ctx, append(displayIDs, 999999999), map[string]interface{}{
+		"comment": testComment,
+	})
+	require.NoError(t, err, "UpdateTasks should succeed")
+	assert.Len(t, updatedIDs, len(displayIDs), "Only existing tasks should be updated")
+	for _, id := range updatedIDs {
+		assert.True(t, expectedIDs[id], "Updated task %d should be one of the issued tasks", id)
+	}
+
+	_, err = client.UpdateTasks(ctx, displayIDs, map[string]interface{}{})
+	require.Error(t, err, "Empty updates should be rejected")
+
+	t.Logf("✓ Bulk updated %d tasks", len(updatedIDs))
+	t.Log("=== ✓ UpdateTasks validation passed ===")
+}
+
 // TestE2E_Tasks_GetTasksByStatus_Filter validates status-based task filtering.
 func TestE2E_Tasks_GetTasksByStatus_Filter(t *testing.T) {
 	// Ensure at least one callback exists (reuses existing or creates one)
@@ -540,6 +641,118 @@ func TestE2E_Tasks_GetTasksByStatus_Filter(t *testing.T) {
 	t.Log("=== ✓ GetTasksByStatus validation passed ===")
 }
 
+// TestE2E_Tasks_GetTasksForCallbackFiltered validates server-side filtering of
+// a callback's tasks by command, status and time range.
+func TestE2E_Tasks_GetTasksForCallbackFiltered(t *testing.T) {
+	callbackID := EnsureCallbackExists(t)
+	client := AuthenticateTestClient(t)
+
+	t.Log("=== Test: GetTasksForCallbackFiltered ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	since := time.Now().Add(-24 * time.Hour)
+	tasks, err := client.GetTasksForCallbackFiltered(ctx, callbackID, &mythic.TaskQueryOptions{
+		CommandName: "shell",
+		Status:      mythic.TaskStatusCompleted,
+		Since:       since,
+		Limit:       20,
+	})
+	require.NoError(t, err, "GetTasksForCallbackFiltered should succeed")
+	assert.LessOrEqual(t, len(tasks), 20)
+	for i, task := range tasks {
+		assert.Equal(t, "shell", task.CommandName, "Task[%d] should be a shell task", i)
+		assert.Equal(t, "completed", task.Status, "Task[%d] should be completed", i)
+	}
+	t.Logf("✓ Found %d completed shell tasks from the last day", len(tasks))
+
+	// An empty window returns nothing
+	future, err := client.GetTasksForCallbackFiltered(ctx, callbackID, &mythic.TaskQueryOptions{
+		Since: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, future, "No tasks should be created in the future")
+	t.Log("✓ Future time range returned no tasks")
+
+	_, err = client.GetTasksForCallbackFiltered(ctx, callbackID, &mythic.TaskQueryOptions{
+		Since: time.Now(),
+		Until: time.Now().Add(-time.Hour),
+	})
+	assert.True(t, errors.Is(err, mythic.ErrInvalidInput), "inverted time range should be rejected, got %v", err)
+	t.Log("✓ Inverted time range rejected")
+
+	// Paging in ascending order walks the same tasks oldest first
+	oldest, err := client.GetTasksForCallbackFiltered(ctx, callbackID, &mythic.TaskQueryOptions{
+		CompletedOnly: true,
+		Ascending:     true,
+		Limit:         4,
+	})
+	require.NoError(t, err)
+	for i := 1; i < len(oldest); i++ {
+		assert.Less(t, oldest[i-1].ID, oldest[i].ID, "Ascending results should be ordered oldest first")
+	}
+	for i, task := range oldest {
+		assert.True(t, task.Completed, "Task[%d] should be completed", i)
+	}
+	if len(oldest) == 4 {
+		page, err := client.GetTasksForCallbackFiltered(ctx, callbackID, &mythic.TaskQueryOptions{
+			CompletedOnly: true,
+			Ascending:     true,
+			Limit:         2,
+			Offset:        2,
+		})
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, oldest[2].ID, page[0].ID, "Offset should skip the first two tasks")
+	}
+	t.Logf("✓ Paged through %d completed tasks oldest first", len(oldest))
+
+	t.Log("=== ✓ GetTasksForCallbackFiltered validation passed ===")
+}
+
+// TestE2E_Tasks_SearchTasks validates operation-wide task search.
+// Covers: SearchTasks
+func TestE2E_Tasks_SearchTasks(t *testing.T) {
+	callbackID := EnsureCallbackExists(t)
+	client := AuthenticateTestClient(t)
+
+	t.Log("=== Test: SearchTasks ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	callback, err := client.GetCallbackByID(ctx, callbackID)
+	require.NoError(t, err, "GetCallbackByID should succeed")
+
+	all, err := client.SearchTasks(ctx, &mythic.TaskSearchRequest{Limit: 20})
+	require.NoError(t, err, "SearchTasks should succeed")
+	for i := 1; i < len(all); i++ {
+		assert.False(t, all[i].Timestamp.After(all[i-1].Timestamp), "Results should be newest first")
+	}
+	t.Logf("✓ Found %d tasks in the current operation", len(all))
+
+	scoped, err := client.SearchTasks(ctx, &mythic.TaskSearchRequest{
+		CallbackID:  &callback.ID,
+		CommandName: "shell",
+		Limit:       20,
+	})
+	require.NoError(t, err, "SearchTasks with filters should succeed")
+	for i, task := range scoped {
+		assert.Equal(t, callback.ID, task.CallbackID, "Task[%d] should belong to the callback", i)
+		assert.Equal(t, "shell", task.CommandName, "Task[%d] should be a shell task", i)
+	}
+	t.Logf("✓ Found %d shell tasks on callback %d", len(scoped), callbackID)
+
+	future := time.Now().Add(time.Hour)
+	none, err := client.SearchTasks(ctx, &mythic.TaskSearchRequest{StartTime: &future})
+	require.NoError(t, err)
+	assert.Empty(t, none, "No tasks should be created in the future")
+	t.Log("✓ Future time range returned no tasks")
+
+	t.Log("=== ✓ SearchTasks validation passed ===")
+}
+
 // TestE2E_Tasks_ReissueTask validates ReissueTask creates a new task instance.
 func TestE2E_Tasks_ReissueTask(t *testing.T) {
 	// Ensure at least one callback exists (reuses existing or creates one)
@@ -688,14 +901,21 @@ func TestE2E_Tasks_AddMITREAttack(t *testing.T) {
 	} else {
 		t.Log("✓ MITRE ATT&CK tag added successfully")
 
-		// Verify tag persisted (if SDK has a method to retrieve tags)
+		// Verify tag persisted
 		ctx3, cancel3 := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel3()
 
-		updatedTask, err := client.GetTask(ctx3, task.DisplayID)
-		if err == nil {
-			t.Logf("✓ Task retrieved after tagging: Status=%s", updatedTask.Status)
+		techniques, err := client.GetMITREAttackForTask(ctx3, task.DisplayID)
+		require.NoError(t, err, "GetMITREAttackForTask should succeed")
+
+		found := false
+		for _, tech := range techniques {
+			if tech.TNum == "T1033" {
+				found = true
+				t.Logf("✓ Tag persisted: %s (%s)", tech.String(), tech.Tactic)
+			}
 		}
+		assert.True(t, found, "T1033 should be tagged on the task")
 	}
 
 	// Test with multiple tags
@@ -710,6 +930,45 @@ func TestE2E_Tasks_AddMITREAttack(t *testing.T) {
 	t.Log("=== ✓ AddMITREAttackToTask validation passed ===")
 }
 
+// TestE2E_Tasks_RemoveMITREAttack validates removing MITRE ATT&CK tags from tasks.
+func TestE2E_Tasks_RemoveMITREAttack(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: RemoveMITREAttackFromTask ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	if err := client.AddMITREAttackToTask(ctx, task.DisplayID, "T1033"); err != nil {
+		t.Skipf("⚠ AddMITREAttackToTask failed, MITRE database may not be populated: %v", err)
+	}
+
+	err = client.RemoveMITREAttackFromTask(ctx, task.DisplayID, "T1033")
+	require.NoError(t, err, "RemoveMITREAttackFromTask should succeed for an existing tag")
+	t.Log("✓ MITRE ATT&CK tag removed")
+
+	techniques, err := client.GetMITREAttackForTask(ctx, task.DisplayID)
+	require.NoError(t, err, "GetMITREAttackForTask should succeed")
+	for _, tech := range techniques {
+		assert.NotEqual(t, "T1033", tech.TNum, "Removed tag should no longer be listed")
+	}
+
+	err = client.RemoveMITREAttackFromTask(ctx, task.DisplayID, "T1033")
+	require.Error(t, err, "Removing the same tag twice should fail")
+	assert.ErrorIs(t, err, mythic.ErrNotFound, "Error should be ErrNotFound")
+	t.Log("✓ Removing a missing tag returns ErrNotFound")
+
+	t.Log("=== ✓ RemoveMITREAttackFromTask validation passed ===")
+}
+
 // TestE2E_Tasks_GetTaskArtifacts validates task artifact tracking.
 func TestE2E_Tasks_GetTaskArtifacts(t *testing.T) {
 	// Ensure at least one callback exists (reuses existing or creates one)
@@ -794,6 +1053,595 @@ func TestE2E_Tasks_WaitForTaskComplete_Error(t *testing.T) {
 	t.Log("=== ✓ WaitForTaskComplete error handling passed ===")
 }
 
+// TestE2E_Tasks_IssueTaskAndWait validates that IssueTaskAndWait returns the
+// final task and its output in a single call.
+func TestE2E_Tasks_IssueTaskAndWait(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: IssueTaskAndWait ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	taskReq := &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	}
+
+	task, responses, err := client.IssueTaskAndWait(ctx, taskReq, 60)
+	if err != nil {
+		// Slow agents may not finish in time; partial results must still be returned
+		t.Logf("⚠ IssueTaskAndWait returned error: %v", err)
+		require.NotNil(t, task, "Task should be returned even when the wait fails")
+		return
+	}
+
+	require.NotNil(t, task, "Task should not be nil")
+	assert.True(t, task.Completed, "Task should be completed")
+	assert.NotEmpty(t, responses, "Completed task should have responses")
+	for _, r := range responses {
+		assert.Equal(t, task.ID, r.TaskID, "Response should belong to the issued task")
+	}
+
+	t.Logf("✓ Task %d completed with %d responses", task.DisplayID, len(responses))
+	t.Log("=== ✓ IssueTaskAndWait validation passed ===")
+}
+
+// TestE2E_Tasks_GetTaskByAgentTaskID validates lookup by the agent-facing
+// task UUID.
+func TestE2E_Tasks_GetTaskByAgentTaskID(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: GetTaskByAgentTaskID ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+	require.NotEmpty(t, task.AgentTaskID, "Issued task should have an agent_task_id")
+
+	found, err := client.GetTaskByAgentTaskID(ctx, task.AgentTaskID)
+	require.NoError(t, err, "GetTaskByAgentTaskID should succeed")
+	assert.Equal(t, task.DisplayID, found.DisplayID, "Should find the issued task")
+
+	_, err = client.GetTaskByAgentTaskID(ctx, "00000000-0000-0000-0000-000000000000")
+	assert.True(t, errors.Is(err, mythic.ErrNotFound), "Unknown agent_task_id should return ErrNotFound")
+
+	t.Logf("✓ Found task %d by agent_task_id %s", found.DisplayID, found.AgentTaskID)
+	t.Log("=== ✓ GetTaskByAgentTaskID validation passed ===")
+}
+
+// TestE2E_Tasks_GetTasksByToken validates that tasks issued under a token
+// report that token and are found by GetTasksByToken.
+func TestE2E_Tasks_GetTasksByToken(t *testing.T) {
+	client := AuthenticateTestClient(t)
+
+	t.Log("=== Test: GetTasksByToken ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	_, err := client.GetTasksByToken(ctx, 0)
+	require.Error(t, err, "Zero token ID should be rejected")
+
+	tokens, err := client.GetTokens(ctx)
+	require.NoError(t, err, "GetTokens should succeed")
+	if len(tokens) == 0 {
+		t.Skip("⚠ No tokens available to query tasks for")
+	}
+
+	tasks, err := client.GetTasksByToken(ctx, tokens[0].ID)
+	require.NoError(t, err, "GetTasksByToken should succeed")
+	for _, task := range tasks {
+		require.NotNil(t, task.TokenID, "Task %d should report its token", task.DisplayID)
+		assert.Equal(t, tokens[0].ID, *task.TokenID, "Task should have run under the queried token")
+	}
+
+	t.Logf("✓ Found %d task(s) for token %d", len(tasks), tokens[0].ID)
+	t.Log("=== ✓ GetTasksByToken validation passed ===")
+}
+
+// TestE2E_Tasks_GetTasksByDisplayIDs validates batch task lookup keyed by
+// display ID, with missing IDs omitted.
+func TestE2E_Tasks_GetTasksByDisplayIDs(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: GetTasksByDisplayIDs ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	missingID := 999999999
+	tasks, err := client.GetTasksByDisplayIDs(ctx, []int{task.DisplayID, missingID})
+	require.NoError(t, err, "GetTasksByDisplayIDs should succeed")
+
+	found, ok := tasks[task.DisplayID]
+	require.True(t, ok, "Issued task should be in the result")
+	assert.Equal(t, task.ID, found.ID, "Task ID should match")
+	assert.Equal(t, task.CommandName, found.CommandName, "Command should match")
+	assert.NotContains(t, tasks, missingID, "Missing display ID should be absent")
+
+	_, err = client.GetTasksByDisplayIDs(ctx, nil)
+	require.Error(t, err, "Empty ID list should be rejected")
+
+	t.Logf("✓ Batch lookup returned %d task(s)", len(tasks))
+	t.Log("=== ✓ GetTasksByDisplayIDs validation passed ===")
+}
+
+// TestE2E_Tasks_WaitForTaskCompleteWithConfig validates waiting on a task
+// with a custom backoff configuration and via the subscription-based wait.
+func TestE2E_Tasks_WaitForTaskCompleteWithConfig(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: WaitForTaskCompleteWithConfig ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	err = client.WaitForTaskCompleteWithConfig(ctx, task.DisplayID, &mythic.PollConfig{
+		InitialInterval: 250 * time.Millisecond,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+		Timeout:         60 * time.Second,
+	})
+	if err != nil {
+		t.Logf("⚠ Task did not complete: %v", err)
+		return
+	}
+
+	completed, err := client.GetTask(ctx, task.DisplayID)
+	require.NoError(t, err, "GetTask should succeed")
+	assert.True(t, completed.Completed, "Task should be completed")
+
+	t.Logf("✓ Task %d completed with backoff polling", task.DisplayID)
+
+	subTask, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	err = client.WaitForTaskCompleteWithConfig(ctx, subTask.DisplayID, &mythic.PollConfig{
+		Timeout:         60 * time.Second,
+		UseSubscription: true,
+	})
+	if err != nil {
+		t.Logf("⚠ Task did not complete via subscription: %v", err)
+		return
+	}
+
+	completed, err = client.GetTask(ctx, subTask.DisplayID)
+	require.NoError(t, err, "GetTask should succeed")
+	assert.True(t, completed.Completed, "Task should be completed")
+
+	t.Logf("✓ Task %d completed via subscription wait", subTask.DisplayID)
+
+	quickTask, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	start := time.Now()
+	if err := client.WaitForTaskCompleteSubscription(ctx, quickTask.DisplayID, 60); err != nil {
+		t.Logf("⚠ Task did not complete via WaitForTaskCompleteSubscription: %v", err)
+		return
+	}
+	t.Logf("✓ Task %d completed via WaitForTaskCompleteSubscription in %s", quickTask.DisplayID, time.Since(start))
+	t.Log("=== ✓ WaitForTaskCompleteWithConfig validation passed ===")
+}
+
+// TestE2E_Tasks_WaitForTaskResult validates that WaitForTaskResult returns the
+// final task and its output for an already issued task.
+func TestE2E_Tasks_WaitForTaskResult(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: WaitForTaskResult ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	issued, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	task, responses, err := client.WaitForTaskResult(ctx, issued.DisplayID, 60)
+	if err != nil {
+		// Slow agents may not finish in time; partial results must still be returned
+		t.Logf("⚠ WaitForTaskResult returned error: %v", err)
+		require.NotNil(t, task, "Task should be returned even when the wait fails")
+		return
+	}
+
+	require.NotNil(t, task, "Task should not be nil")
+	assert.Equal(t, issued.DisplayID, task.DisplayID, "Should return the awaited task")
+	assert.True(t, task.Completed, "Task should be completed")
+	for _, r := range responses {
+		assert.Equal(t, task.ID, r.TaskID, "Response should belong to the awaited task")
+	}
+
+	t.Logf("✓ Task %d completed with %d responses", task.DisplayID, len(responses))
+	t.Log("=== ✓ WaitForTaskResult validation passed ===")
+}
+
+// TestE2E_Tasks_InternalIDLookups validates the task helpers that take the
+// internal task ID returned by IssueTask instead of a display ID.
+func TestE2E_Tasks_InternalIDLookups(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: Internal ID lookups ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	issued, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	task, err := client.GetTaskByInternalID(ctx, issued.ID)
+	require.NoError(t, err, "GetTaskByInternalID should succeed")
+	assert.Equal(t, issued.DisplayID, task.DisplayID, "Should return the issued task")
+	t.Logf("✓ Task %d resolved by internal ID %d", task.DisplayID, task.ID)
+
+	if err := client.WaitForTaskComplete(ctx, issued.DisplayID, 60); err != nil {
+		t.Logf("⚠ Task did not complete: %v", err)
+	}
+
+	byInternal, err := client.GetTaskOutputByInternalID(ctx, issued.ID)
+	require.NoError(t, err, "GetTaskOutputByInternalID should succeed")
+	byDisplay, err := client.GetTaskOutput(ctx, issued.DisplayID)
+	require.NoError(t, err, "GetTaskOutput should succeed")
+	assert.GreaterOrEqual(t, len(byDisplay), len(byInternal), "Output by display ID should include output by internal ID")
+	t.Logf("✓ %d responses by internal ID", len(byInternal))
+
+	comment := "internal id lookup " + time.Now().Format("15:04:05")
+	err = client.UpdateTaskByInternalID(ctx, issued.ID, map[string]interface{}{"comment": comment})
+	require.NoError(t, err, "UpdateTaskByInternalID should succeed")
+
+	updated, err := client.GetTask(ctx, issued.DisplayID)
+	require.NoError(t, err, "GetTask should succeed")
+	assert.Equal(t, comment, updated.Comment, "Comment should be updated")
+
+	t.Log("=== ✓ Internal ID lookup validation passed ===")
+}
+
+// TestE2E_Tasks_WaitForTaskOutput validates that WaitForTaskOutput returns the
+// task's responses once it completes.
+func TestE2E_Tasks_WaitForTaskOutput(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: WaitForTaskOutput ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	responses, err := client.WaitForTaskOutput(ctx, task.DisplayID, 60)
+	if err != nil {
+		t.Logf("⚠ WaitForTaskOutput returned error with %d partial responses: %v", len(responses), err)
+		return
+	}
+
+	assert.NotEmpty(t, responses, "Completed task should have responses")
+	for _, r := range responses {
+		assert.Equal(t, task.ID, r.TaskID, "Response should belong to the awaited task")
+	}
+
+	t.Logf("✓ Task %d returned %d responses", task.DisplayID, len(responses))
+	t.Log("=== ✓ WaitForTaskOutput validation passed ===")
+}
+
+// TestE2E_Tasks_IssueTaskBulk validates per-callback results when one of the
+// target callbacks does not exist.
+func TestE2E_Tasks_IssueTaskBulk(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: IssueTaskBulk with one invalid callback ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	taskReq := &mythic.TaskRequest{
+		Command:     "shell",
+		Params:      "whoami",
+		CallbackIDs: []int{callback.DisplayID, 999999},
+	}
+
+	tasks, errs, err := client.IssueTaskBulk(ctx, taskReq)
+	require.NoError(t, err, "IssueTaskBulk should succeed when at least one callback is tasked")
+	require.Len(t, tasks, 2, "Tasks should be parallel to CallbackIDs")
+	require.Len(t, errs, 2, "Errors should be parallel to CallbackIDs")
+
+	assert.NotNil(t, tasks[0], "Valid callback should be tasked")
+	assert.NoError(t, errs[0], "Valid callback should not report an error")
+	assert.Nil(t, tasks[1], "Invalid callback should not return a task")
+	assert.Error(t, errs[1], "Invalid callback should report an error")
+
+	t.Log("=== ✓ IssueTaskBulk validation passed ===")
+}
+
+// TestE2E_Tasks_GetTaskTree validates that GetTaskTree returns a tree rooted
+// at the requested task.
+func TestE2E_Tasks_GetTaskTree(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: GetTaskTree ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	tree, err := client.GetTaskTree(ctx, task.DisplayID)
+	require.NoError(t, err, "GetTaskTree should succeed")
+	require.NotNil(t, tree, "Tree should not be nil")
+	assert.Equal(t, task.ID, tree.ID, "Tree should be rooted at the requested task")
+	for _, child := range tree.Children {
+		require.NotNil(t, child.ParentTaskID, "Child should reference its parent")
+		assert.Equal(t, tree.ID, *child.ParentTaskID, "Child should belong to the root task")
+	}
+
+	subtasks, err := client.GetSubtasks(ctx, task.ID)
+	require.NoError(t, err, "GetSubtasks should succeed")
+	assert.Len(t, subtasks, len(tree.Children), "GetSubtasks should match the tree's direct children")
+
+	byDisplayID, err := client.GetSubtasksByDisplayID(ctx, task.DisplayID)
+	require.NoError(t, err, "GetSubtasksByDisplayID should succeed")
+	assert.Len(t, byDisplayID, len(subtasks), "GetSubtasksByDisplayID should match GetSubtasks")
+
+	shallow, err := client.GetTaskTreeWithDepth(ctx, task.DisplayID, 0)
+	require.NoError(t, err, "GetTaskTreeWithDepth should succeed")
+	assert.Empty(t, shallow.Children, "Depth 0 should return only the root")
+
+	t.Logf("✓ Task %d has %d direct subtasks", tree.DisplayID, len(tree.Children))
+	t.Log("=== ✓ GetTaskTree validation passed ===")
+}
+
+// TestE2E_Tasks_StreamTaskOutput validates that streamed responses are
+// delivered once each, in order, until the task completes.
+func TestE2E_Tasks_StreamTaskOutput(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: StreamTaskOutput ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	responses, errs := client.StreamTaskOutput(ctx, task.DisplayID)
+
+	seen := make(map[int]bool)
+	lastID := 0
+	for r := range responses {
+		assert.False(t, seen[r.ID], "Response %d should only be delivered once", r.ID)
+		assert.Greater(t, r.ID, lastID, "Responses should arrive in ascending ID order")
+		seen[r.ID] = true
+		lastID = r.ID
+	}
+	for err := range errs {
+		require.NoError(t, err, "StreamTaskOutput should not report an error")
+	}
+
+	t.Logf("✓ Streamed %d responses for task %d", len(seen), task.DisplayID)
+	t.Log("=== ✓ StreamTaskOutput validation passed ===")
+}
+
+// TestE2E_Tasks_GetTaskOutputStream validates that subscription-streamed
+// responses are delivered once each, and that existing output is only
+// replayed when requested.
+func TestE2E_Tasks_GetTaskOutputStream(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: GetTaskOutputStream ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	responses, errs := client.GetTaskOutputStream(ctx, task.DisplayID, false)
+
+	seen := make(map[int]bool)
+	for r := range responses {
+		assert.False(t, seen[r.ID], "Response %d should only be delivered once", r.ID)
+		assert.Equal(t, task.ID, r.TaskID, "Response should belong to the streamed task")
+		seen[r.ID] = true
+	}
+	for err := range errs {
+		require.NoError(t, err, "GetTaskOutputStream should not report an error")
+	}
+	t.Logf("✓ Streamed %d responses for task %d", len(seen), task.DisplayID)
+
+	// The task is complete now, so only a replay yields its output
+	existing, err := client.GetTaskOutput(ctx, task.DisplayID)
+	require.NoError(t, err, "GetTaskOutput should succeed")
+
+	replayed := 0
+	responses, errs = client.GetTaskOutputStream(ctx, task.DisplayID, true)
+	for range responses {
+		replayed++
+	}
+	for err := range errs {
+		require.NoError(t, err, "GetTaskOutputStream replay should not report an error")
+	}
+	assert.Equal(t, len(existing), replayed, "Replay should include all existing responses")
+
+	t.Logf("✓ Replayed %d existing responses", replayed)
+	t.Log("=== ✓ GetTaskOutputStream validation passed ===")
+}
+
+// TestE2E_Tasks_GetTaskExpanded validates that GetTaskExpanded returns the
+// task together with its callback and operator in one call.
+func TestE2E_Tasks_GetTaskExpanded(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: GetTaskExpanded ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	})
+	require.NoError(t, err, "IssueTask should succeed")
+
+	expanded, err := client.GetTaskExpanded(ctx, task.DisplayID, nil)
+	require.NoError(t, err, "GetTaskExpanded should succeed")
+	require.NotNil(t, expanded.Task, "Expanded task should embed the task")
+	assert.Equal(t, task.ID, expanded.ID, "Task ID should match")
+	require.NotNil(t, expanded.Callback, "Callback should be included by default")
+	assert.Equal(t, callback.DisplayID, expanded.Callback.DisplayID, "Callback should match the tasked callback")
+	assert.NotEmpty(t, expanded.OperatorUsername, "Operator username should be included by default")
+	t.Logf("✓ Task %d on %s issued by %s", expanded.DisplayID, expanded.Callback.Host, expanded.OperatorUsername)
+
+	minimal, err := client.GetTaskExpanded(ctx, task.DisplayID, &mythic.TaskExpandOptions{})
+	require.NoError(t, err, "GetTaskExpanded with no includes should succeed")
+	assert.Nil(t, minimal.Callback, "Callback should be omitted when not requested")
+	assert.Empty(t, minimal.OperatorUsername, "Operator should be omitted when not requested")
+	assert.Nil(t, minimal.Token, "Token should be omitted when not requested")
+
+	t.Log("=== ✓ GetTaskExpanded validation passed ===")
+}
+
+// TestE2E_Tasks_GetTaskOutputSince validates that only responses after the
+// given response ID are returned.
+func TestE2E_Tasks_GetTaskOutputSince(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: GetTaskOutputSince ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
+
+	task, _, err := client.IssueTaskAndWait(ctx, &mythic.TaskRequest{
+		Command:    "shell",
+		Params:     "whoami",
+		CallbackID: &callback.DisplayID,
+	}, 60)
+	require.NoError(t, err, "IssueTaskAndWait should succeed")
+
+	all, err := client.GetTaskOutputSince(ctx, task.DisplayID, 0)
+	require.NoError(t, err, "GetTaskOutputSince from 0 should succeed")
+
+	full, err := client.GetTaskOutput(ctx, task.DisplayID)
+	require.NoError(t, err, "GetTaskOutput should succeed")
+	assert.Equal(t, len(full), len(all), "Output since 0 should match the full output")
+
+	if len(all) == 0 {
+		t.Log("⚠ Task produced no output, skipping cursor check")
+		return
+	}
+
+	lastID := all[len(all)-1].ID
+	newer, err := client.GetTaskOutputSince(ctx, task.DisplayID, lastID)
+	require.NoError(t, err, "GetTaskOutputSince from last ID should succeed")
+	assert.Empty(t, newer, "No responses should follow the last response")
+
+	t.Logf("✓ %d responses, none after ID %d", len(all), lastID)
+	t.Log("=== ✓ GetTaskOutputSince validation passed ===")
+}
+
+// TestE2E_Tasks_IssueTaskByAgentCallbackID validates tasking a callback by its
+// agent_callback_id instead of its display ID.
+func TestE2E_Tasks_IssueTaskByAgentCallbackID(t *testing.T) {
+	client := AuthenticateTestClient(t)
+	callback := getActiveCallback(t, client)
+
+	t.Log("=== Test: IssueTask with AgentCallbackID ===")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resolved, err := client.GetCallbackByAgentID(ctx, callback.AgentCallbackID)
+	require.NoError(t, err, "GetCallbackByAgentID should succeed")
+	assert.Equal(t, callback.DisplayID, resolved.DisplayID, "Agent ID should resolve to the same callback")
+
+	task, err := client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:         "shell",
+		Params:          "whoami",
+		AgentCallbackID: callback.AgentCallbackID,
+	})
+	require.NoError(t, err, "IssueTask with AgentCallbackID should succeed")
+	assert.Equal(t, callback.ID, task.CallbackID, "Task should be issued to the resolved callback")
+	t.Logf("✓ Task %d issued via agent callback ID %s", task.DisplayID, callback.AgentCallbackID)
+
+	_, err = client.IssueTask(ctx, &mythic.TaskRequest{
+		Command:         "shell",
+		Params:          "whoami",
+		AgentCallbackID: "00000000-0000-0000-0000-000000000000",
+	})
+	require.Error(t, err, "Unknown agent callback ID should be rejected")
+	assert.ErrorIs(t, err, mythic.ErrInvalidInput, "Error should be ErrInvalidInput")
+	t.Log("✓ Unknown agent callback ID rejected")
+
+	t.Log("=== ✓ IssueTask with AgentCallbackID validation passed ===")
+}
+
 // TestE2E_Tasks_Comprehensive_Summary provides a summary of all task test coverage.
 func TestE2E_Tasks_Comprehensive_Summary(t *testing.T) {
 	t.Log("=== Task Comprehensive Test Coverage Summary ===")
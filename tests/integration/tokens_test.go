@@ -91,7 +91,7 @@ func TestE2E_TokenRetrieval(t *testing.T) {
 }
 
 // TestE2E_CallbackTokens tests callback token operations.
-// Covers: GetCallbackTokens, GetCallbackTokensByCallback
+// Covers: GetCallbackTokens, GetCallbackTokensByCallback, GetTokensByCallback
 func TestE2E_CallbackTokens(t *testing.T) {
 	client := AuthenticateTestClient(t)
 
@@ -143,6 +143,27 @@ func TestE2E_CallbackTokens(t *testing.T) {
 				t.Error("CallbackToken has wrong CallbackID")
 			}
 		}
+
+		// Test 3: Resolve the tokens themselves for the same callback
+		t.Log("=== Test 3: Get tokens for specific callback ===")
+		tokens, err := client.GetTokensByCallback(ctx2, testCallbackID)
+		if err != nil {
+			t.Fatalf("GetTokensByCallback failed: %v", err)
+		}
+		t.Logf("✓ Retrieved %d tokens usable by callback %d", len(tokens), testCallbackID)
+
+		linked := make(map[int]bool, len(tokens))
+		for _, tok := range tokens {
+			if tok.Deleted {
+				t.Errorf("GetTokensByCallback returned deleted token %d", tok.ID)
+			}
+			linked[tok.ID] = true
+		}
+		for _, ct := range cbTokens {
+			if tok, err := client.GetTokenByID(ctx2, ct.TokenID); err == nil && !tok.Deleted && !linked[ct.TokenID] {
+				t.Errorf("Token %d is linked to callback %d but missing from GetTokensByCallback", ct.TokenID, testCallbackID)
+			}
+		}
 	}
 
 	t.Log("=== ✓ Callback token tests passed ===")
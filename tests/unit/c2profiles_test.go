@@ -1,10 +1,16 @@
 package unit
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -463,3 +469,108 @@ func TestBuildParameterTypeNewFields(t *testing.T) {
 	// param.CreationTime - removed (field never existed in GraphQL schema)
 	// param.ParameterGroupName - renamed to GroupName (matching GraphQL group_name)
 }
+
+// TestGetC2ProfileParameters_Decode tests that parameters with string,
+// dictionary and null defaults decode from the nested profile selection.
+func TestGetC2ProfileParameters_Decode(t *testing.T) {
+	var gotQuery string
+	found := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotQuery = req.Query
+
+		if !found {
+			_, _ = w.Write([]byte(`{"data":{"c2profile_by_pk":null}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"c2profile_by_pk":{"id":2,"c2profileparameters":[
+			{"id":10,"name":"callback_host","parameter_type":"String","required":true,"default_value":"https://domain.com","choices":[]},
+			{"id":11,"name":"headers","parameter_type":"Dictionary","default_value":{"User-Agent":"Mozilla/5.0"},"choices":[{"name":"User-Agent","default_value":"Mozilla/5.0"}]},
+			{"id":12,"name":"encrypted_exchange_check","parameter_type":"ChooseOne","default_value":null,"choices":["T","F"]}
+		]}}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	params, err := client.GetC2ProfileParameters(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("GetC2ProfileParameters() error = %v", err)
+	}
+	if !strings.Contains(gotQuery, "c2profile_by_pk(id: $profile_id){id,c2profileparameters(") {
+		t.Errorf("Expected parameters nested under the profile, got %q", gotQuery)
+	}
+	if len(params) != 3 {
+		t.Fatalf("Expected 3 parameters, got %d", len(params))
+	}
+
+	want := []struct {
+		name, defaultValue, choices string
+	}{
+		{"callback_host", "https://domain.com", `[]`},
+		{"headers", `{"User-Agent":"Mozilla/5.0"}`, `[{"name":"User-Agent","default_value":"Mozilla/5.0"}]`},
+		{"encrypted_exchange_check", "", `["T","F"]`},
+	}
+	for i, w := range want {
+		p := params[i]
+		if p.Name != w.name || p.DefaultValue != w.defaultValue || p.Choices != w.choices || p.C2ProfileID != 2 {
+			t.Errorf("params[%d] = {%s %q %s profile %d}, want {%s %q %s profile 2}",
+				i, p.Name, p.DefaultValue, p.Choices, p.C2ProfileID, w.name, w.defaultValue, w.choices)
+		}
+	}
+
+	found = false
+	if _, err := client.GetC2ProfileParameters(context.Background(), 99); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("GetC2ProfileParameters() error = %v, want ErrNotFound", err)
+	}
+}
+
+// TestGetC2ProfileByName tests name lookup, including container status.
+func TestGetC2ProfileByName(t *testing.T) {
+	var gotVars map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotVars = req.Variables
+
+		if req.Variables["name"] != "http" {
+			_, _ = w.Write([]byte(`{"data":{"c2profile":[]}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"c2profile":[{"id":2,"name":"http","running":true,"container_running":true,"is_p2p":false}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	profile, err := client.GetC2ProfileByName(context.Background(), "http")
+	if err != nil {
+		t.Fatalf("GetC2ProfileByName() error = %v", err)
+	}
+	if profile.ID != 2 || !profile.Running || !profile.ContainerRunning {
+		t.Errorf("GetC2ProfileByName() = %+v, want running http profile 2 with its container up", profile)
+	}
+	if gotVars["name"] != "http" {
+		t.Errorf("Expected name variable %q, got %v", "http", gotVars)
+	}
+
+	if _, err := client.GetC2ProfileByName(context.Background(), "smb"); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("GetC2ProfileByName() error = %v, want ErrNotFound", err)
+	}
+	if _, err := client.GetC2ProfileByName(context.Background(), ""); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetC2ProfileByName() error = %v, want ErrInvalidInput", err)
+	}
+}
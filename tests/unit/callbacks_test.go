@@ -1,6 +1,14 @@
 package unit
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -169,6 +177,152 @@ func TestCallbackUpdateRequest(t *testing.T) {
 	}
 }
 
+func TestUpdateCallback_OmitsUnsetFields(t *testing.T) {
+	var received map[string]map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "fake-access-token",
+				"refresh_token": "fake-refresh-token",
+				"user":          map[string]interface{}{"id": 1, "username": "operator1", "current_operation_id": 1},
+			})
+		case "/api/v1.4/update_callback_webhook":
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				http.Error(w, "bad json", http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+		case "/graphql/":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"callback": []map[string]interface{}{
+						{"id": 50, "display_id": 5, "description": "updated", "locked": true},
+					},
+				},
+			})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	description := "updated"
+	locked := true
+	err = client.UpdateCallback(context.Background(), &types.CallbackUpdateRequest{
+		CallbackDisplayID: 5,
+		Description:       &description,
+		Locked:            &locked,
+	})
+	if err != nil {
+		t.Fatalf("UpdateCallback() failed: %v", err)
+	}
+
+	input := received["input"]
+	if input == nil {
+		t.Fatal("Expected request body to contain input")
+	}
+	if input["callback_display_id"] != float64(5) {
+		t.Errorf("callback_display_id = %v, want 5", input["callback_display_id"])
+	}
+	if input["description"] != description {
+		t.Errorf("description = %v, want %q", input["description"], description)
+	}
+	if input["locked"] != true {
+		t.Errorf("locked = %v, want true", input["locked"])
+	}
+	if len(input) != 3 {
+		t.Errorf("Expected only set fields to be sent, got %v", input)
+	}
+}
+
+func TestUpdateCallback_VerifiesResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    string
+		callbacks []map[string]interface{}
+		wantErr   error
+	}{
+		{
+			name:      "applied",
+			status:    "success",
+			callbacks: []map[string]interface{}{{"id": 50, "display_id": 5, "sleep_info": "10s"}},
+		},
+		{
+			name:      "not persisted",
+			status:    "success",
+			callbacks: []map[string]interface{}{{"id": 50, "display_id": 5, "sleep_info": "60s"}},
+			wantErr:   mythic.ErrOperationFailed,
+		},
+		{
+			name:      "missing callback",
+			status:    "error",
+			callbacks: []map[string]interface{}{},
+			wantErr:   mythic.ErrNotFound,
+		},
+		{
+			name:      "rejected update",
+			status:    "error",
+			callbacks: []map[string]interface{}{{"id": 50, "display_id": 5}},
+			wantErr:   mythic.ErrOperationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v1.4/update_callback_webhook":
+					json.NewEncoder(w).Encode(map[string]string{"status": tt.status, "error": "failed to update"})
+				case "/graphql/":
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"data": map[string]interface{}{"callback": tt.callbacks},
+					})
+				default:
+					http.Error(w, "not found", http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			client, err := mythic.NewClient(&mythic.Config{
+				ServerURL: srv.URL,
+				APIToken:  "test-token",
+				SSL:       false,
+			})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer client.Close()
+
+			sleep := "10s"
+			err = client.UpdateCallback(context.Background(), &types.CallbackUpdateRequest{
+				CallbackDisplayID: 5,
+				SleepInfo:         &sleep,
+			})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("UpdateCallback() failed: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("UpdateCallback() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestCallbackTypes(t *testing.T) {
 	// Test that all callback-related types can be created
 	now := time.Now()
@@ -344,3 +498,478 @@ func TestCreateCallbackInput_MinimalRequired(t *testing.T) {
 		t.Error("Expected Host to be nil")
 	}
 }
+
+func TestCallbackGraphEdge_IsActive(t *testing.T) {
+	ended := time.Now()
+
+	active := &types.CallbackGraphEdge{ID: 1, SourceID: 1, DestinationID: 2, C2ProfileName: "smb"}
+	if !active.IsActive() {
+		t.Error("Edge without EndTimestamp should be active")
+	}
+
+	removed := &types.CallbackGraphEdge{ID: 2, SourceID: 1, DestinationID: 2, C2ProfileName: "smb", EndTimestamp: &ended}
+	if removed.IsActive() {
+		t.Error("Edge with EndTimestamp should not be active")
+	}
+}
+
+func TestCallbackCheckinAge(t *testing.T) {
+	cb := &types.Callback{LastCheckin: time.Now().Add(-10 * time.Minute)}
+	age := cb.CheckinAge()
+	if age < 10*time.Minute || age > 11*time.Minute {
+		t.Errorf("CheckinAge() = %s, want about 10m", age)
+	}
+
+	unknown := &types.Callback{}
+	if got := unknown.CheckinAge(); got != 0 {
+		t.Errorf("CheckinAge() with no last checkin = %s, want 0", got)
+	}
+}
+
+func TestCallbackCheckinStaleness(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		lastCheckin time.Time
+		wantSince   time.Duration
+		wantBucket  types.CheckinBucket
+	}{
+		{"never", time.Time{}, 0, types.CheckinNever},
+		{"recent", now.Add(-time.Minute), time.Minute, types.CheckinRecent},
+		{"within day", now.Add(-2 * time.Hour), 2 * time.Hour, types.CheckinWithinDay},
+		{"stale", now.Add(-48 * time.Hour), 48 * time.Hour, types.CheckinStale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := &types.Callback{LastCheckin: tt.lastCheckin}
+			if got := cb.TimeSinceCheckin(now); got != tt.wantSince {
+				t.Errorf("TimeSinceCheckin() = %s, want %s", got, tt.wantSince)
+			}
+			if got := cb.CheckinBucket(now); got != tt.wantBucket {
+				t.Errorf("CheckinBucket() = %q, want %q", got, tt.wantBucket)
+			}
+		})
+	}
+
+	if !(&types.Callback{}).IsStale(time.Hour) {
+		t.Error("Callback that never checked in should be stale")
+	}
+	fresh := &types.Callback{LastCheckin: time.Now().Add(-time.Minute)}
+	if fresh.IsStale(time.Hour) {
+		t.Error("Callback that checked in a minute ago should not be stale for a 1h threshold")
+	}
+	if !fresh.IsStale(time.Second) {
+		t.Error("Callback that checked in a minute ago should be stale for a 1s threshold")
+	}
+}
+
+func TestRemoveCallbackGraphEdgeByEndpoints(t *testing.T) {
+	edge := func(id, source, dest int, profile string, ended bool) map[string]interface{} {
+		e := map[string]interface{}{
+			"id":              id,
+			"start_timestamp": "2024-01-01T00:00:00Z",
+			"end_timestamp":   nil,
+			"source":          map[string]interface{}{"display_id": source},
+			"destination":     map[string]interface{}{"display_id": dest},
+			"c2profile":       map[string]interface{}{"name": profile},
+		}
+		if ended {
+			e["end_timestamp"] = "2024-01-02T00:00:00Z"
+		}
+		return e
+	}
+
+	tests := []struct {
+		name        string
+		edges       []map[string]interface{}
+		wantRemoved int
+		wantErr     error
+		wantInError string
+	}{
+		{
+			name: "single match",
+			edges: []map[string]interface{}{
+				edge(7, 1, 2, "smb", true),
+				edge(8, 1, 2, "smb", false),
+				edge(9, 1, 2, "tcp", false),
+				edge(10, 3, 1, "smb", false),
+			},
+			wantRemoved: 8,
+		},
+		{
+			name:    "no active match",
+			edges:   []map[string]interface{}{edge(7, 1, 2, "smb", true)},
+			wantErr: mythic.ErrNotFound,
+		},
+		{
+			name: "ambiguous",
+			edges: []map[string]interface{}{
+				edge(11, 1, 2, "smb", false),
+				edge(12, 1, 2, "smb", false),
+			},
+			wantErr:     mythic.ErrInvalidInput,
+			wantInError: "[11 12]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			removed := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Query     string                 `json:"query"`
+					Variables map[string]interface{} `json:"variables"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					http.Error(w, "bad json", http.StatusBadRequest)
+					return
+				}
+				if strings.Contains(body.Query, "callbackgraphedge_remove") {
+					removed = int(body.Variables["edge_id"].(float64))
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"data": map[string]interface{}{
+							"callbackgraphedge_remove": map[string]interface{}{"status": "success", "error": ""},
+						},
+					})
+					return
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"data": map[string]interface{}{"callbackgraphedge": tt.edges},
+				})
+			}))
+			defer srv.Close()
+
+			client, err := mythic.NewClient(&mythic.Config{
+				ServerURL: srv.URL,
+				APIToken:  "test-token",
+				SSL:       false,
+			})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer client.Close()
+
+			err = client.RemoveCallbackGraphEdgeByEndpoints(context.Background(), 1, 2, "smb")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantInError != "" && !strings.Contains(err.Error(), tt.wantInError) {
+					t.Errorf("error %q should list candidates %s", err, tt.wantInError)
+				}
+				if removed != 0 {
+					t.Errorf("Expected no edge removal, removed %d", removed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RemoveCallbackGraphEdgeByEndpoints() failed: %v", err)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("removed edge %d, want %d", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestCallbackConfig_RoundTrip(t *testing.T) {
+	exported := `{"agent_callback_id":"abc-123","payload":{"uuid":"p-1"},"c2_profiles":[{"name":"http"}],"enc_key":"ZW5j","dec_key":null,"sleep_info":"10s"}`
+
+	var config types.CallbackConfig
+	if err := json.Unmarshal([]byte(exported), &config); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if config.AgentCallbackID != "abc-123" {
+		t.Errorf("AgentCallbackID = %q, want %q", config.AgentCallbackID, "abc-123")
+	}
+	if config.EncKey == nil || *config.EncKey != "ZW5j" {
+		t.Errorf("EncKey = %v, want ZW5j", config.EncKey)
+	}
+	if config.DecKey != nil {
+		t.Errorf("DecKey = %v, want nil", *config.DecKey)
+	}
+	if string(config.Extra["sleep_info"]) != `"10s"` {
+		t.Errorf("Extra[sleep_info] = %s, want \"10s\"", config.Extra["sleep_info"])
+	}
+
+	config.AgentCallbackID = "def-456"
+	marshaled, err := config.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var roundTrip map[string]interface{}
+	if err := json.Unmarshal([]byte(marshaled), &roundTrip); err != nil {
+		t.Fatalf("Marshal produced invalid JSON: %v", err)
+	}
+	if roundTrip["agent_callback_id"] != "def-456" {
+		t.Errorf("agent_callback_id = %v, want def-456", roundTrip["agent_callback_id"])
+	}
+	if roundTrip["sleep_info"] != "10s" {
+		t.Errorf("sleep_info = %v, want 10s (extra keys must be preserved)", roundTrip["sleep_info"])
+	}
+	profiles, ok := roundTrip["c2_profiles"].([]interface{})
+	if !ok || len(profiles) != 1 {
+		t.Errorf("c2_profiles = %v, want one profile", roundTrip["c2_profiles"])
+	}
+}
+
+func TestExportCallbackConfigParsed(t *testing.T) {
+	config := `{"agent_callback_id":"abc-123","payload":{"uuid":"p-1"}}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"exportCallbackConfig": map[string]interface{}{
+					"status":            "success",
+					"error":             "",
+					"agent_callback_id": "abc-123",
+					"config":            config,
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		APIToken:  "test-token",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	parsed, err := client.ExportCallbackConfigParsed(context.Background(), "abc-123")
+	if err != nil {
+		t.Fatalf("ExportCallbackConfigParsed() failed: %v", err)
+	}
+	if parsed.AgentCallbackID != "abc-123" {
+		t.Errorf("AgentCallbackID = %q, want %q", parsed.AgentCallbackID, "abc-123")
+	}
+	if string(parsed.Payload) != `{"uuid":"p-1"}` {
+		t.Errorf("Payload = %s, want {\"uuid\":\"p-1\"}", parsed.Payload)
+	}
+}
+
+func TestGetCallbacksPaginated(t *testing.T) {
+	var limits []float64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				Where map[string]map[string]float64 `json:"where"`
+				Limit float64                       `json:"limit"`
+			} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		limits = append(limits, req.Variables.Limit)
+
+		// Five callbacks with IDs 5..1, served newest first like the real query
+		var rows []map[string]interface{}
+		for id := 5; id >= 1 && len(rows) < int(req.Variables.Limit); id-- {
+			if after, ok := req.Variables.Where["id"]["_lt"]; ok && float64(id) >= after {
+				continue
+			}
+			rows = append(rows, map[string]interface{}{"id": id, "display_id": id})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"callback": rows}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	opts := &mythic.CallbackPageOptions{Limit: 2}
+	var pages [][]int
+	for {
+		page, err := client.GetCallbacksPaginated(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("GetCallbacksPaginated() error = %v", err)
+		}
+		if page.Count != len(page.Callbacks) {
+			t.Errorf("Count = %d, want %d", page.Count, len(page.Callbacks))
+		}
+		var ids []int
+		for _, cb := range page.Callbacks {
+			ids = append(ids, cb.ID)
+		}
+		pages = append(pages, ids)
+		if !page.HasMore {
+			if page.NextCursor != 0 {
+				t.Errorf("NextCursor = %d on last page, want 0", page.NextCursor)
+			}
+			break
+		}
+		opts.AfterID = page.NextCursor
+	}
+
+	if got, want := fmt.Sprint(pages), "[[5 4] [3 2] [1]]"; got != want {
+		t.Errorf("pages = %s, want %s", got, want)
+	}
+	if limits[0] != 3 {
+		t.Errorf("Expected limit+1 rows requested, got %v", limits[0])
+	}
+
+	all, err := client.GetAllCallbacks(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllCallbacks() error = %v", err)
+	}
+	if len(all) != 5 {
+		t.Errorf("GetAllCallbacks() returned %d callbacks, want 5", len(all))
+	}
+
+	if _, err := client.GetCallbacksPaginated(context.Background(), &mythic.CallbackPageOptions{AfterID: -1}); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetCallbacksPaginated() error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestGetCallbacksFiltered(t *testing.T) {
+	var gotWhere string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]json.RawMessage `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotWhere = string(req.Variables["where"])
+		_, _ = w.Write([]byte(`{"data":{"callback":[{"id":3,"display_id":3,"host":"WS01","active":false}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	host := "ws01"
+	inactive := false
+	integrity := 3
+	callbacks, err := client.GetCallbacksFiltered(context.Background(), &mythic.CallbackFilter{
+		Host:           &host,
+		Active:         &inactive,
+		IntegrityLevel: &integrity,
+		ActiveOnly:     true,
+	})
+	if err != nil {
+		t.Fatalf("GetCallbacksFiltered() error = %v", err)
+	}
+
+	want := `{"active":{"_eq":false},"host":{"_ilike":"ws01"},"integrity_level":{"_eq":3}}`
+	if gotWhere != want {
+		t.Errorf("where = %s, want %s", gotWhere, want)
+	}
+	if len(callbacks) != 1 || callbacks[0].Host != "WS01" {
+		t.Errorf("Unexpected callbacks %+v", callbacks)
+	}
+
+	if _, err := client.GetCallbacksFiltered(context.Background(), nil); err != nil {
+		t.Fatalf("GetCallbacksFiltered(nil) error = %v", err)
+	}
+	if gotWhere != `{}` {
+		t.Errorf("Expected empty where clause for nil filter, got %s", gotWhere)
+	}
+}
+
+func TestGetStaleCallbacks(t *testing.T) {
+	var where struct {
+		Active      map[string]bool   `json:"active"`
+		LastCheckin map[string]string `json:"last_checkin"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]json.RawMessage `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.Unmarshal(req.Variables["where"], &where)
+		_, _ = w.Write([]byte(`{"data":{"callback":[{"id":1,"display_id":1,"active":true}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetStaleCallbacks(context.Background(), 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for zero threshold, got %v", err)
+	}
+
+	before := time.Now().Add(-time.Hour)
+	callbacks, err := client.GetStaleCallbacks(context.Background(), time.Hour)
+	if err != nil {
+		t.Fatalf("GetStaleCallbacks() error = %v", err)
+	}
+	if len(callbacks) != 1 {
+		t.Errorf("Expected 1 callback, got %d", len(callbacks))
+	}
+	if !where.Active["_eq"] {
+		t.Errorf("Expected active filter, got %v", where.Active)
+	}
+
+	cutoff, err := time.Parse(time.RFC3339, where.LastCheckin["_lt"])
+	if err != nil {
+		t.Fatalf("Cutoff %q is not RFC3339: %v", where.LastCheckin["_lt"], err)
+	}
+	if cutoff.Location() != time.UTC {
+		t.Errorf("Expected UTC cutoff, got %q", where.LastCheckin["_lt"])
+	}
+	if d := cutoff.Sub(before); d < -time.Second || d > time.Minute {
+		t.Errorf("Cutoff %s is not about an hour ago", cutoff)
+	}
+}
+
+func TestWaitForCallback(t *testing.T) {
+	var gotWhere string
+	var rows atomic.Value
+	rows.Store(`[{"id":8,"display_id":4,"host":"WS02","active":true}]`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]json.RawMessage `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotWhere = string(req.Variables["where"])
+		_, _ = w.Write([]byte(`{"data":{"callback":` + rows.Load().(string) + `}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// An already-present match is returned without waiting
+	payloadType := "poseidon"
+	inactive := false
+	callback, err := client.WaitForCallback(context.Background(), 10, &mythic.CallbackFilter{
+		PayloadTypeName: &payloadType,
+		Active:          &inactive,
+	})
+	if err != nil {
+		t.Fatalf("WaitForCallback() error = %v", err)
+	}
+	if callback.DisplayID != 4 {
+		t.Errorf("Expected callback 4, got %+v", callback)
+	}
+	want := `{"active":{"_eq":true},"payload":{"payloadtype":{"name":{"_eq":"poseidon"}}}}`
+	if gotWhere != want {
+		t.Errorf("where = %s, want %s", gotWhere, want)
+	}
+
+	rows.Store(`[]`)
+	start := time.Now()
+	_, err = client.WaitForCallback(context.Background(), 1, nil)
+	if !errors.Is(err, mythic.ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 3*time.Second {
+		t.Errorf("Expected to give up after about 1s, took %v", elapsed)
+	}
+}
@@ -1,7 +1,19 @@
 package unit
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 )
@@ -164,3 +176,518 @@ func TestClientURLStripping(t *testing.T) {
 		})
 	}
 }
+
+// newFlakyGraphQLServer fails the first failures GraphQL requests with
+// status, then serves a single task.
+func newFlakyGraphQLServer(t *testing.T, failures int32, status int, calls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			http.Error(w, "unavailable", status)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"task": []map[string]interface{}{{"id": 42, "display_id": 7, "command_name": "shell"}},
+		}})
+	}))
+}
+
+func TestClientRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		retry     *mythic.RetryConfig
+		status    int
+		wantErr   bool
+		wantCalls int32
+	}{
+		{
+			name:      "disabled by default",
+			status:    http.StatusServiceUnavailable,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "retries 5xx responses",
+			retry:     &mythic.RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond},
+			status:    http.StatusServiceUnavailable,
+			wantErr:   false,
+			wantCalls: 3,
+		},
+		{
+			name:      "gives up after max retries",
+			retry:     &mythic.RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond},
+			status:    http.StatusBadGateway,
+			wantErr:   true,
+			wantCalls: 2,
+		},
+		{
+			name:      "retries 429 responses",
+			retry:     &mythic.RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond},
+			status:    http.StatusTooManyRequests,
+			wantErr:   false,
+			wantCalls: 3,
+		},
+		{
+			name:      "never retries 4xx responses",
+			retry:     &mythic.RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond},
+			status:    http.StatusBadRequest,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := newFlakyGraphQLServer(t, 2, tt.status, &calls)
+			defer srv.Close()
+
+			client, err := mythic.NewClient(&mythic.Config{
+				ServerURL: srv.URL,
+				APIToken:  "test-token",
+				SSL:       false,
+				Retry:     tt.retry,
+			})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			_, err = client.GetTask(context.Background(), 7)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("Expected %d requests, got %d", tt.wantCalls, got)
+			}
+			if err != nil && tt.wantCalls > 1 && !strings.Contains(err.Error(), fmt.Sprintf("failed after %d attempts", tt.wantCalls)) {
+				t.Errorf("Expected error to report %d attempts, got %v", tt.wantCalls, err)
+			}
+		})
+	}
+}
+
+func TestClientRetryMutations(t *testing.T) {
+	retry := &mythic.RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	// A 5xx response means the server may have applied the mutation
+	var calls int32
+	srv := newFlakyGraphQLServer(t, 2, http.StatusServiceUnavailable, &calls)
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false, Retry: retry})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := client.DeleteTag(context.Background(), 5); err == nil {
+		t.Error("Expected DeleteTag to fail on 5xx response")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected mutation to be sent once, got %d requests", got)
+	}
+
+	// A dial failure means the request was never sent, so it is retried
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	unreachable, err := mythic.NewClient(&mythic.Config{ServerURL: closedURL, APIToken: "test-token", SSL: false, Retry: retry})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer unreachable.Close()
+
+	err = unreachable.DeleteTag(context.Background(), 5)
+	if err == nil || !strings.Contains(err.Error(), "failed after 4 attempts") {
+		t.Errorf("Expected dial failure to be retried 3 times, got %v", err)
+	}
+}
+
+func TestClientValidationError(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"errors":[{"message":"field 'callback_id' not found in type: 'task'","extensions":{"path":"$.selectionSet.task.selectionSet.callback_id","code":"validation-failed"}}]}`)
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		APIToken:  "test-token",
+		SSL:       false,
+		Retry:     &mythic.RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	_, err = client.GetTask(context.Background(), 7)
+	if !mythic.IsValidationError(err) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "not found in type: 'task'") || !strings.Contains(err.Error(), "$.selectionSet.task.selectionSet.callback_id") {
+		t.Errorf("Expected error to keep the message and path, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("Expected validation errors not to be retried, got %d requests", got)
+	}
+
+	_, err = client.ExecuteRawGraphQL(context.Background(), "query { task { callback_id } }", nil)
+	if !mythic.IsValidationError(err) {
+		t.Errorf("Expected ExecuteRawGraphQL to return a validation error, got %v", err)
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	var calls int32
+	srv := newFlakyGraphQLServer(t, 0, http.StatusOK, &calls)
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL:         srv.URL,
+		APIToken:          "test-token",
+		SSL:               false,
+		RequestsPerSecond: 20,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// The first request is immediate, the next four are spaced 50ms apart
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := client.GetTask(context.Background(), 7); err != nil {
+			t.Fatalf("GetTask() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("Expected 5 requests at 20/s to take at least 200ms, took %v", elapsed)
+	}
+
+	// A caller waiting on the limiter gives up when its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err = client.GetTask(ctx, 7)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded while rate limited, got %v", err)
+	}
+
+	// A cancelled caller queued ahead of another waiter must not free a slot
+	// that the later waiter already holds
+	var mu sync.Mutex
+	var arrivals []time.Time
+	paced := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"task": []map[string]interface{}{{"id": 42, "display_id": 7, "command_name": "shell"}},
+		}})
+	}))
+	defer paced.Close()
+
+	pacedClient, err := mythic.NewClient(&mythic.Config{
+		ServerURL:         paced.URL,
+		APIToken:          "test-token",
+		SSL:               false,
+		RequestsPerSecond: 10,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer pacedClient.Close()
+
+	if _, err := pacedClient.GetTask(context.Background(), 7); err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	cancelCtx, cancelWaiter := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelWaiter()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = pacedClient.GetTask(cancelCtx, 7)
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = pacedClient.GetTask(context.Background(), 7)
+	}()
+	time.Sleep(40 * time.Millisecond)
+
+	if _, err := pacedClient.GetTask(context.Background(), 7); err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(arrivals) != 3 {
+		t.Fatalf("Expected 3 requests to reach the server, got %d", len(arrivals))
+	}
+	for i := 1; i < len(arrivals); i++ {
+		if gap := arrivals[i].Sub(arrivals[i-1]); gap < 90*time.Millisecond {
+			t.Errorf("Requests %d and %d arrived %v apart, expected at least 100ms", i, i+1, gap)
+		}
+	}
+
+	// Raw GraphQL requests share the same limiter
+	start = time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.ExecuteRawGraphQL(context.Background(), "query { task { id } }", nil); err != nil {
+			t.Fatalf("ExecuteRawGraphQL() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected 3 raw queries at 20/s to take at least 100ms, took %v", elapsed)
+	}
+}
+
+func TestExecuteRawGraphQLInto(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		out     interface{}
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name: "decodes data envelope",
+			body: `{"data":{"updateOperation":{"status":"success","error":""}}}`,
+			out: &struct {
+				UpdateOperation struct {
+					Status string `json:"status"`
+				} `json:"updateOperation"`
+			}{},
+		},
+		{
+			name:    "surfaces graphql error message",
+			body:    `{"errors":[{"message":"field 'nope' not found"}]}`,
+			out:     &map[string]interface{}{},
+			wantMsg: "field 'nope' not found",
+		},
+		{
+			name: "reports shape mismatch",
+			body: `{"data":{"updateOperation":"unexpected"}}`,
+			out: &struct {
+				UpdateOperation struct {
+					Status string `json:"status"`
+				} `json:"updateOperation"`
+			}{},
+			wantErr: mythic.ErrInvalidResponse,
+		},
+		{
+			name:    "rejects nil target",
+			body:    `{"data":{}}`,
+			wantErr: mythic.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client, err := mythic.NewClient(&mythic.Config{
+				ServerURL: srv.URL,
+				APIToken:  "test-token",
+				SSL:       false,
+			})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			err = client.ExecuteRawGraphQLInto(context.Background(), `query { x }`, nil, tt.out)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ExecuteRawGraphQLInto() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantMsg != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Fatalf("ExecuteRawGraphQLInto() error = %v, want message %q", err, tt.wantMsg)
+				}
+			case err != nil:
+				t.Fatalf("ExecuteRawGraphQLInto() error = %v", err)
+			}
+		})
+	}
+}
+
+// captureLogger records messages passed to the client logger, prefixed with
+// their level.
+type captureLogger struct {
+	messages []string
+}
+
+func (l *captureLogger) record(level, format string, v ...interface{}) {
+	l.messages = append(l.messages, level+" "+fmt.Sprintf(format, v...))
+}
+
+func (l *captureLogger) Debugf(format string, v ...interface{}) { l.record("DEBUG", format, v...) }
+func (l *captureLogger) Infof(format string, v ...interface{})  { l.record("INFO", format, v...) }
+func (l *captureLogger) Warnf(format string, v ...interface{})  { l.record("WARN", format, v...) }
+func (l *captureLogger) Errorf(format string, v ...interface{}) { l.record("ERROR", format, v...) }
+
+// withLevel returns the recorded messages at level.
+func (l *captureLogger) withLevel(level string) []string {
+	var out []string
+	for _, msg := range l.messages {
+		if strings.HasPrefix(msg, level+" ") {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+func TestClientMaxRows(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxRows   int
+		rows      int
+		wantLimit string
+		wantWarn  bool
+	}{
+		{name: "disabled by default", rows: 3},
+		{name: "injects limit", maxRows: 5, rows: 3, wantLimit: "limit: 5"},
+		{name: "warns when cap is hit", maxRows: 2, rows: 2, wantLimit: "limit: 2", wantWarn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotQuery string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query string `json:"query"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				gotQuery = req.Query
+
+				rows := make([]map[string]interface{}, tt.rows)
+				for i := range rows {
+					rows[i] = map[string]interface{}{"id": i + 1, "display_id": i + 1}
+				}
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"callback": rows}})
+			}))
+			defer srv.Close()
+
+			logger := &captureLogger{}
+			client, err := mythic.NewClient(&mythic.Config{
+				ServerURL: srv.URL,
+				APIToken:  "test-token",
+				SSL:       false,
+				MaxRows:   tt.maxRows,
+				Logger:    logger,
+			})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			callbacks, err := client.GetCallbacksFiltered(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("GetCallbacksFiltered() error = %v", err)
+			}
+			if len(callbacks) != tt.rows {
+				t.Errorf("Expected %d callbacks, got %d", tt.rows, len(callbacks))
+			}
+			if callbacks[0].DisplayID != 1 {
+				t.Errorf("Expected results copied back, got display ID %d", callbacks[0].DisplayID)
+			}
+
+			if tt.wantLimit == "" && strings.Contains(gotQuery, "limit:") {
+				t.Errorf("Expected no limit in query, got %q", gotQuery)
+			}
+			if tt.wantLimit != "" && !strings.Contains(gotQuery, tt.wantLimit) {
+				t.Errorf("Expected %q in query, got %q", tt.wantLimit, gotQuery)
+			}
+			if warned := len(logger.withLevel("WARN")) > 0; warned != tt.wantWarn {
+				t.Errorf("Expected warning = %v, got %v", tt.wantWarn, logger.messages)
+			}
+		})
+	}
+}
+
+func TestClientLogging(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(req.Query, "keylog") {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"field 'keylog' not found in type: 'query_root'"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"task":[{"id":42,"display_id":7,"command_name":"shell"}]}}`))
+	}))
+	defer srv.Close()
+
+	logger := &captureLogger{}
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		APIToken:  "test-token",
+		SSL:       false,
+		Logger:    logger,
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetTask(context.Background(), 7); err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	debug := strings.Join(logger.withLevel("DEBUG"), "\n")
+	for _, want := range []string{"graphql_request op=query name=task", "display_id", "graphql_ok op=query name=task duration="} {
+		if !strings.Contains(debug, want) {
+			t.Errorf("Expected debug log containing %q, got %v", want, logger.messages)
+		}
+	}
+
+	_, err = client.ExecuteRawGraphQL(context.Background(), `query KeylogProbe { keylog { id } }`, nil)
+	if err == nil {
+		t.Fatal("Expected ExecuteRawGraphQL() to fail")
+	}
+	errs := logger.withLevel("ERROR")
+	if len(errs) != 1 || !strings.Contains(errs[0], "name=KeylogProbe") || !strings.Contains(errs[0], "field 'keylog' not found") {
+		t.Errorf("Expected one error log with the operation name and raw error, got %v", errs)
+	}
+}
+
+func TestNewStdLogger(t *testing.T) {
+	var calls int32
+	srv := newFlakyGraphQLServer(t, 0, http.StatusOK, &calls)
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		APIToken:  "test-token",
+		SSL:       false,
+		Logger:    mythic.NewStdLogger(log.New(&buf, "", 0)),
+	})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.GetTask(context.Background(), 7); err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "DEBUG mythic: graphql_ok op=query name=task") {
+		t.Errorf("Expected leveled output, got %q", buf.String())
+	}
+}
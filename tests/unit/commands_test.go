@@ -1,8 +1,15 @@
 package unit
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -540,3 +547,78 @@ func TestParameterTypeConstants(t *testing.T) {
 		}
 	}
 }
+
+// TestCommandCache tests that EnableCommandCache serves repeated
+// GetCommandWithParameters calls from memory until expiry or invalidation
+func TestCommandCache(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"command":[{"id":3,"cmd":"ls","payload_type_id":1,
+			"payloadtype":{"name":"poseidon"},"commandparameters":[{"id":9,"command_id":3,"name":"path","type":"String"}]}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	get := func() *mythic.CommandWithParameters {
+		t.Helper()
+		cmd, err := client.GetCommandWithParameters(ctx, 1, "ls")
+		if err != nil {
+			t.Fatalf("GetCommandWithParameters() error = %v", err)
+		}
+		return cmd
+	}
+	expectCalls := func(want int32) {
+		t.Helper()
+		if got := atomic.LoadInt32(&calls); got != want {
+			t.Errorf("Expected %d requests, got %d", want, got)
+		}
+	}
+
+	// Disabled by default
+	get()
+	get()
+	expectCalls(2)
+
+	client.EnableCommandCache(time.Minute)
+	first := get()
+	first.Parameters[0].Name = "modified"
+	if second := get(); second.Parameters[0].Name != "path" {
+		t.Errorf("Expected cached entry to be unaffected by caller changes, got %q", second.Parameters[0].Name)
+	}
+	expectCalls(3)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.GetCommandWithParameters(ctx, 1, "ls")
+		}()
+	}
+	wg.Wait()
+	expectCalls(3)
+
+	// Entries are keyed by payload type as well as command name
+	if _, err := client.GetCommandWithParameters(ctx, 2, "ls"); err != nil {
+		t.Fatalf("GetCommandWithParameters() error = %v", err)
+	}
+	expectCalls(4)
+
+	client.InvalidateCommandCache()
+	get()
+	expectCalls(5)
+
+	client.EnableCommandCache(20 * time.Millisecond)
+	get()
+	time.Sleep(40 * time.Millisecond)
+	get()
+	expectCalls(7)
+}
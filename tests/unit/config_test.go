@@ -54,6 +54,46 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "negative task lookup attempts",
+			config: &mythic.Config{
+				ServerURL:          "https://mythic.example.com:7443",
+				TaskLookupAttempts: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative retry count",
+			config: &mythic.Config{
+				ServerURL: "https://mythic.example.com:7443",
+				Retry:     &mythic.RetryConfig{MaxRetries: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative retry delay",
+			config: &mythic.Config{
+				ServerURL: "https://mythic.example.com:7443",
+				Retry:     &mythic.RetryConfig{MaxRetries: 3, BaseDelay: -time.Second},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative requests per second",
+			config: &mythic.Config{
+				ServerURL:         "https://mythic.example.com:7443",
+				RequestsPerSecond: -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max rows",
+			config: &mythic.Config{
+				ServerURL: "https://mythic.example.com:7443",
+				MaxRows:   -1,
+			},
+			wantErr: true,
+		},
 		{
 			name: "missing ServerURL",
 			config: &mythic.Config{
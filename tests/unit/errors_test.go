@@ -101,6 +101,7 @@ func TestErrorConstants(t *testing.T) {
 		mythic.ErrNotFound,
 		mythic.ErrInvalidResponse,
 		mythic.ErrConnectionFailed,
+		mythic.ErrChecksumMismatch,
 	}
 
 	for _, err := range errs {
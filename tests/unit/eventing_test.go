@@ -1,9 +1,17 @@
 package unit
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -611,3 +619,232 @@ func TestWebhookRequest_String(t *testing.T) {
 		t.Errorf("String() should contain URL")
 	}
 }
+
+func TestEventExecution_IsTerminal(t *testing.T) {
+	tests := []struct {
+		status     string
+		terminal   bool
+		successful bool
+	}{
+		{types.EventExecutionRunning, false, false},
+		{types.EventExecutionSuccess, true, true},
+		{types.EventExecutionError, true, false},
+		{types.EventExecutionCancelled, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			e := &types.EventExecution{Status: tt.status}
+			if got := e.IsTerminal(); got != tt.terminal {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.terminal)
+			}
+			if got := e.IsSuccessful(); got != tt.successful {
+				t.Errorf("IsSuccessful() = %v, want %v", got, tt.successful)
+			}
+		})
+	}
+}
+
+func TestWaitForEventExecution(t *testing.T) {
+	var polls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := types.EventExecutionRunning
+		var steps []map[string]interface{}
+		if atomic.AddInt32(&polls, 1) > 1 {
+			status = types.EventExecutionError
+			steps = []map[string]interface{}{{"stderr": "step 2 exploded"}}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"eventgroupinstance": []map[string]interface{}{{
+					"id": 9, "eventgroup_id": 3, "operation_id": 1, "status": status,
+					"trigger": "manual", "current_order_step": 2, "total_order_steps": 3,
+					"created_at": "2024-01-01T00:00:00Z", "end_timestamp": nil,
+				}},
+				"eventstepinstance": steps,
+			},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	execution, err := client.WaitForEventExecution(context.Background(), 9, 10)
+	if !errors.Is(err, mythic.ErrOperationFailed) {
+		t.Fatalf("WaitForEventExecution() error = %v, want ErrOperationFailed", err)
+	}
+	if execution == nil || execution.Status != types.EventExecutionError {
+		t.Fatalf("execution = %+v, want status error", execution)
+	}
+	if !strings.Contains(execution.Error, "step 2 exploded") {
+		t.Errorf("execution.Error = %q, want failed step stderr", execution.Error)
+	}
+	if got := atomic.LoadInt32(&polls); got != 2 {
+		t.Errorf("Expected 2 status checks, got %d", got)
+	}
+
+	if _, err := client.GetEventExecutionStatus(context.Background(), 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetEventExecutionStatus(0) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSendExternalWebhookRaw(t *testing.T) {
+	var gotQuery string
+	var gotVariables map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                     `json:"query"`
+			Variables map[string]json.RawMessage `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotQuery, gotVariables = body.Query, body.Variables
+		if string(body.Variables["method"]) == `"SLOW"` {
+			time.Sleep(500 * time.Millisecond)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"sendExternalWebhook": map[string]interface{}{
+					"status":           "success",
+					"status_code":      202,
+					"response":         "accepted",
+					"response_headers": map[string]string{"X-RateLimit-Remaining": "9"},
+					"error":            "",
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	resp, err := client.SendExternalWebhookRaw(ctx, "https://hooks.example.com", "POST",
+		map[string]string{"X-Token": "abc"}, json.RawMessage(`[1,2,3]`), time.Second)
+	if err != nil {
+		t.Fatalf("SendExternalWebhookRaw() failed: %v", err)
+	}
+
+	if !strings.Contains(gotQuery, "$body:jsonb") || !strings.Contains(gotQuery, "$headers:jsonb") {
+		t.Errorf("body and headers should be declared as jsonb, got %s", gotQuery)
+	}
+	if string(gotVariables["body"]) != `[1,2,3]` {
+		t.Errorf("body = %s, want [1,2,3]", gotVariables["body"])
+	}
+	if string(gotVariables["headers"]) != `{"X-Token":"abc"}` {
+		t.Errorf("headers = %s, want {\"X-Token\":\"abc\"}", gotVariables["headers"])
+	}
+	if resp.StatusCode != 202 || resp.Headers["X-RateLimit-Remaining"] != "9" {
+		t.Errorf("response = %+v, want status 202 with rate-limit header", resp)
+	}
+
+	if _, err := client.SendExternalWebhookRaw(ctx, "https://hooks.example.com", "POST", nil, json.RawMessage(`{bad`), 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("invalid JSON body error = %v, want ErrInvalidInput", err)
+	}
+
+	start := time.Now()
+	if _, err := client.SendExternalWebhookRaw(ctx, "https://hooks.example.com", "SLOW", nil, nil, 50*time.Millisecond); err == nil {
+		t.Error("Expected timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("Timeout not applied, call took %s", elapsed)
+	}
+}
+
+// TestSendExternalWebhookRaw_NoResponseHeaders tests that the webhook is sent
+// again without response_headers when the schema does not have that field.
+func TestSendExternalWebhookRaw_NoResponseHeaders(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.Contains(body.Query, "response_headers") {
+			w.Write([]byte(`{"errors":[{"message":"field 'response_headers' not found in type: 'sendExternalWebhookOutput'","extensions":{"path":"$.selectionSet.sendExternalWebhook.selectionSet.response_headers","code":"validation-failed"}}]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"sendExternalWebhook": map[string]interface{}{
+					"status":      "success",
+					"status_code": 200,
+					"response":    "ok",
+					"error":       "",
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	resp, err := client.SendExternalWebhookRaw(context.Background(), "https://hooks.example.com", "POST", nil, json.RawMessage(`{}`), 0)
+	if err != nil {
+		t.Fatalf("SendExternalWebhookRaw() failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the webhook to be retried once without response_headers, got %d calls", calls)
+	}
+	if resp.StatusCode != 200 || resp.Response != "ok" || resp.Headers != nil {
+		t.Errorf("response = %+v, want status 200 with nil headers", resp)
+	}
+}
+
+func TestGetEventExecutionSteps(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"eventstepinstance": []map[string]interface{}{
+					{"id": 20, "order": 1, "status": "success", "stdout": "ok", "stderr": "", "end_timestamp": "2024-01-01T00:00:01Z", "eventstep": map[string]interface{}{"name": "gather"}},
+					{"id": 21, "order": 2, "status": "error", "stdout": "", "stderr": "timeout talking to agent", "end_timestamp": nil, "eventstep": map[string]interface{}{"name": "exfil"}},
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	steps, err := client.GetEventExecutionSteps(context.Background(), 9)
+	if err != nil {
+		t.Fatalf("GetEventExecutionSteps() failed: %v", err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("Expected 2 steps, got %d", len(steps))
+	}
+
+	var failed *types.EventStep
+	for _, step := range steps {
+		if step.IsFailed() {
+			failed = step
+			break
+		}
+	}
+	if failed == nil || failed.Order != 2 || failed.Name != "exfil" || failed.Error != "timeout talking to agent" {
+		t.Errorf("failed step = %+v, want order 2 'exfil' with stderr", failed)
+	}
+	if steps[0].EndTimestamp == nil || steps[1].EndTimestamp != nil {
+		t.Errorf("EndTimestamp = %v, %v; want set, nil", steps[0].EndTimestamp, steps[1].EndTimestamp)
+	}
+
+	if _, err := client.GetEventExecutionSteps(context.Background(), -1); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetEventExecutionSteps(-1) error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -1,6 +1,18 @@
 package unit
 
 import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -348,3 +360,454 @@ func TestFilePreview_Structure(t *testing.T) {
 		t.Error("Expected Contents to be non-empty")
 	}
 }
+
+func TestDownloadFile_Content(t *testing.T) {
+	small := []byte("hello from the agent")
+	large := bytes.Repeat([]byte("0123456789abcdef/+"), 4096)
+
+	wrap := func(data []byte) []byte {
+		b, _ := json.Marshal(map[string]string{"status": "success", "file": base64.StdEncoding.EncodeToString(data)})
+		return b
+	}
+
+	// Real JSON files over the sniff window that happen to have a "file" key
+	// must come back unchanged rather than be decoded as base64
+	largeJSON, _ := json.Marshal(struct {
+		File    string   `json:"file"`
+		Entries []string `json:"entries"`
+	}{"C:\\Users\\alice\\notes.txt", strings.Split(strings.Repeat("line of text,", 1000), ",")})
+	nestedJSON, _ := json.Marshal(map[string]interface{}{
+		"a": map[string]string{"file": strings.Repeat("QUJD", 2000)},
+	})
+
+	tests := []struct {
+		name     string
+		body     []byte
+		expected []byte
+	}{
+		{"raw small", small, small},
+		{"raw large", large, large},
+		{"wrapped small", wrap(small), small},
+		{"wrapped large", wrap(large), large},
+		{
+			name:     "wrapped large with escaped slashes",
+			body:     bytes.ReplaceAll(wrap(large), []byte("/"), []byte(`\/`)),
+			expected: large,
+		},
+		{"small json without file field", []byte(`{"key":"value"}`), []byte(`{"key":"value"}`)},
+		{"large json with file field", largeJSON, largeJSON},
+		{"large json with nested file field", nestedJSON, nestedJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFileClient(t, nil, tt.body)
+
+			data, err := client.DownloadFile(context.Background(), "file-1")
+			if err != nil {
+				t.Fatalf("DownloadFile() failed: %v", err)
+			}
+			if !bytes.Equal(data, tt.expected) {
+				t.Errorf("DownloadFile() returned %d bytes, expected %d", len(data), len(tt.expected))
+			}
+
+			var buf bytes.Buffer
+			n, err := client.DownloadFileToWriter(context.Background(), "file-1", &buf)
+			if err != nil {
+				t.Fatalf("DownloadFileToWriter() failed: %v", err)
+			}
+			if n != int64(len(tt.expected)) || !bytes.Equal(buf.Bytes(), tt.expected) {
+				t.Errorf("DownloadFileToWriter() wrote %d bytes (reported %d), expected %d", buf.Len(), n, len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestDownloadFile_ErrorResponse(t *testing.T) {
+	client := newFileClient(t, nil, []byte(`{"status":"error","error":"file not found"}`))
+
+	_, err := client.DownloadFile(context.Background(), "missing")
+	if !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := client.DownloadFileToWriter(context.Background(), "missing", &buf)
+	if !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if n != 0 || buf.Len() != 0 {
+		t.Errorf("Expected nothing written for an error response, got %d bytes", buf.Len())
+	}
+}
+
+func TestUploadFileReader_StreamsContent(t *testing.T) {
+	var received []byte
+	var receivedName string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "fake-access-token",
+				"refresh_token": "fake-refresh-token",
+				"user":          map[string]interface{}{"id": 1, "username": "operator1", "current_operation_id": 1},
+			})
+		case "/api/v1.4/task_upload_file_webhook":
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+			receivedName = header.Filename
+			received, _ = io.ReadAll(file)
+			json.NewEncoder(w).Encode(map[string]string{"status": "success", "agent_file_id": "uploaded-1"})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	data := bytes.Repeat([]byte("payload"), 50000)
+	var lastSent int64
+	agentFileID, err := client.UploadFileReader(context.Background(), "tool.bin", bytes.NewReader(data), int64(len(data)), func(sent int64) {
+		if sent < lastSent {
+			t.Errorf("progress went backwards: %d after %d", sent, lastSent)
+		}
+		lastSent = sent
+	})
+	if err != nil {
+		t.Fatalf("UploadFileReader() failed: %v", err)
+	}
+
+	if agentFileID != "uploaded-1" {
+		t.Errorf("Expected agent_file_id uploaded-1, got %q", agentFileID)
+	}
+	if receivedName != "tool.bin" {
+		t.Errorf("Expected filename tool.bin, got %q", receivedName)
+	}
+	if !bytes.Equal(received, data) {
+		t.Errorf("Server received %d bytes, expected %d", len(received), len(data))
+	}
+	if lastSent != int64(len(data)) {
+		t.Errorf("Final progress = %d, expected %d", lastSent, len(data))
+	}
+
+	// A reader shorter than the declared size fails the upload
+	_, err = client.UploadFileReader(context.Background(), "short.bin", bytes.NewReader(data[:10]), 100, nil)
+	if err == nil {
+		t.Error("Expected error when reader is shorter than size")
+	}
+}
+
+func TestUploadFileReader_Progress(t *testing.T) {
+	var received int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		received = len(data)
+		json.NewEncoder(w).Encode(map[string]string{"status": "success", "agent_file_id": "uploaded-2"})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	data := bytes.Repeat([]byte("x"), 300*1024+17)
+	total := int64(len(data))
+	var reports []int64
+	agentFileID, err := client.UploadFileReader(context.Background(), "big.bin", bytes.NewReader(data), total, func(sent int64) {
+		reports = append(reports, sent)
+	})
+	if err != nil {
+		t.Fatalf("UploadFileReader() failed: %v", err)
+	}
+	if agentFileID != "uploaded-2" || received != len(data) {
+		t.Errorf("Expected uploaded-2 with %d bytes, got %q with %d", len(data), agentFileID, received)
+	}
+
+	if len(reports) == 0 || reports[len(reports)-1] != total {
+		t.Fatalf("Expected final progress report of %d, got %v", total, reports)
+	}
+	for i := 1; i < len(reports)-1; i++ {
+		if reports[i]-reports[i-1] < 64*1024 {
+			t.Errorf("Progress reported at %d only %d bytes after %d", reports[i], reports[i]-reports[i-1], reports[i-1])
+		}
+	}
+}
+
+// TestUploadFileWrappers tests that UploadFileWithProgress and
+// UploadFileStreaming upload through UploadFileReader.
+func TestUploadFileWrappers(t *testing.T) {
+	var received int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		data, _ := io.ReadAll(file)
+		received = len(data)
+		json.NewEncoder(w).Encode(map[string]string{"status": "success", "agent_file_id": "uploaded-3"})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	data := bytes.Repeat([]byte("x"), 100*1024)
+	total := int64(len(data))
+	var lastSent int64
+	agentFileID, err := client.UploadFileWithProgress(context.Background(), "big.bin", bytes.NewReader(data), total, func(sent, size int64) {
+		if size != total {
+			t.Errorf("progress total = %d, want %d", size, total)
+		}
+		lastSent = sent
+	})
+	if err != nil {
+		t.Fatalf("UploadFileWithProgress() failed: %v", err)
+	}
+	if agentFileID != "uploaded-3" || received != len(data) || lastSent != total {
+		t.Errorf("UploadFileWithProgress() = %q with %d bytes and final progress %d, want uploaded-3 with %d", agentFileID, received, lastSent, total)
+	}
+
+	agentFileID, err = client.UploadFileStreaming(context.Background(), "big.bin", bytes.NewReader(data), total, nil)
+	if err != nil {
+		t.Fatalf("UploadFileStreaming() failed: %v", err)
+	}
+	if agentFileID != "uploaded-3" || received != len(data) {
+		t.Errorf("UploadFileStreaming() = %q with %d bytes, want uploaded-3 with %d", agentFileID, received, total)
+	}
+}
+
+func TestUploadFileReader_ContextCanceled(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never read the body, so the upload stalls until the client gives up
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	data := bytes.Repeat([]byte("x"), 16*1024*1024)
+	start := time.Now()
+	_, err = client.UploadFileReader(ctx, "stalled.bin", bytes.NewReader(data), int64(len(data)), nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Upload took %v to abort after cancellation", elapsed)
+	}
+}
+
+func TestUploadFileReader_Validation(t *testing.T) {
+	client := newFileClient(t, nil, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		filename string
+		reader   io.Reader
+		size     int64
+	}{
+		{"empty filename", "", strings.NewReader("x"), 1},
+		{"nil reader", "file.txt", nil, 1},
+		{"zero size", "file.txt", strings.NewReader("x"), 0},
+		{"negative size", "file.txt", strings.NewReader("x"), -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.UploadFileReader(ctx, tt.filename, tt.reader, tt.size, nil)
+			if !errors.Is(err, mythic.ErrInvalidInput) {
+				t.Errorf("Expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDownloadFileVerified(t *testing.T) {
+	data := []byte("hello from the agent")
+	md5Sum := md5.Sum(data)
+	sha1Sum := sha1.Sum(data)
+	goodMD5 := hex.EncodeToString(md5Sum[:])
+	goodSHA1 := hex.EncodeToString(sha1Sum[:])
+
+	tests := []struct {
+		name    string
+		md5     string
+		sha1    string
+		wantErr error
+	}{
+		{"matching hashes", goodMD5, goodSHA1, nil},
+		{"uppercase hashes", strings.ToUpper(goodMD5), strings.ToUpper(goodSHA1), nil},
+		{"no recorded hashes", "", "", nil},
+		{"md5 mismatch", strings.Repeat("0", 32), goodSHA1, mythic.ErrChecksumMismatch},
+		{"sha1 mismatch", goodMD5, strings.Repeat("0", 40), mythic.ErrChecksumMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFileClient(t, map[string]interface{}{"id": 1, "agent_file_id": "file-1", "md5": tt.md5, "sha1": tt.sha1}, data)
+
+			got, err := client.DownloadFileVerified(context.Background(), "file-1")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, mythic.ErrInvalidResponse) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+				}
+				if hash := strings.Fields(tt.name)[0]; !strings.Contains(err.Error(), hash+" mismatch") {
+					t.Errorf("Expected error to name the %s hash, got %v", hash, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadFileVerified() failed: %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("Expected %q, got %q", data, got)
+			}
+		})
+	}
+}
+
+func TestGetFilesByFilter(t *testing.T) {
+	var gotVars map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotVars = req.Variables
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"filemeta": []map[string]interface{}{{"id": 3, "agent_file_id": "file-3", "host": "WS01", "is_download_from_agent": true}},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	downloaded, deleted, host := true, false, "ws%"
+	files, err := client.GetFilesByFilter(context.Background(), &mythic.FileFilter{
+		IsDownloadFromAgent: &downloaded,
+		Deleted:             &deleted,
+		Host:                &host,
+	}, 0)
+	if err != nil {
+		t.Fatalf("GetFilesByFilter() failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"is_download_from_agent": map[string]interface{}{"_eq": true},
+		"deleted":                map[string]interface{}{"_eq": false},
+		"host":                   map[string]interface{}{"_ilike": "ws%"},
+	}
+	where, _ := gotVars["where"].(map[string]interface{})
+	if len(where) != len(want) {
+		t.Errorf("Expected where %v, got %v", want, where)
+	}
+	for column, cond := range want {
+		if got, _ := json.Marshal(where[column]); string(got) != mustJSON(t, cond) {
+			t.Errorf("Expected %s condition %s, got %s", column, mustJSON(t, cond), got)
+		}
+	}
+	if gotVars["limit"] != float64(100) {
+		t.Errorf("Expected default limit 100, got %v", gotVars["limit"])
+	}
+
+	if len(files) != 1 || files[0].AgentFileID != "file-3" || files[0].Host != "WS01" {
+		t.Errorf("Unexpected files %+v", files)
+	}
+
+	// A nil filter sends an empty where clause
+	if _, err := client.GetFiles(context.Background(), 5); err != nil {
+		t.Fatalf("GetFiles() failed: %v", err)
+	}
+	if where, _ := gotVars["where"].(map[string]interface{}); len(where) != 0 || gotVars["limit"] != float64(5) {
+		t.Errorf("Expected empty where and limit 5, got %v", gotVars)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return string(data)
+}
+
+func TestGetFilesForOperation(t *testing.T) {
+	var gotVars map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotVars = req.Variables
+		w.Write([]byte(`{"data":{"filemeta":[]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	files, err := client.GetFilesForOperation(context.Background(), 3, 10)
+	if err != nil {
+		t.Fatalf("GetFilesForOperation() failed: %v", err)
+	}
+	if files == nil || len(files) != 0 {
+		t.Errorf("Expected an empty, non-nil slice, got %#v", files)
+	}
+
+	where, _ := gotVars["where"].(map[string]interface{})
+	if got := mustJSON(t, where["operation_id"]); got != `{"_eq":3}` {
+		t.Errorf("Expected operation_id filter, got %s", got)
+	}
+
+	if _, err := client.GetFilesForOperation(context.Background(), 0, 10); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for operation 0, got %v", err)
+	}
+}
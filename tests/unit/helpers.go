@@ -1,5 +1,15 @@
 package unit
 
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
+)
+
 // contains checks if a string contains a substring
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && findSubstring(s, substr))
@@ -13,3 +23,46 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// newFileClient returns a logged-in client backed by a server that answers
+// every filemeta query with filemeta (no rows when nil) and every file
+// download with body.
+func newFileClient(t *testing.T, filemeta map[string]interface{}, body []byte) *mythic.Client {
+	t.Helper()
+
+	rows := []map[string]interface{}{}
+	if filemeta != nil {
+		rows = append(rows, filemeta)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/auth":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "fake-access-token",
+				"refresh_token": "fake-refresh-token",
+				"user":          map[string]interface{}{"id": 1, "username": "operator1", "current_operation_id": 1},
+			})
+		case r.URL.Path == "/graphql/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"filemeta": rows}})
+		case strings.HasPrefix(r.URL.Path, "/api/v1.4/files/download/"):
+			w.Write(body)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
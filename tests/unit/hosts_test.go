@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
+)
+
+// TestGetHosts_AggregatesCallbacks tests that GetHosts groups callbacks by
+// host, counts them, tracks first/last seen and includes file-only hosts.
+func TestGetHosts_AggregatesCallbacks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		if strings.Contains(req.Query, "HostCallbackCounts") {
+			// The per-host aggregates must be filtered on the exact host values
+			if req.Variables["host_0"] != "DC01" || req.Variables["host_1"] != "WS01" || req.Variables["host_2"] != "ws01" {
+				t.Errorf("Unexpected host variables: %v", req.Variables)
+			}
+			all := func(count int, first, last string) map[string]interface{} {
+				return map[string]interface{}{"aggregate": map[string]interface{}{
+					"count": count,
+					"min":   map[string]interface{}{"init_callback": first},
+					"max":   map[string]interface{}{"last_checkin": last},
+				}}
+			}
+			active := func(count int) map[string]interface{} {
+				return map[string]interface{}{"aggregate": map[string]interface{}{"count": count}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"h0_all":    all(1, "2026-01-04T00:00:00", "2026-01-04T12:00:00"),
+				"h0_active": active(1),
+				"h1_all":    all(3, "2026-01-03T00:00:00", "2026-01-05T00:00:00"),
+				"h1_active": active(2),
+				"h2_all":    all(2, "2026-01-01T00:00:00", "2026-01-02T00:00:00"),
+				"h2_active": active(0),
+			}})
+			return
+		}
+
+		if !strings.Contains(req.Query, "distinct_on: host") {
+			t.Errorf("Expected distinct callback hosts, got query %s", req.Query)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"callback": []map[string]interface{}{
+				{"host": "DC01", "os": "Windows Server", "operation_id": 1},
+				{"host": "WS01", "os": "Windows 11", "operation_id": 1},
+				{"host": "ws01", "os": "Windows 10", "operation_id": 1},
+			},
+			"filemeta": []map[string]interface{}{
+				{"host": "FS01", "timestamp": "2026-01-02T08:00:00"},
+				{"host": "WS01", "timestamp": "2025-12-31T00:00:00"},
+			},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	hosts, err := client.GetHosts(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetHosts() error = %v", err)
+	}
+
+	if len(hosts) != 3 {
+		t.Fatalf("Expected 3 hosts, got %d", len(hosts))
+	}
+	wantOrder := []string{"DC01", "FS01", "WS01"}
+	for i, want := range wantOrder {
+		if hosts[i].Hostname != want {
+			t.Errorf("hosts[%d].Hostname = %q, want %q", i, hosts[i].Hostname, want)
+		}
+		if hosts[i].ID != i+1 {
+			t.Errorf("hosts[%d].ID = %d, want %d", i, hosts[i].ID, i+1)
+		}
+	}
+
+	ws := hosts[2]
+	if ws.CallbackCount != 5 || ws.ActiveCallbackCount != 2 || ws.GetCallbackCount() != 2 {
+		t.Errorf("WS01 counts = %d total/%d active, want 5/2", ws.CallbackCount, ws.ActiveCallbackCount)
+	}
+	if ws.OS != "Windows 11" {
+		t.Errorf("WS01 OS = %q, want the most recently seen spelling's %q", ws.OS, "Windows 11")
+	}
+	if want := time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC); !ws.Timestamp.Equal(want) {
+		t.Errorf("WS01 first seen = %v, want %v", ws.Timestamp, want)
+	}
+	if want := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC); !ws.LastSeen.Equal(want) {
+		t.Errorf("WS01 last seen = %v, want %v", ws.LastSeen, want)
+	}
+
+	fs := hosts[1]
+	if fs.CallbackCount != 0 || fs.OperationID != 1 {
+		t.Errorf("FS01 = %+v, want a file-only host in operation 1", fs)
+	}
+}
@@ -1,9 +1,17 @@
 package unit
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -268,3 +276,132 @@ func TestKeylogFields(t *testing.T) {
 		t.Errorf("Expected CallbackID 101, got %d", keylog.CallbackID)
 	}
 }
+
+// TestSearchKeylogs tests that keylogs select the callback through its
+// relationship, apply filters server-side and decode base64 keystrokes.
+func TestSearchKeylogs(t *testing.T) {
+	var gotQuery string
+	var gotWhere map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotQuery = req.Query
+		gotWhere, _ = req.Variables["where"].(map[string]interface{})
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"keylog": []map[string]interface{}{
+				{
+					"id": 1, "task_id": 9, "window": "Notepad", "user": "alice", "operation_id": 3,
+					"timestamp":  "2026-01-02T03:04:05Z",
+					"keystrokes": base64.StdEncoding.EncodeToString([]byte("hunter2[ENTER]")),
+					"callback":   map[string]interface{}{"id": 40, "display_id": 4, "host": "WS01", "user": "alice"},
+				},
+				{
+					"id": 2, "task_id": 9, "operation_id": 3,
+					"timestamp":  "2026-01-02T03:04:00Z",
+					"keystrokes": "plain text",
+					"callback":   map[string]interface{}{"id": 40, "display_id": 4, "host": "WS01", "user": "alice"},
+				},
+				{
+					// Valid base64 that decodes to NUL bytes
+					"id": 3, "task_id": 9, "operation_id": 3,
+					"timestamp":  "2026-01-02T03:03:00Z",
+					"keystrokes": "AAAA",
+					"callback":   map[string]interface{}{"id": 40, "display_id": 4, "host": "WS01", "user": "alice"},
+				},
+				{
+					// Valid base64 that decodes to control characters
+					"id": 4, "task_id": 9, "operation_id": 3,
+					"timestamp":  "2026-01-02T03:02:00Z",
+					"keystrokes": "AQID",
+					"callback":   map[string]interface{}{"id": 40, "display_id": 4, "host": "WS01", "user": "alice"},
+				},
+			},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	keylogs, err := client.SearchKeylogs(context.Background(), &mythic.KeylogQueryOptions{
+		OperationID:       3,
+		CallbackDisplayID: 4,
+		Since:             since,
+	})
+	if err != nil {
+		t.Fatalf("SearchKeylogs() error = %v", err)
+	}
+
+	if strings.Contains(gotQuery, "callback_id") {
+		t.Errorf("Query must not select keylog.callback_id, got %q", gotQuery)
+	}
+	if !strings.Contains(gotQuery, "callback{id,display_id,host,user}") {
+		t.Errorf("Expected nested callback selection, got %q", gotQuery)
+	}
+	callback, _ := gotWhere["callback"].(map[string]interface{})
+	if _, ok := callback["display_id"]; !ok {
+		t.Errorf("Expected callback display_id filter, got %v", gotWhere)
+	}
+	timestamp, _ := gotWhere["timestamp"].(map[string]interface{})
+	if timestamp["_gte"] != "2026-01-01T00:00:00Z" {
+		t.Errorf("Expected timestamp _gte filter, got %v", gotWhere)
+	}
+
+	if len(keylogs) != 4 {
+		t.Fatalf("Expected 4 keylogs, got %d", len(keylogs))
+	}
+	first := keylogs[0]
+	if first.Keystrokes != "hunter2[ENTER]" {
+		t.Errorf("Expected decoded keystrokes, got %q", first.Keystrokes)
+	}
+	if first.CallbackID != 40 || first.CallbackDisplayID != 4 {
+		t.Errorf("Expected callback 40 (display 4), got %d (display %d)", first.CallbackID, first.CallbackDisplayID)
+	}
+	if first.Callback == nil || first.Callback.Host != "WS01" {
+		t.Errorf("Expected callback host WS01, got %+v", first.Callback)
+	}
+	if keylogs[1].Keystrokes != "plain text" {
+		t.Errorf("Expected non-base64 keystrokes unchanged, got %q", keylogs[1].Keystrokes)
+	}
+	// Plaintext that is also valid base64 must not be decoded into garbage
+	for i, want := range []string{"AAAA", "AQID"} {
+		if got := keylogs[i+2].Keystrokes; got != want {
+			t.Errorf("Expected plaintext keystrokes %q unchanged, got %q", want, got)
+		}
+	}
+}
+
+// TestSearchKeylogs_InvalidInput tests SearchKeylogs input validation.
+func TestSearchKeylogs_InvalidInput(t *testing.T) {
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: "http://127.0.0.1:1", APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	now := time.Now()
+	tests := []struct {
+		name string
+		opts *mythic.KeylogQueryOptions
+	}{
+		{"negative limit", &mythic.KeylogQueryOptions{OperationID: 1, Limit: -1}},
+		{"negative callback", &mythic.KeylogQueryOptions{OperationID: 1, CallbackID: -1}},
+		{"inverted range", &mythic.KeylogQueryOptions{OperationID: 1, Since: now, Until: now.Add(-time.Hour)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.SearchKeylogs(context.Background(), tt.opts)
+			if !errors.Is(err, mythic.ErrInvalidInput) {
+				t.Errorf("SearchKeylogs() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
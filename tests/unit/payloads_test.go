@@ -1,9 +1,18 @@
 package unit
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -491,3 +500,440 @@ func TestExportPayloadConfigResponseTypes(t *testing.T) {
 		t.Error("Expected Config to be set")
 	}
 }
+
+// newPayloadBuildServer returns a server describing a "poseidon" payload type
+// with one required build parameter and the http and smb C2 profiles. Calls
+// to createPayload are counted and their payload definitions recorded.
+func newPayloadBuildServer(t *testing.T, creates *int32, definition *map[string]interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case strings.Contains(req.Query, "createPayload"):
+			atomic.AddInt32(creates, 1)
+			if def, ok := req.Variables["payload_definition"].(string); ok {
+				_ = json.Unmarshal([]byte(def), definition)
+			}
+			_, _ = w.Write([]byte(`{"data":{"createPayload":{"status":"success","error":"","uuid":"abc-123"}}}`))
+		case strings.Contains(req.Query, "payloadtype("):
+			_, _ = w.Write([]byte(`{"data":{"payloadtype":[{"id":5,"name":"poseidon","payloadtypec2profiles":[
+				{"c2profile":{"name":"http"}},{"c2profile":{"name":"smb"}}]}]}}`))
+		case strings.Contains(req.Query, "buildparameter("):
+			_, _ = w.Write([]byte(`{"data":{"buildparameter":[
+				{"id":1,"name":"mode","required":true,"default_value":""},
+				{"id":2,"name":"architecture","required":true,"default_value":"AMD_x64"},
+				{"id":3,"name":"garble","required":false,"default_value":""}]}}`))
+		case strings.Contains(req.Query, "c2profile_by_pk"):
+			_, _ = w.Write([]byte(`{"data":{"c2profile_by_pk":{"id":2,"c2profileparameters":[
+				{"id":10,"name":"callback_host","required":true,"default_value":""},
+				{"id":11,"name":"callback_port","required":true,"default_value":"80"},
+				{"id":12,"name":"AESPSK","required":true,"default_value":"","crypto_type":true},
+				{"id":13,"name":"killdate","required":false,"default_value":""}]}}}`))
+		case strings.Contains(req.Query, "c2profile("):
+			name, _ := req.Variables["name"].(string)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"c2profile": []map[string]interface{}{{"id": 2, "name": name, "is_p2p": name == "smb"}},
+			}})
+		case strings.Contains(req.Query, "payload("):
+			_, _ = w.Write([]byte(`{"data":{"payload":[{"id":9,"uuid":"abc-123","build_phase":"building","payloadtype":{"id":5,"name":"poseidon"}}]}}`))
+		default:
+			t.Errorf("unexpected query %q", req.Query)
+		}
+	}))
+}
+
+// TestCreatePayload_ValidatesParameters tests that builds missing required
+// parameters are rejected before createPayload is called when Validate is set.
+func TestCreatePayload_ValidatesParameters(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *types.CreatePayloadRequest
+		wantErr     error
+		wantMissing []string
+	}{
+		{
+			name: "reports every missing parameter",
+			req: &types.CreatePayloadRequest{
+				PayloadType: "poseidon",
+				C2Profiles:  []types.C2ProfileConfig{{Name: "http"}},
+				Validate:    true,
+			},
+			wantErr:     mythic.ErrInvalidInput,
+			wantMissing: []string{"build[mode]", "c2 http[callback_host]"},
+		},
+		{
+			name: "rejects unsupported C2 profile",
+			req: &types.CreatePayloadRequest{
+				PayloadType:     "poseidon",
+				BuildParameters: map[string]interface{}{"mode": "default"},
+				C2Profiles:      []types.C2ProfileConfig{{Name: "dns"}},
+				Validate:        true,
+			},
+			wantErr: mythic.ErrInvalidInput,
+		},
+		{
+			name:    "rejects unknown payload type",
+			req:     &types.CreatePayloadRequest{PayloadType: "nope", Validate: true},
+			wantErr: mythic.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var creates int32
+			var definition map[string]interface{}
+			srv := newPayloadBuildServer(t, &creates, &definition)
+			defer srv.Close()
+
+			client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			_, err = client.CreatePayload(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreatePayload() error = %v, want %v", err, tt.wantErr)
+			}
+			for _, want := range tt.wantMissing {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Expected error to mention %q, got %v", want, err)
+				}
+			}
+			if strings.Contains(err.Error(), "architecture") || strings.Contains(err.Error(), "AESPSK") {
+				t.Errorf("Parameters with defaults or generated values should not be reported: %v", err)
+			}
+			if atomic.LoadInt32(&creates) != 0 {
+				t.Error("createPayload should not be called when validation fails")
+			}
+		})
+	}
+}
+
+// TestCreatePayload_SubmitsValidRequest tests that a complete request is
+// submitted with each C2 profile's P2P flag looked up from the server.
+func TestCreatePayload_SubmitsValidRequest(t *testing.T) {
+	var creates int32
+	var definition map[string]interface{}
+	srv := newPayloadBuildServer(t, &creates, &definition)
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	req := &types.CreatePayloadRequest{
+		PayloadType:     "poseidon",
+		BuildParameters: map[string]interface{}{"mode": "default"},
+		C2Profiles: []types.C2ProfileConfig{
+			{Name: "http", Parameters: map[string]interface{}{"callback_host": "https://10.0.0.1"}},
+			{Name: "smb", Parameters: map[string]interface{}{"callback_host": "pipe"}},
+		},
+	}
+
+	if err := client.ValidatePayloadRequest(context.Background(), req); err != nil {
+		t.Fatalf("ValidatePayloadRequest() error = %v", err)
+	}
+
+	payload, err := client.CreatePayload(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreatePayload() error = %v", err)
+	}
+	if payload.UUID != "abc-123" {
+		t.Errorf("Expected payload abc-123, got %q", payload.UUID)
+	}
+	if got := atomic.LoadInt32(&creates); got != 1 {
+		t.Fatalf("Expected 1 createPayload call, got %d", got)
+	}
+
+	profiles, _ := definition["c2_profiles"].([]interface{})
+	if len(profiles) != 2 {
+		t.Fatalf("Expected 2 C2 profiles in definition, got %v", definition["c2_profiles"])
+	}
+	for _, raw := range profiles {
+		profile := raw.(map[string]interface{})
+		wantP2P := profile["c2_profile"] == "smb"
+		if profile["c2_profile_is_p2p"] != wantP2P {
+			t.Errorf("Profile %v c2_profile_is_p2p = %v, want %v", profile["c2_profile"], profile["c2_profile_is_p2p"], wantP2P)
+		}
+	}
+}
+
+// TestCreatePayload_SkipsValidationByDefault tests that without Validate a
+// build missing required parameters is still submitted, leaving Mythic to
+// reject it, with P2P flags looked up for the selected C2 profiles.
+func TestCreatePayload_SkipsValidationByDefault(t *testing.T) {
+	var creates int32
+	var definition map[string]interface{}
+	srv := newPayloadBuildServer(t, &creates, &definition)
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	req := &types.CreatePayloadRequest{
+		PayloadType: "poseidon",
+		C2Profiles:  []types.C2ProfileConfig{{Name: "smb"}},
+	}
+	if _, err := client.CreatePayload(context.Background(), req); err != nil {
+		t.Fatalf("CreatePayload() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&creates); got != 1 {
+		t.Fatalf("Expected 1 createPayload call, got %d", got)
+	}
+
+	profiles, _ := definition["c2_profiles"].([]interface{})
+	if len(profiles) != 1 || profiles[0].(map[string]interface{})["c2_profile_is_p2p"] != true {
+		t.Errorf("Expected smb to be submitted as P2P, got %v", definition["c2_profiles"])
+	}
+}
+
+// TestWaitForPayloadBuild tests polling until the build finishes.
+func TestWaitForPayloadBuild(t *testing.T) {
+	tests := []struct {
+		name      string
+		phases    []string
+		timeout   int
+		wantErr   error
+		wantPhase string
+		wantMsg   string
+	}{
+		{name: "returns built payload", phases: []string{"submitted", "building", "success"}, timeout: 10, wantPhase: "success"},
+		{name: "reports build message on failure", phases: []string{"building", "error"}, timeout: 10,
+			wantErr: mythic.ErrTaskFailed, wantPhase: "error", wantMsg: "go build exited 1"},
+		{name: "reports last phase on timeout", phases: []string{"building"}, timeout: 1,
+			wantErr: mythic.ErrTimeout, wantPhase: "building", wantMsg: "last build_phase: building"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&polls, 1)) - 1
+				if n >= len(tt.phases) {
+					n = len(tt.phases) - 1
+				}
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+					"payload": []map[string]interface{}{{
+						"id": 9, "uuid": "abc-123", "build_phase": tt.phases[n], "build_message": "go build exited 1",
+						"payloadtype": map[string]interface{}{"id": 5, "name": "poseidon"},
+					}},
+				}})
+			}))
+			defer srv.Close()
+
+			client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			payload, err := client.WaitForPayloadBuild(context.Background(), "abc-123", tt.timeout)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("WaitForPayloadBuild() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WaitForPayloadBuild() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Expected error to contain %q, got %v", tt.wantMsg, err)
+			}
+			if payload == nil || payload.BuildPhase != tt.wantPhase {
+				t.Errorf("Expected payload in phase %q, got %+v", tt.wantPhase, payload)
+			}
+		})
+	}
+}
+
+// TestWaitForPayloadBuild_ContextCanceled tests that cancellation stops polling.
+func TestWaitForPayloadBuild_ContextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"payload":[{"id":9,"uuid":"abc-123","build_phase":"building","payloadtype":{"id":5,"name":"poseidon"}}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.WaitForPayloadBuild(ctx, "abc-123", 60)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForPayloadBuild() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("WaitForPayloadBuild() took %v after cancellation", elapsed)
+	}
+}
+
+// TestDownloadPayload tests that the payload's file is resolved and fetched
+// through the file download endpoint.
+func TestDownloadPayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		payloads string
+		wantErr  error
+	}{
+		{name: "downloads built payload", payloads: `[{"build_phase":"success","filemetum":{"agent_file_id":"file-789"}}]`},
+		{name: "payload still building", payloads: `[{"build_phase":"building","filemetum":null}]`, wantErr: mythic.ErrNotFound},
+		{name: "unknown payload", payloads: `[]`, wantErr: mythic.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var downloadPath string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/graphql/", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"data":{"payload":` + tt.payloads + `}}`))
+			})
+			mux.HandleFunc("/api/v1.4/files/download/", func(w http.ResponseWriter, r *http.Request) {
+				downloadPath = r.URL.Path
+				_, _ = w.Write([]byte("\x7fELF payload"))
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			defer client.Close()
+
+			data, err := client.DownloadPayload(context.Background(), "abc-123")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DownloadPayload() error = %v, want %v", err, tt.wantErr)
+				}
+				if downloadPath != "" {
+					t.Errorf("Expected no download request, got %s", downloadPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadPayload() error = %v", err)
+			}
+			if downloadPath != "/api/v1.4/files/download/file-789" {
+				t.Errorf("Expected download of file-789, got %s", downloadPath)
+			}
+			if string(data) != "\x7fELF payload" {
+				t.Errorf("Unexpected payload data %q", data)
+			}
+		})
+	}
+}
+
+// TestSearchPayloads tests that filters are sent as a where clause and
+// results are mapped with their payload type.
+func TestSearchPayloads(t *testing.T) {
+	var gotVars map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotVars = req.Variables
+		_, _ = w.Write([]byte(`{"data":{"payload":[{"id":9,"uuid":"abc-123","description":"beacon","os":"Linux",
+			"build_phase":"success","creation_time":"2026-01-02T03:04:05","payloadtype":{"id":5,"name":"poseidon"}}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	live := false
+	payloads, err := client.SearchPayloads(context.Background(), &mythic.PayloadQueryOptions{
+		Deleted:     &live,
+		BuildPhase:  "success",
+		PayloadType: "poseidon",
+		OS:          "linux",
+		Limit:       10,
+	})
+	if err != nil {
+		t.Fatalf("SearchPayloads() error = %v", err)
+	}
+
+	where, _ := json.Marshal(gotVars["where"])
+	want := `{"build_phase":{"_eq":"success"},"deleted":{"_eq":false},"os":{"_ilike":"linux"},"payloadtype":{"name":{"_eq":"poseidon"}}}`
+	if string(where) != want {
+		t.Errorf("where = %s, want %s", where, want)
+	}
+	if gotVars["limit"] != float64(10) {
+		t.Errorf("Expected limit 10, got %v", gotVars["limit"])
+	}
+
+	if len(payloads) != 1 {
+		t.Fatalf("Expected 1 payload, got %d", len(payloads))
+	}
+	p := payloads[0]
+	if p.UUID != "abc-123" || p.PayloadType.Name != "poseidon" || p.BuildPhase != "success" || p.CreationTime.IsZero() {
+		t.Errorf("Unexpected payload %+v", p)
+	}
+
+	if _, err := client.SearchPayloads(context.Background(), &mythic.PayloadQueryOptions{Limit: -1}); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("SearchPayloads() error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestDeletePayload(t *testing.T) {
+	var gotQuery string
+	affected := 1
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotQuery = req.Query
+		_, _ = fmt.Fprintf(w, `{"data":{"update_payload":{"affected_rows":%d}}}`, affected)
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := client.DeletePayload(context.Background(), "abc-123"); err != nil {
+		t.Fatalf("DeletePayload() error = %v", err)
+	}
+	if !strings.Contains(gotQuery, "deleted: true") {
+		t.Errorf("Expected soft-delete mutation, got %s", gotQuery)
+	}
+
+	if err := client.UpdatePayloadDescription(context.Background(), "abc-123", "new"); err != nil {
+		t.Fatalf("UpdatePayloadDescription() error = %v", err)
+	}
+	if !strings.Contains(gotQuery, "description: $description") {
+		t.Errorf("Expected description mutation, got %s", gotQuery)
+	}
+
+	affected = 0
+	if err := client.DeletePayload(context.Background(), "missing"); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("DeletePayload() error = %v, want ErrNotFound", err)
+	}
+	if err := client.UpdatePayloadDescription(context.Background(), "missing", "new"); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("UpdatePayloadDescription() error = %v, want ErrNotFound", err)
+	}
+	if err := client.DeletePayload(context.Background(), ""); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("DeletePayload() error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,433 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
+)
+
+// newResponseServer serves a callback's responses from ids, honouring the
+// where.id._lt cursor and limit of response queries.
+func newResponseServer(t *testing.T, mu *sync.Mutex, ids *[]int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "fake-access-token",
+				"refresh_token": "fake-refresh-token",
+				"user":          map[string]interface{}{"id": 1, "username": "operator1", "current_operation_id": 1},
+			})
+		case "/graphql/":
+			var body struct {
+				Variables struct {
+					Where struct {
+						ID struct {
+							Lt int `json:"_lt"`
+						} `json:"id"`
+					} `json:"where"`
+					Limit int `json:"limit"`
+				} `json:"variables"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+
+			mu.Lock()
+			sorted := append([]int(nil), *ids...)
+			mu.Unlock()
+			sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+			rows := []map[string]interface{}{}
+			for _, id := range sorted {
+				if body.Variables.Where.ID.Lt > 0 && id >= body.Variables.Where.ID.Lt {
+					continue
+				}
+				if len(rows) == body.Variables.Limit {
+					break
+				}
+				rows = append(rows, map[string]interface{}{
+					"id": id, "response_text": "out", "task_id": 1,
+					"task": map[string]interface{}{"id": 1, "command_name": "shell", "status": "completed", "callback_id": 1},
+				})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"response": rows}})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+}
+
+func TestResponseIterator_StableWhileResponsesArrive(t *testing.T) {
+	var mu sync.Mutex
+	ids := []int{1, 2, 3, 4, 5}
+	srv := newResponseServer(t, &mu, &ids)
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	it := mythic.NewResponseIterator(client, 1, 2)
+	var got []int
+	for {
+		resp, err := it.Next(context.Background())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() failed: %v", err)
+		}
+		got = append(got, resp.ID)
+		if resp.TaskStatus != "completed" || resp.TaskCallbackID != 1 {
+			t.Errorf("Expected task status and callback on response %d, got %q and %d", resp.ID, resp.TaskStatus, resp.TaskCallbackID)
+		}
+
+		// New output arriving mid-iteration must not shift later pages
+		mu.Lock()
+		ids = append(ids, 100+len(got))
+		mu.Unlock()
+	}
+
+	want := []int{5, 4, 3, 2, 1}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, got)
+		}
+	}
+
+	// The iterator stays exhausted
+	if _, err := it.Next(context.Background()); err != io.EOF {
+		t.Errorf("Expected io.EOF after exhaustion, got %v", err)
+	}
+}
+
+func TestResponseIterator_NilClient(t *testing.T) {
+	it := mythic.NewResponseIterator(nil, 1, 10)
+	if _, err := it.Next(context.Background()); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for nil client, got %v", err)
+	}
+}
+
+// newSearchServer serves rows to SearchResponses, honouring limit and offset,
+// and records the variables of each query.
+func newSearchServer(t *testing.T, rows []string, requests *[]map[string]interface{}) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		body.Variables["query"] = body.Query
+		*requests = append(*requests, body.Variables)
+
+		limit := int(body.Variables["limit"].(float64))
+		offset := int(body.Variables["offset"].(float64))
+		page := []map[string]interface{}{}
+		for i := offset; i < len(rows) && len(page) < limit; i++ {
+			page = append(page, map[string]interface{}{"id": i + 1, "response_text": rows[i], "task_id": 1})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"response": page}})
+	}))
+}
+
+func TestSearchResponses_Modes(t *testing.T) {
+	callbackID := 3
+	isError := true
+
+	tests := []struct {
+		name      string
+		req       types.ResponseSearchRequest
+		wantWhere string
+		wantIDs   []int
+	}{
+		{
+			name:      "default is case-insensitive",
+			req:       types.ResponseSearchRequest{Query: "admin_1", CallbackID: &callbackID},
+			wantWhere: `{"response_text":{"_ilike":"%admin\\_1%"},"task":{"callback_id":{"_eq":3}}}`,
+		},
+		{
+			name:      "substring",
+			req:       types.ResponseSearchRequest{Query: "100%", SearchMode: types.SearchModeSubstring, CallbackID: &callbackID, IsError: &isError},
+			wantWhere: `{"is_error":{"_eq":true},"response_text":{"_like":"%100\\%%"},"task":{"callback_id":{"_eq":3}}}`,
+		},
+		{
+			name:      "regex is matched client-side",
+			req:       types.ResponseSearchRequest{Query: `user=\w+`, SearchMode: types.SearchModeRegex, CallbackID: &callbackID, Limit: 2},
+			wantWhere: `{"task":{"callback_id":{"_eq":3}}}`,
+			wantIDs:   []int{2, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := []string{"nothing", "user=admin", "user=", "user=svc", "user=root"}
+			var requests []map[string]interface{}
+			srv := newSearchServer(t, rows, &requests)
+			defer srv.Close()
+
+			client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			defer client.Close()
+
+			req := tt.req
+			results, err := client.SearchResponses(context.Background(), &req)
+			if err != nil {
+				t.Fatalf("SearchResponses() failed: %v", err)
+			}
+			if len(requests) == 0 {
+				t.Fatal("Expected a response query")
+			}
+
+			where, _ := json.Marshal(requests[0]["where"])
+			if string(where) != tt.wantWhere {
+				t.Errorf("where = %s, want %s", where, tt.wantWhere)
+			}
+			if q := requests[0]["query"].(string); !strings.Contains(q, "$order_by:[response_order_by!]") {
+				t.Errorf("query should declare order_by as [response_order_by!], got %s", q)
+			}
+
+			if tt.wantIDs != nil {
+				var ids []int
+				for _, r := range results {
+					ids = append(ids, r.ID)
+				}
+				if len(ids) != len(tt.wantIDs) || ids[0] != tt.wantIDs[0] || ids[1] != tt.wantIDs[1] {
+					t.Errorf("matched IDs = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchResponses_InvalidMode(t *testing.T) {
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: "http://127.0.0.1:1", APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	for _, req := range []*types.ResponseSearchRequest{
+		{Query: "(", SearchMode: types.SearchModeRegex},
+		{Query: "x", SearchMode: "glob"},
+	} {
+		if _, err := client.SearchResponses(context.Background(), req); !errors.Is(err, mythic.ErrInvalidInput) {
+			t.Errorf("SearchResponses(%q, %q) error = %v, want ErrInvalidInput", req.Query, req.SearchMode, err)
+		}
+	}
+}
+
+func TestGetCallbackResponseStatistics(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		queries = append(queries, body.Query)
+
+		count := func(n int) map[string]interface{} {
+			return map[string]interface{}{"aggregate": map[string]interface{}{"count": n}}
+		}
+		if strings.Contains(body.Query, "task_aggregate") {
+			// Per-command breakdown, keyed by the aliases for each command
+			if body.Variables["command_0"] != "ls" || body.Variables["command_1"] != "shell" {
+				t.Errorf("Unexpected command variables %v", body.Variables)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"c0_tasks": count(1), "c0_responses": count(1), "c0_errors": count(0),
+					"c1_tasks": count(2), "c1_responses": count(5), "c1_errors": count(1),
+				},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"callback": []map[string]interface{}{{"id": 5}},
+				"totals": map[string]interface{}{
+					"aggregate": map[string]interface{}{
+						"count": 6,
+						"min":   map[string]interface{}{"timestamp": "2024-01-01T10:00:00.000000"},
+						"max":   map[string]interface{}{"timestamp": "2024-01-01T12:30:00.000000"},
+					},
+				},
+				"error_totals": count(1),
+				"commands": []map[string]interface{}{
+					{"command_name": "ls"},
+					{"command_name": "shell"},
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	stats, err := client.GetCallbackResponseStatistics(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("GetCallbackResponseStatistics() failed: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 queries, got %d", len(queries))
+	}
+	for _, q := range queries {
+		if strings.Contains(q, "response_text") {
+			t.Errorf("Statistics query should not fetch response text: %s", q)
+		}
+	}
+	if !strings.Contains(queries[0], "distinct_on: command_name") {
+		t.Errorf("Expected one row per command, got %s", queries[0])
+	}
+	if stats.ResponseCount != 6 || stats.ErrorCount != 1 {
+		t.Errorf("ResponseCount, ErrorCount = %d, %d, want 6, 1", stats.ResponseCount, stats.ErrorCount)
+	}
+	if got := stats.LatestResponse.Sub(stats.FirstResponse); got.Minutes() != 150 {
+		t.Errorf("Latest - First = %s, want 2h30m", got)
+	}
+	shell := stats.ByCommand["shell"]
+	if shell == nil || shell.TaskCount != 2 || shell.ResponseCount != 5 || shell.ErrorCount != 1 {
+		t.Errorf("ByCommand[shell] = %+v, want 2 tasks, 5 responses, 1 error", shell)
+	}
+	if ls := stats.ByCommand["ls"]; ls == nil || ls.ResponseCount != 1 {
+		t.Errorf("ByCommand[ls] = %+v, want 1 response", ls)
+	}
+
+	if _, err := client.GetCallbackResponseStatistics(context.Background(), 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetCallbackResponseStatistics(0) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestGetResponsesByIDs(t *testing.T) {
+	var chunkSizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables struct {
+				ResponseIDs []int `json:"response_ids"`
+			} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		chunkSizes = append(chunkSizes, len(body.Variables.ResponseIDs))
+
+		// Return only even IDs, in reverse order
+		rows := []map[string]interface{}{}
+		for i := len(body.Variables.ResponseIDs) - 1; i >= 0; i-- {
+			if id := body.Variables.ResponseIDs[i]; id%2 == 0 {
+				rows = append(rows, map[string]interface{}{
+					"id": id, "response_text": "out", "task_id": 1,
+					"task": map[string]interface{}{"id": 1, "command_name": "shell", "status": "completed", "callback_id": 1},
+				})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"response": rows}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	ids := make([]int, 0, 1200)
+	for id := 1200; id >= 1; id-- {
+		ids = append(ids, id)
+	}
+
+	responses, err := client.GetResponsesByIDs(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetResponsesByIDs() failed: %v", err)
+	}
+
+	if len(chunkSizes) != 3 {
+		t.Errorf("Expected 3 chunked queries, got %d (%v)", len(chunkSizes), chunkSizes)
+	}
+	for _, size := range chunkSizes {
+		if size > 500 {
+			t.Errorf("Chunk of %d IDs exceeds 500", size)
+		}
+	}
+
+	if len(responses) != 600 {
+		t.Fatalf("Expected 600 existing responses, got %d", len(responses))
+	}
+	for i, resp := range responses {
+		if want := 1200 - 2*i; resp.ID != want {
+			t.Fatalf("responses[%d].ID = %d, want %d (requested order)", i, resp.ID, want)
+		}
+	}
+
+	if _, err := client.GetResponsesByIDs(context.Background(), nil); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetResponsesByIDs(nil) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+// TestResponseQueries_ReportIsError tests that the task, callback and latest
+// response queries all select is_error.
+func TestResponseQueries_ReportIsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !strings.Contains(req.Query, "is_error") {
+			t.Errorf("Expected is_error to be selected, got %s", req.Query)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"response": []map[string]interface{}{{
+				"id": 1, "response_text": "access denied", "task_id": 42, "is_error": true,
+				"task": map[string]interface{}{"id": 42, "command_name": "shell", "status": "error", "callback_id": 3},
+			}},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	queries := map[string]func() ([]*types.Response, error){
+		"GetResponsesByTask":     func() ([]*types.Response, error) { return client.GetResponsesByTask(ctx, 42) },
+		"GetResponsesByCallback": func() ([]*types.Response, error) { return client.GetResponsesByCallback(ctx, 3, 10) },
+		"GetLatestResponses":     func() ([]*types.Response, error) { return client.GetLatestResponses(ctx, 1, 10) },
+	}
+	for name, query := range queries {
+		responses, err := query()
+		if err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		if len(responses) != 1 || !responses[0].IsError || responses[0].TaskCommand != "shell" {
+			t.Errorf("%s() = %+v, want one error response from shell", name, responses)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
+)
+
+// screenshotRow is the filemeta row of the screenshot served by newFileClient.
+var screenshotRow = map[string]interface{}{"id": 1, "agent_file_id": "shot-1", "is_screenshot": true}
+
+func encodeTestPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestGetScreenshotThumbnail(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   [2]int
+		opts  *mythic.ThumbnailOptions
+		wantW int
+		wantH int
+	}{
+		{"default bounds", [2]int{1024, 512}, nil, 256, 128},
+		{"width bound", [2]int{1000, 500}, &mythic.ThumbnailOptions{MaxWidth: 100}, 100, 50},
+		{"height bound", [2]int{400, 800}, &mythic.ThumbnailOptions{MaxWidth: 400, MaxHeight: 200}, 100, 200},
+		{"never upscaled", [2]int{40, 30}, nil, 40, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFileClient(t, screenshotRow, encodeTestPNG(t, tt.src[0], tt.src[1]))
+
+			thumb, err := client.GetScreenshotThumbnail(context.Background(), "shot-1", tt.opts)
+			if err != nil {
+				t.Fatalf("GetScreenshotThumbnail() failed: %v", err)
+			}
+
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(thumb))
+			if err != nil {
+				t.Fatalf("Thumbnail is not a valid image: %v", err)
+			}
+			if format != "png" {
+				t.Errorf("Expected png thumbnail, got %s", format)
+			}
+			if cfg.Width != tt.wantW || cfg.Height != tt.wantH {
+				t.Errorf("Expected %dx%d, got %dx%d", tt.wantW, tt.wantH, cfg.Width, cfg.Height)
+			}
+		})
+	}
+}
+
+func TestGetScreenshotThumbnail_JPEG(t *testing.T) {
+	var src bytes.Buffer
+	if err := jpeg.Encode(&src, image.NewRGBA(image.Rect(0, 0, 800, 600)), nil); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+
+	thumb, err := newFileClient(t, screenshotRow, src.Bytes()).GetScreenshotThumbnail(context.Background(), "shot-1", nil)
+	if err != nil {
+		t.Fatalf("GetScreenshotThumbnail() failed: %v", err)
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(thumb))
+	if err != nil {
+		t.Fatalf("Thumbnail is not a valid image: %v", err)
+	}
+	if format != "png" || cfg.Width != 256 || cfg.Height != 192 {
+		t.Errorf("Expected 256x192 png, got %dx%d %s", cfg.Width, cfg.Height, format)
+	}
+}
+
+func TestGetScreenshotThumbnail_Fallbacks(t *testing.T) {
+	// Unrecognized formats are returned unchanged
+	raw := []byte("BM not an image format we decode")
+	thumb, err := newFileClient(t, screenshotRow, raw).GetScreenshotThumbnail(context.Background(), "shot-1", nil)
+	if err != nil {
+		t.Fatalf("GetScreenshotThumbnail() failed: %v", err)
+	}
+	if !bytes.Equal(thumb, raw) {
+		t.Errorf("Expected original data for unrecognized format, got %q", thumb)
+	}
+
+	// A corrupt PNG is an error rather than a silent fallback
+	corrupt := encodeTestPNG(t, 64, 64)[:60]
+	if _, err := newFileClient(t, screenshotRow, corrupt).GetScreenshotThumbnail(context.Background(), "shot-1", nil); err == nil {
+		t.Error("Expected error for corrupt PNG")
+	}
+
+	_, err = newFileClient(t, screenshotRow, raw).GetScreenshotThumbnail(context.Background(), "shot-1", &mythic.ThumbnailOptions{MaxWidth: -1})
+	if !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for negative bounds, got %v", err)
+	}
+}
+
+func TestDownloadScreenshots(t *testing.T) {
+	var graphqlCalls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/graphql/":
+			atomic.AddInt32(&graphqlCalls, 1)
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"filemeta": []map[string]interface{}{
+					{"id": 1, "agent_file_id": "shot-1", "is_screenshot": true, "complete": true},
+					{"id": 2, "agent_file_id": "shot-2", "is_screenshot": true, "complete": true},
+					{"id": 3, "agent_file_id": "shot-3", "is_screenshot": true, "complete": false},
+					{"id": 4, "agent_file_id": "shot-4", "is_screenshot": true, "complete": true},
+				},
+			}})
+		case r.URL.Path == "/api/v1.4/files/download/shot-4":
+			http.Error(w, "gone", http.StatusNotFound)
+		case strings.HasPrefix(r.URL.Path, "/api/v1.4/files/download/"):
+			w.Write([]byte("image-" + strings.TrimPrefix(r.URL.Path, "/api/v1.4/files/download/")))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	images, err := client.DownloadScreenshots(context.Background(), 5, 10)
+	if err == nil || !strings.Contains(err.Error(), "shot-4") {
+		t.Errorf("Expected aggregated error naming shot-4, got %v", err)
+	}
+
+	if len(images) != 2 {
+		t.Fatalf("Expected 2 images, got %d", len(images))
+	}
+	for _, id := range []string{"shot-1", "shot-2"} {
+		if string(images[id]) != "image-"+id {
+			t.Errorf("Expected %s to be downloaded, got %q", id, images[id])
+		}
+	}
+	if _, ok := images["shot-3"]; ok {
+		t.Error("Expected incomplete screenshot to be skipped")
+	}
+	if got := atomic.LoadInt32(&graphqlCalls); got != 1 {
+		t.Errorf("Expected a single metadata query, got %d", got)
+	}
+}
+
+func TestGetScreenshots_FallsBackOnValidationError(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		queries = append(queries, req.Query)
+
+		if strings.Contains(req.Query, "task: {callback_id") {
+			w.Write([]byte(`{"errors":[{"message":"field 'task' not found in type: 'filemeta_bool_exp'","extensions":{"path":"$.selectionSet.filemeta.args.where.task","code":"validation-failed"}}]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"filemeta": []map[string]interface{}{
+				{"id": 1, "agent_file_id": "shot-1", "is_screenshot": true, "task": map[string]interface{}{"callback_id": 5}},
+				{"id": 2, "agent_file_id": "shot-2", "is_screenshot": true, "task": map[string]interface{}{"callback_id": 6}},
+				{"id": 3, "agent_file_id": "shot-3", "is_screenshot": true, "task": nil},
+				{"id": 4, "agent_file_id": "shot-4", "is_screenshot": true, "task": map[string]interface{}{"callback_id": 5}},
+				{"id": 5, "agent_file_id": "shot-5", "is_screenshot": true, "task": map[string]interface{}{"callback_id": 5}},
+			},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	screenshots, err := client.GetScreenshots(context.Background(), 5, 2)
+	if err != nil {
+		t.Fatalf("GetScreenshots() failed: %v", err)
+	}
+
+	if len(queries) != 2 || !strings.Contains(queries[0], "limit: $limit") {
+		t.Fatalf("Expected a limited query followed by a fallback, got %q", queries)
+	}
+	if len(screenshots) != 2 || screenshots[0].AgentFileID != "shot-1" || screenshots[1].AgentFileID != "shot-4" {
+		t.Errorf("Expected shot-1 and shot-4 from callback 5, got %+v", screenshots)
+	}
+}
@@ -1,9 +1,13 @@
 package unit
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -159,6 +163,27 @@ func TestSubscriptionConfig_Validate(t *testing.T) {
 			shouldErr: true,
 			errMsg:    "buffer size cannot be negative",
 		},
+		{
+			name: "negative reconnect backoff",
+			config: types.SubscriptionConfig{
+				Type:             types.SubscriptionTypeCallback,
+				Handler:          func(e *types.SubscriptionEvent) error { return nil },
+				AutoReconnect:    true,
+				ReconnectBackoff: -time.Second,
+			},
+			shouldErr: true,
+			errMsg:    "reconnect backoff cannot be negative",
+		},
+		{
+			name: "auto reconnect with backoff (valid)",
+			config: types.SubscriptionConfig{
+				Type:             types.SubscriptionTypeCallback,
+				Handler:          func(e *types.SubscriptionEvent) error { return nil },
+				AutoReconnect:    true,
+				ReconnectBackoff: 2 * time.Second,
+			},
+			shouldErr: false,
+		},
 		{
 			name: "zero buffer size (valid)",
 			config: types.SubscriptionConfig{
@@ -269,6 +294,7 @@ func TestSubscriptionType_Constants(t *testing.T) {
 		{"task output", types.SubscriptionTypeTaskOutput, "task_output"},
 		{"callback", types.SubscriptionTypeCallback, "callback"},
 		{"file", types.SubscriptionTypeFile, "file"},
+		{"new task", types.SubscriptionTypeNewTask, "new_task"},
 		{"all", types.SubscriptionTypeAll, "all"},
 	}
 
@@ -280,3 +306,42 @@ func TestSubscriptionType_Constants(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscribe_UnsupportedFilterKey(t *testing.T) {
+	srv := newAuthServer(t, "operator1", "pass123")
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		name    string
+		subType types.SubscriptionType
+		filter  map[string]interface{}
+	}{
+		{"unknown key", types.SubscriptionTypeTaskOutput, map[string]interface{}{"host": "DC01"}},
+		{"task_id on callbacks", types.SubscriptionTypeCallback, map[string]interface{}{"task_id": 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Subscribe(context.Background(), &types.SubscriptionConfig{
+				Type:        tt.subType,
+				Handler:     func(*types.SubscriptionEvent) error { return nil },
+				Filter:      tt.filter,
+				OperationID: 1,
+			})
+			if !errors.Is(err, mythic.ErrInvalidInput) {
+				t.Errorf("Expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
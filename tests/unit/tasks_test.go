@@ -1,10 +1,21 @@
 package unit
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
 func TestTask_String(t *testing.T) {
@@ -212,6 +223,7 @@ func TestTaskResponse_Structure(t *testing.T) {
 func TestTask_CompleteStructure(t *testing.T) {
 	now := time.Now()
 	parentID := 5
+	tokenID := 7
 	interactiveType := 1
 	opsecBlocked := true
 
@@ -231,6 +243,7 @@ func TestTask_CompleteStructure(t *testing.T) {
 		OperatorID:                1,
 		OperationID:               1,
 		ParentTaskID:              &parentID,
+		TokenID:                   &tokenID,
 		ResponseCount:             3,
 		IsInteractiveTask:         true,
 		InteractiveTaskType:       &interactiveType,
@@ -265,6 +278,9 @@ func TestTask_CompleteStructure(t *testing.T) {
 	if task.ParentTaskID == nil || *task.ParentTaskID != 5 {
 		t.Errorf("Expected ParentTaskID 5, got %v", task.ParentTaskID)
 	}
+	if task.TokenID == nil || *task.TokenID != 7 {
+		t.Errorf("Expected TokenID 7, got %v", task.TokenID)
+	}
 	if task.InteractiveTaskType == nil || *task.InteractiveTaskType != 1 {
 		t.Errorf("Expected InteractiveTaskType 1, got %v", task.InteractiveTaskType)
 	}
@@ -441,3 +457,875 @@ func TestTaskArtifact_Structure(t *testing.T) {
 		t.Errorf("Expected Timestamp %v, got %v", now, artifact.Timestamp)
 	}
 }
+
+func TestTaskNode_EmbedsTask(t *testing.T) {
+	parentID := 100
+	root := &mythic.TaskNode{
+		Task: &mythic.Task{ID: 100, DisplayID: 1, CommandName: "execute_assembly"},
+		Children: []*mythic.TaskNode{
+			{Task: &mythic.Task{ID: 101, DisplayID: 2, CommandName: "inject", ParentTaskID: &parentID}},
+		},
+	}
+
+	// Task fields are promoted through the embedded *Task
+	if root.DisplayID != 1 {
+		t.Errorf("Expected DisplayID 1, got %d", root.DisplayID)
+	}
+	if root.CommandName != "execute_assembly" {
+		t.Errorf("Expected CommandName 'execute_assembly', got %q", root.CommandName)
+	}
+	if len(root.Children) != 1 {
+		t.Fatalf("Expected 1 child, got %d", len(root.Children))
+	}
+
+	child := root.Children[0]
+	if child.ParentTaskID == nil || *child.ParentTaskID != root.ID {
+		t.Errorf("Expected child ParentTaskID %d, got %v", root.ID, child.ParentTaskID)
+	}
+}
+
+func TestTaskParamsBuilder_Build(t *testing.T) {
+	params, err := mythic.NewTaskParamsBuilder(nil).
+		SetString("path", "C:\\Temp").
+		SetBool("recurse", true).
+		SetArray("args", []string{"-a", "-b"}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() returned unexpected error: %v", err)
+	}
+
+	expected := `{"args":["-a","-b"],"path":"C:\\Temp","recurse":true}`
+	if params != expected {
+		t.Errorf("Expected %s, got %s", expected, params)
+	}
+}
+
+func TestTaskParamsBuilder_Validation(t *testing.T) {
+	command := &mythic.CommandWithParameters{
+		Command: &types.Command{Cmd: "upload"},
+		Parameters: []*types.CommandParameter{
+			{Name: "file", Type: "File", Required: true},
+			{Name: "remote_path", Type: "String", Required: true},
+			{Name: "overwrite", Type: "Boolean", Required: true, DefaultValue: "false"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		build   func(b *mythic.TaskParamsBuilder)
+		wantErr bool
+	}{
+		{
+			name: "all required present",
+			build: func(b *mythic.TaskParamsBuilder) {
+				b.SetFile("file", "abc-123").SetString("remote_path", "/tmp/x")
+			},
+		},
+		{
+			name: "missing required parameter",
+			build: func(b *mythic.TaskParamsBuilder) {
+				b.SetFile("file", "abc-123")
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown parameter",
+			build: func(b *mythic.TaskParamsBuilder) {
+				b.SetFile("file", "abc-123").SetString("remote_path", "/tmp/x").SetString("bogus", "1")
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong type",
+			build: func(b *mythic.TaskParamsBuilder) {
+				b.SetFile("file", "abc-123").SetString("remote_path", "/tmp/x").SetString("overwrite", "yes")
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := mythic.NewTaskParamsBuilder(command)
+			tt.build(b)
+			_, err := b.Build()
+			if tt.wantErr {
+				if !errors.Is(err, mythic.ErrInvalidInput) {
+					t.Errorf("Expected ErrInvalidInput, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Build() returned unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTaskParamsBuilder_SetCoercesTypes(t *testing.T) {
+	command := &mythic.CommandWithParameters{
+		Command: &types.Command{Cmd: "download"},
+		Parameters: []*types.CommandParameter{
+			{Name: "path", Type: "String", Required: true},
+			{Name: "chunk_size", Type: "Number", Required: true},
+			{Name: "recurse", Type: "Boolean"},
+			{Name: "exclude", Type: "Array"},
+		},
+	}
+
+	params, err := mythic.NewTaskParamsBuilder(command).
+		Set("path", "/etc").
+		Set("chunk_size", 512).
+		Set("recurse", "true").
+		Set("exclude", []interface{}{"*.log"}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() returned unexpected error: %v", err)
+	}
+
+	expected := `{"chunk_size":512,"exclude":["*.log"],"path":"/etc","recurse":true}`
+	if params != expected {
+		t.Errorf("Expected %s, got %s", expected, params)
+	}
+
+	_, err = mythic.NewTaskParamsBuilder(command).Set("chunk_size", "big").Set("path", "/etc").Build()
+	if !errors.Is(err, mythic.ErrInvalidInput) || !strings.Contains(err.Error(), "chunk_size") {
+		t.Errorf("Expected type error for chunk_size, got %v", err)
+	}
+
+	// Every missing required parameter is reported at once
+	_, err = mythic.NewTaskParamsBuilder(command).Set("recurse", false).Build()
+	if !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Fatalf("Expected ErrInvalidInput, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "missing required parameters: path, chunk_size") {
+		t.Errorf("Expected both missing parameters to be listed, got %v", err)
+	}
+}
+
+func TestDefaultPollConfig(t *testing.T) {
+	cfg := mythic.DefaultPollConfig()
+
+	if cfg.InitialInterval <= 0 || cfg.InitialInterval > cfg.MaxInterval {
+		t.Errorf("InitialInterval %v should be positive and at most MaxInterval %v", cfg.InitialInterval, cfg.MaxInterval)
+	}
+	if cfg.Multiplier < 1 {
+		t.Errorf("Multiplier %v should be at least 1", cfg.Multiplier)
+	}
+	if cfg.Timeout != 5*time.Minute {
+		t.Errorf("Timeout = %v, expected 5m", cfg.Timeout)
+	}
+	if cfg.AutoBypassOpsec {
+		t.Error("AutoBypassOpsec should default to false")
+	}
+}
+
+func TestWaitForTaskCompleteWithConfig_Validation(t *testing.T) {
+	srv := newAuthServer(t, "operator1", "pass123")
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		name string
+		cfg  *mythic.PollConfig
+	}{
+		{"negative initial interval", &mythic.PollConfig{InitialInterval: -time.Second}},
+		{"negative max interval", &mythic.PollConfig{MaxInterval: -time.Second}},
+		{"negative timeout", &mythic.PollConfig{Timeout: -time.Second}},
+		{"multiplier below one", &mythic.PollConfig{Multiplier: 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.WaitForTaskCompleteWithConfig(context.Background(), 1, tt.cfg)
+			if !errors.Is(err, mythic.ErrInvalidInput) {
+				t.Errorf("Expected ErrInvalidInput, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIssueTaskBulk_PerCallbackResults(t *testing.T) {
+	var inFlight, maxInFlight int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "fake-access-token",
+				"refresh_token": "fake-refresh-token",
+				"user":          map[string]interface{}{"id": 1, "username": "operator1", "current_operation_id": 1},
+			})
+		case "/api/v1.4/create_task_webhook":
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				cur := atomic.LoadInt32(&maxInFlight)
+				if n <= cur || atomic.CompareAndSwapInt32(&maxInFlight, cur, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			var body struct {
+				Input struct {
+					CallbackID int `json:"callback_id"`
+				} `json:"input"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.Input.CallbackID == 3 {
+				json.NewEncoder(w).Encode(map[string]interface{}{"status": "error", "error": "callback not found"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "id": body.Input.CallbackID, "display_id": body.Input.CallbackID})
+		case "/graphql/":
+			var gql struct {
+				Variables struct {
+					DisplayID int `json:"display_id"`
+				} `json:"variables"`
+			}
+			json.NewDecoder(r.Body).Decode(&gql)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"task": []map[string]interface{}{{"id": gql.Variables.DisplayID, "display_id": gql.Variables.DisplayID, "command_name": "shell"}},
+				},
+			})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	callbackIDs := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	tasks, errs, err := client.IssueTaskBulk(context.Background(), &mythic.TaskRequest{
+		Command:     "shell",
+		Params:      "whoami",
+		CallbackIDs: callbackIDs,
+	})
+	if err != nil {
+		t.Fatalf("IssueTaskBulk() failed: %v", err)
+	}
+
+	if len(tasks) != len(callbackIDs) || len(errs) != len(callbackIDs) {
+		t.Fatalf("Expected %d results, got %d tasks and %d errors", len(callbackIDs), len(tasks), len(errs))
+	}
+	for i, callbackID := range callbackIDs {
+		if callbackID == 3 {
+			if errs[i] == nil || tasks[i] != nil {
+				t.Errorf("Callback 3 should have failed, got task %v err %v", tasks[i], errs[i])
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("Callback %d failed: %v", callbackID, errs[i])
+		} else if tasks[i].DisplayID != callbackID {
+			t.Errorf("Result %d should belong to callback %d, got task %d", i, callbackID, tasks[i].DisplayID)
+		}
+	}
+
+	failed := mythic.BulkTaskErrors(callbackIDs, errs)
+	if len(failed) != 1 || failed[3] == nil {
+		t.Errorf("Expected only callback 3 in BulkTaskErrors, got %v", failed)
+	}
+
+	if peak := atomic.LoadInt32(&maxInFlight); peak < 2 || peak > 8 {
+		t.Errorf("Expected between 2 and 8 concurrent requests, got %d", peak)
+	}
+}
+
+func TestIssueTask_RetriesUntilTaskVisible(t *testing.T) {
+	var lookups int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "fake-access-token",
+				"refresh_token": "fake-refresh-token",
+				"user":          map[string]interface{}{"id": 1, "username": "operator1", "current_operation_id": 1},
+			})
+		case "/api/v1.4/create_task_webhook":
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "id": 42, "display_id": 7})
+		case "/graphql/":
+			// The new task only becomes visible on the third lookup
+			rows := []map[string]interface{}{}
+			if atomic.AddInt32(&lookups, 1) >= 3 {
+				rows = append(rows, map[string]interface{}{"id": 42, "display_id": 7, "command_name": "shell"})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"task": rows}})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	newClient := func(attempts int) *mythic.Client {
+		client, err := mythic.NewClient(&mythic.Config{
+			ServerURL:          srv.URL,
+			Username:           "operator1",
+			Password:           "pass123",
+			SSL:                false,
+			TaskLookupAttempts: attempts,
+		})
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+
+	callbackID := 1
+	req := &mythic.TaskRequest{Command: "shell", Params: "whoami", CallbackID: &callbackID}
+
+	task, err := newClient(0).IssueTask(context.Background(), req)
+	if err != nil {
+		t.Fatalf("IssueTask() failed: %v", err)
+	}
+	if task.DisplayID != 7 {
+		t.Errorf("Expected task 7, got %d", task.DisplayID)
+	}
+	if got := atomic.LoadInt32(&lookups); got != 3 {
+		t.Errorf("Expected 3 lookups, got %d", got)
+	}
+
+	// With retries disabled the first miss is returned
+	atomic.StoreInt32(&lookups, 0)
+	_, err = newClient(1).IssueTask(context.Background(), req)
+	if !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound without retries, got %v", err)
+	}
+}
+
+func TestIssueTask_ParamsMap(t *testing.T) {
+	var sentParams atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "fake-access-token",
+				"refresh_token": "fake-refresh-token",
+				"user":          map[string]interface{}{"id": 1, "username": "operator1", "current_operation_id": 1},
+			})
+		case "/api/v1.4/create_task_webhook":
+			var body struct {
+				Input struct {
+					Params string `json:"params"`
+				} `json:"input"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			sentParams.Store(body.Input.Params)
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "id": 42, "display_id": 7})
+		case "/graphql/":
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"task": []map[string]interface{}{{"id": 42, "display_id": 7, "command_name": "ls"}},
+			}})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	callbackID := 1
+	_, err = client.IssueTask(context.Background(), &mythic.TaskRequest{
+		Command:    "ls",
+		ParamsMap:  map[string]interface{}{"path": "/tmp"},
+		CallbackID: &callbackID,
+	})
+	if err != nil {
+		t.Fatalf("IssueTask() failed: %v", err)
+	}
+	if got := sentParams.Load(); got != `{"path":"/tmp"}` {
+		t.Errorf("Expected params %q, got %q", `{"path":"/tmp"}`, got)
+	}
+
+	// Params and ParamsMap are mutually exclusive
+	_, err = client.IssueTask(context.Background(), &mythic.TaskRequest{
+		Command:    "ls",
+		Params:     `{"path": "/tmp"}`,
+		ParamsMap:  map[string]interface{}{"path": "/tmp"},
+		CallbackID: &callbackID,
+	})
+	if !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput when both are set, got %v", err)
+	}
+}
+
+func TestGetTaskOutput_DecodesResponseRaw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "fake-access-token",
+				"refresh_token": "fake-refresh-token",
+				"user":          map[string]interface{}{"id": 1, "username": "operator1", "current_operation_id": 1},
+			})
+		case "/graphql/":
+			body, _ := io.ReadAll(r.Body)
+			if strings.Contains(string(body), "response(where") {
+				json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+					"response": []map[string]interface{}{
+						{"id": 1, "task_id": 42, "response_text": "binary", "response_raw": base64.StdEncoding.EncodeToString([]byte{0x00, 0xff, 'o', 'k'})},
+						{"id": 2, "task_id": 42, "response_text": "not base64", "response_raw": "%%%"},
+						{"id": 3, "task_id": 42, "response_text": "", "response_raw": ""},
+					},
+				}})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"task": []map[string]interface{}{{"id": 42, "display_id": 7, "command_name": "download"}},
+			}})
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{
+		ServerURL: srv.URL,
+		Username:  "operator1",
+		Password:  "pass123",
+		SSL:       false,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer client.Close()
+
+	responses, err := client.GetTaskOutput(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetTaskOutput() failed: %v", err)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("Expected 3 responses, got %d", len(responses))
+	}
+	if !bytes.Equal(responses[0].ResponseRaw, []byte{0x00, 0xff, 'o', 'k'}) {
+		t.Errorf("Expected decoded raw bytes, got %v", responses[0].ResponseRaw)
+	}
+	if responses[0].ResponseText != "binary" {
+		t.Errorf("Expected ResponseText 'binary', got %q", responses[0].ResponseText)
+	}
+	// Undecodable raw output is dropped without failing the call
+	if responses[1].ResponseRaw != nil {
+		t.Errorf("Expected nil ResponseRaw for invalid base64, got %v", responses[1].ResponseRaw)
+	}
+	if responses[2].ResponseRaw != nil {
+		t.Errorf("Expected nil ResponseRaw for an empty response, got %v", responses[2].ResponseRaw)
+	}
+}
+
+func TestWaitForTaskResult_ReturnsOutputOnFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if strings.Contains(req.Query, "response(") {
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"response": []map[string]interface{}{{"id": 1, "task_id": 42, "response_text": "access denied", "is_error": true}},
+			}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"task": []map[string]interface{}{{"id": 42, "display_id": 7, "status": "error", "stderr": "access denied"}},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	task, responses, err := client.WaitForTaskResult(context.Background(), 7, 5)
+	if !errors.Is(err, mythic.ErrTaskFailed) {
+		t.Fatalf("Expected ErrTaskFailed, got %v", err)
+	}
+	if task == nil || task.Status != "error" {
+		t.Fatalf("Expected final task with error status, got %+v", task)
+	}
+	if len(responses) != 1 || responses[0].ResponseText != "access denied" {
+		t.Errorf("Expected failure output to be returned, got %+v", responses)
+	}
+
+	responses, err = client.WaitForTaskOutput(context.Background(), 7, 5)
+	if !errors.Is(err, mythic.ErrTaskFailed) || len(responses) != 1 {
+		t.Errorf("WaitForTaskOutput() = %d responses, %v; want 1 response and ErrTaskFailed", len(responses), err)
+	}
+
+	// The failure is wrapped once where it was detected, not at every layer
+	if want := "WaitForTaskComplete: task 7 failed: access denied: task failed"; err.Error() != want {
+		t.Errorf("Error = %q, want %q", err, want)
+	}
+}
+
+func TestWaitForTaskCompleteSubscription_FallsBackToPolling(t *testing.T) {
+	var lookups int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql/" || r.Method != http.MethodPost {
+			// No websocket support, so the subscription cannot be used
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		var req struct {
+			Variables struct {
+				DisplayID int `json:"display_id"`
+			} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		// Task 7 completes on its second lookup; task 8 never does
+		completed := req.Variables.DisplayID == 7 && atomic.AddInt32(&lookups, 1) >= 2
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"task": []map[string]interface{}{{"id": 42, "display_id": 7, "status": "processing", "completed": completed}},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if err := client.WaitForTaskCompleteSubscription(context.Background(), 7, 10); err != nil {
+		t.Fatalf("WaitForTaskCompleteSubscription() error = %v", err)
+	}
+	if atomic.LoadInt32(&lookups) < 2 {
+		t.Errorf("Expected a polling fallback lookup, got %d lookups", lookups)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := client.WaitForTaskCompleteSubscription(ctx, 8, 10); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestWaitForTaskCompleteWithWaitOptions(t *testing.T) {
+	var lookups int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		completed := atomic.AddInt32(&lookups, 1) >= 3
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"task": []map[string]interface{}{{"id": 42, "display_id": 7, "status": "processing", "completed": completed}},
+		}})
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// The default backoff would take over a second to reach the third lookup
+	start := time.Now()
+	if err := client.WaitForTaskCompleteWithWaitOptions(context.Background(), 7, 10, &mythic.WaitOptions{PollInterval: 20 * time.Millisecond}); err != nil {
+		t.Fatalf("WaitForTaskCompleteWithWaitOptions() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected polling every 20ms, waiting took %v", elapsed)
+	}
+	if got := atomic.LoadInt32(&lookups); got != 3 {
+		t.Errorf("Expected 3 lookups, got %d", got)
+	}
+
+	err = client.WaitForTaskCompleteWithWaitOptions(context.Background(), 7, 10, &mythic.WaitOptions{PollInterval: -time.Second})
+	if !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for a negative poll interval, got %v", err)
+	}
+}
+
+func TestTaskInternalIDLookups(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		queries = append(queries, req.Query)
+
+		switch {
+		case strings.Contains(req.Query, "update_task"):
+			affected := 0
+			if req.Variables["id"] == float64(42) {
+				affected = 1
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"update_task": map[string]interface{}{"affected_rows": affected}}})
+		case strings.Contains(req.Query, "response("):
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"response": []map[string]interface{}{{"id": 1, "task_id": req.Variables["task_id"], "response_text": "root"}},
+			}})
+		case req.Variables["display_id"] == float64(8):
+			// Display ID 8 resolves to internal ID 43, which the update misses
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+				"task": []map[string]interface{}{{"id": 43, "display_id": 8, "command_name": "shell"}},
+			}})
+		default:
+			rows := []map[string]interface{}{}
+			if req.Variables["id"] == float64(42) {
+				rows = append(rows, map[string]interface{}{"id": 42, "display_id": 7, "command_name": "shell"})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"task": rows}})
+		}
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	task, err := client.GetTaskByInternalID(ctx, 42)
+	if err != nil {
+		t.Fatalf("GetTaskByInternalID() error = %v", err)
+	}
+	if task.ID != 42 || task.DisplayID != 7 {
+		t.Errorf("Unexpected task %+v", task)
+	}
+	if _, err := client.GetTaskByInternalID(ctx, 43); !errors.Is(err, mythic.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	queries = nil
+	responses, err := client.GetTaskOutputByInternalID(ctx, 42)
+	if err != nil {
+		t.Fatalf("GetTaskOutputByInternalID() error = %v", err)
+	}
+	if len(responses) != 1 || responses[0].TaskID != 42 {
+		t.Errorf("Unexpected responses %+v", responses)
+	}
+	if len(queries) != 1 {
+		t.Errorf("Expected a single query without a task lookup, got %d", len(queries))
+	}
+
+	queries = nil
+	if err := client.UpdateTaskByInternalID(ctx, 42, map[string]interface{}{"comment": "checked"}); err != nil {
+		t.Fatalf("UpdateTaskByInternalID() error = %v", err)
+	}
+	if len(queries) != 1 {
+		t.Errorf("Expected a single mutation without a task lookup, got %d", len(queries))
+	}
+
+	// Not-found errors name the identifier the caller passed
+	err = client.UpdateTaskByInternalID(ctx, 43, map[string]interface{}{"comment": "x"})
+	if !errors.Is(err, mythic.ErrNotFound) || !strings.Contains(err.Error(), "task with id 43 not found") {
+		t.Errorf("UpdateTaskByInternalID(43) error = %v, want not found by id", err)
+	}
+	err = client.UpdateTask(ctx, 8, map[string]interface{}{"comment": "x"})
+	if !errors.Is(err, mythic.ErrNotFound) || !strings.Contains(err.Error(), "task with display_id 8 not found") {
+		t.Errorf("UpdateTask(8) error = %v, want not found by display_id", err)
+	}
+
+	for name, err := range map[string]error{
+		"GetTaskByInternalID":       func() error { _, err := client.GetTaskByInternalID(ctx, 0); return err }(),
+		"GetTaskOutputByInternalID": func() error { _, err := client.GetTaskOutputByInternalID(ctx, -1); return err }(),
+		"UpdateTaskByInternalID":    client.UpdateTaskByInternalID(ctx, 0, map[string]interface{}{"comment": "x"}),
+	} {
+		if !errors.Is(err, mythic.ErrInvalidInput) {
+			t.Errorf("%s() error = %v, want ErrInvalidInput", name, err)
+		}
+	}
+}
+
+func TestGetTasksForCallbackFiltered_Options(t *testing.T) {
+	var taskVars map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		if strings.Contains(req.Query, "callback(") {
+			w.Write([]byte(`{"data":{"callback":[{"id":50,"display_id":5}]}}`))
+			return
+		}
+		taskVars = req.Variables
+		w.Write([]byte(`{"data":{"task":[{"id":9,"display_id":3,"command_name":"ls","status":"error"}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	tasks, err := client.GetTasksForCallbackFiltered(context.Background(), 5, &mythic.TaskQueryOptions{
+		CommandName:   "ls",
+		Status:        mythic.TaskStatusError,
+		CompletedOnly: true,
+		Limit:         20,
+		Offset:        40,
+		Ascending:     true,
+	})
+	if err != nil {
+		t.Fatalf("GetTasksForCallbackFiltered() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].CommandName != "ls" {
+		t.Fatalf("Unexpected tasks %+v", tasks)
+	}
+
+	where, _ := taskVars["where"].(map[string]interface{})
+	for column, want := range map[string]string{
+		"callback_id":  `{"_eq":50}`,
+		"command_name": `{"_eq":"ls"}`,
+		"status":       `{"_eq":"error"}`,
+		"completed":    `{"_eq":true}`,
+	} {
+		if got, _ := json.Marshal(where[column]); string(got) != want {
+			t.Errorf("Expected %s condition %s, got %s", column, want, got)
+		}
+	}
+	if got, _ := json.Marshal(taskVars["order_by"]); string(got) != `[{"id":"asc"}]` {
+		t.Errorf("Expected ascending order, got %s", got)
+	}
+	if taskVars["limit"] != float64(20) || taskVars["offset"] != float64(40) {
+		t.Errorf("Expected limit 20 offset 40, got %v %v", taskVars["limit"], taskVars["offset"])
+	}
+
+	if _, err := client.GetTasksForCallbackFiltered(context.Background(), 5, &mythic.TaskQueryOptions{Offset: -1}); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for negative offset, got %v", err)
+	}
+}
+
+func TestSearchTasks(t *testing.T) {
+	var gotVars map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		gotVars = req.Variables
+		w.Write([]byte(`{"data":{"task":[{"id":9,"display_id":3,"command_name":"upload","operator_id":2}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+
+	// Without a current operation there is nothing to scope the search to
+	if _, err := client.SearchTasks(ctx, &mythic.TaskSearchRequest{}); !errors.Is(err, mythic.ErrNotAuthenticated) {
+		t.Errorf("Expected ErrNotAuthenticated without a current operation, got %v", err)
+	}
+
+	client.SetCurrentOperation(4)
+	operatorID := 2
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tasks, err := client.SearchTasks(ctx, &mythic.TaskSearchRequest{
+		CommandName: "upload",
+		OperatorID:  &operatorID,
+		StartTime:   &start,
+	})
+	if err != nil {
+		t.Fatalf("SearchTasks() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].OperatorID != 2 {
+		t.Fatalf("Unexpected tasks %+v", tasks)
+	}
+
+	where, _ := gotVars["where"].(map[string]interface{})
+	for column, want := range map[string]string{
+		"operation_id": `{"_eq":4}`,
+		"command_name": `{"_eq":"upload"}`,
+		"operator_id":  `{"_eq":2}`,
+		"timestamp":    `{"_gte":"2026-01-02T03:04:05Z"}`,
+	} {
+		if got, _ := json.Marshal(where[column]); string(got) != want {
+			t.Errorf("Expected %s condition %s, got %s", column, want, got)
+		}
+	}
+	if got, _ := json.Marshal(gotVars["order_by"]); string(got) != `[{"timestamp":"desc"},{"id":"desc"}]` {
+		t.Errorf("Expected newest first by default, got %s", got)
+	}
+	if gotVars["limit"] != float64(100) || gotVars["offset"] != float64(0) {
+		t.Errorf("Expected default limit 100 and offset 0, got %v %v", gotVars["limit"], gotVars["offset"])
+	}
+
+	for name, req := range map[string]*mythic.TaskSearchRequest{
+		"nil request":     nil,
+		"negative limit":  {Limit: -1},
+		"negative offset": {Offset: -1},
+		"bad sort order":  {SortOrder: "sideways"},
+	} {
+		if _, err := client.SearchTasks(ctx, req); !errors.Is(err, mythic.ErrInvalidInput) {
+			t.Errorf("%s: expected ErrInvalidInput, got %v", name, err)
+		}
+	}
+}
+
+func TestGetSubtasksByDisplayID(t *testing.T) {
+	var subtaskVars map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		if strings.Contains(req.Query, "parent_task_id: {_eq") {
+			subtaskVars = req.Variables
+			w.Write([]byte(`{"data":{"task":[{"id":43,"display_id":8,"parent_task_id":42},{"id":44,"display_id":9,"parent_task_id":42}]}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"task":[{"id":42,"display_id":7}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	subtasks, err := client.GetSubtasksByDisplayID(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetSubtasksByDisplayID() error = %v", err)
+	}
+	if subtaskVars["parent_task_id"] != float64(42) {
+		t.Errorf("Expected subtasks of internal ID 42, got %v", subtaskVars["parent_task_id"])
+	}
+	if len(subtasks) != 2 || subtasks[0].DisplayID != 8 || subtasks[1].DisplayID != 9 {
+		t.Errorf("Unexpected subtasks %+v", subtasks)
+	}
+
+	if _, err := client.GetSubtasksByDisplayID(context.Background(), 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for display ID 0, got %v", err)
+	}
+}
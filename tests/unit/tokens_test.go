@@ -1,9 +1,16 @@
 package unit
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic"
 	"github.com/nbaertsch/mythic-sdk-go/pkg/mythic/types"
 )
 
@@ -413,3 +420,52 @@ func TestTokenWithoutOptionalFields(t *testing.T) {
 		t.Error("String() should not return empty string even without optional fields")
 	}
 }
+
+func TestGetTokensByCallback(t *testing.T) {
+	var gotQuery string
+	var gotVars map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		gotQuery, gotVars = req.Query, req.Variables
+		_, _ = w.Write([]byte(`{"data":{"token":[{"id":4,"token_id":"0x1a2b","user":"CORP\\admin",
+			"description":"stolen from lsass","task_id":12,"timestamp":"2026-01-02T03:04:05Z","deleted":false}]}}`))
+	}))
+	defer srv.Close()
+
+	client, err := mythic.NewClient(&mythic.Config{ServerURL: srv.URL, APIToken: "test-token", SSL: false})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	tokens, err := client.GetTokensByCallback(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetTokensByCallback() error = %v", err)
+	}
+
+	if gotVars["callback_id"] != float64(7) {
+		t.Errorf("Expected callback_id 7, got %v", gotVars["callback_id"])
+	}
+	if !strings.Contains(gotQuery, "deleted: {_eq: false}") {
+		t.Errorf("Expected deleted tokens to be excluded, got %s", gotQuery)
+	}
+
+	if len(tokens) != 1 {
+		t.Fatalf("Expected 1 token, got %d", len(tokens))
+	}
+	tok := tokens[0]
+	if tok.ID != 4 || tok.TokenID != "0x1a2b" || tok.User != `CORP\admin` || tok.Description != "stolen from lsass" {
+		t.Errorf("Unexpected token %+v", tok)
+	}
+	if tok.TaskID == nil || *tok.TaskID != 12 {
+		t.Errorf("Expected task ID 12, got %v", tok.TaskID)
+	}
+
+	if _, err := client.GetTokensByCallback(context.Background(), 0); !errors.Is(err, mythic.ErrInvalidInput) {
+		t.Errorf("GetTokensByCallback(0) error = %v, want ErrInvalidInput", err)
+	}
+}